@@ -0,0 +1,73 @@
+package bemailparts
+
+import (
+	_ "embed"
+	"strings"
+	"sync"
+)
+
+// academicDomainList lists a small hand-picked sample of the domains of universities outside the academic
+// suffixes, one per line, after comment lines starting with '#'.
+//
+//go:embed academic_domains.txt
+var academicDomainList string
+
+var (
+	academicDomainsOnce sync.Once
+	academicDomainSet   map[string]bool
+)
+
+// loadAcademicDomains parses academicDomainList on first use.
+func loadAcademicDomains() map[string]bool {
+	academicDomainsOnce.Do(func() {
+		academicDomainSet = map[string]bool{}
+		for _, line := range strings.Split(academicDomainList, "\n") {
+			if domain := strings.TrimSpace(line); domain != "" && !strings.HasPrefix(domain, "#") {
+				academicDomainSet[domain] = true
+			}
+		}
+	})
+	return academicDomainSet
+}
+
+// IsAcademic reports whether the domain of e belongs to a university, e.g. for education discounts: it is
+// under the "edu" TLD or an academic second-level domain of a country such as "edu.au" or "ac.uk", or it is,
+// or is a subdomain of, a university domain of the embedded list, such as "ethz.ch". The list is a small
+// hand-picked sample, so false does not mean the domain is not academic.
+//
+// Example:
+//
+//	e, err := New("john@cs.ox.ac.uk")
+//	if err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	fmt.Println(IsAcademic(e)) // Output: true
+func IsAcademic(e BEmailParts) bool {
+	return isAcademic(e.Domain())
+}
+
+// isAcademic reports whether domain is under an academic suffix, such as "edu" or "ac.uk", or is a
+// university domain of the embedded list or a subdomain of one.
+func isAcademic(domain string) bool {
+	if isAddressLiteral(domain) {
+		return false
+	}
+	labels := strings.Split(strings.ToLower(domain), domainSeparator)
+	n := len(labels)
+	switch {
+	case n >= 2 && labels[n-1] == "edu":
+		return true
+	case n >= 3 && (labels[n-2] == "edu" || labels[n-2] == "ac") && len(labels[n-1]) == 2:
+		// The academic second-level domains of country-code TLDs, such as "edu.au" and "ac.uk".
+		return true
+	}
+
+	academic := loadAcademicDomains()
+	for i := 0; i < n-1; i++ {
+		if academic[strings.Join(labels[i:], domainSeparator)] {
+			return true
+		}
+	}
+	return false
+}
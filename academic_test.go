@@ -0,0 +1,45 @@
+package bemailparts_test
+
+import (
+	"github.com/bearaujus/bemailparts"
+	"testing"
+)
+
+func TestIsAcademic(t *testing.T) {
+	tests := []struct {
+		name  string
+		email string
+		opts  []bemailparts.Option
+		want  bool
+	}{
+		{name: "edu", email: "john@mit.edu", want: true},
+		{name: "subdomain of edu", email: "john@cs.stanford.edu", want: true},
+		{name: "edu of a country", email: "john@unimelb.edu.au", want: true},
+		{name: "ac of a country", email: "john@cs.ox.ac.uk", want: true},
+		{name: "ac of another country", email: "john@ui.ac.id", want: true},
+		{name: "case is ignored", email: "john@U-Tokyo.AC.JP", want: true},
+		{name: "listed domain", email: "john@ethz.ch", want: true},
+		{name: "subdomain of a listed domain", email: "john@student.ethz.ch", want: true},
+		{name: "commercial", email: "john@example.com"},
+		{name: "ascension island", email: "john@example.ac"},
+		{name: "ac outside a country", email: "john@example.ac.example.com"},
+		{name: "listed domain as a subdomain", email: "john@ethz.ch.example.com"},
+		{name: "name of a listed domain", email: "john@ethz.de"},
+		{
+			name:  "address literal",
+			email: "john@[192.0.2.1]",
+			opts:  []bemailparts.Option{bemailparts.WithAllowIPDomain()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := bemailparts.New(tt.email, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := bemailparts.IsAcademic(e); got != tt.want {
+				t.Errorf("IsAcademic() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package bemailparts
+
+import (
+	"mime"
+	"strings"
+)
+
+// displayNameSpecials are the RFC 5322 specials that must be quoted in a display name. Parentheses and dots
+// are accepted unquoted, since mail clients commonly emit names such as "John Q. Doe (Sales)".
+const displayNameSpecials = `<>@,;:\[]`
+
+// ParseAddress creates a new instance of BEmailParts from an RFC 5322 mailbox as found in From and To
+// headers, keeping its display name.
+//
+// Parameters:
+//
+//	address: A mailbox such as "John Doe <john.doe@example.com>", "<john.doe@example.com>" or a bare
+//	  "john.doe@example.com". Quoted display names may contain specials, e.g. `"Doe, John" <john@example.com>`.
+//	opts: Optional Options controlling validation of the address, e.g. WithRFC5322().
+//
+// Returns:
+//   - A BEmailParts instance representing the parsed email, whose DisplayName returns the display name with
+//     RFC 2047 encoded words such as "=?UTF-8?B?Sm9zw6k=?=" decoded.
+//   - ErrInvalidDisplayName if the display name is malformed, or an error of New if the address is invalid.
+//
+// Example:
+//
+//	emailParts, err := ParseAddress("John Doe <john.doe@example.com>")
+//	if err != nil {
+//	    log.Fatalf("Invalid address: %v", err)
+//	}
+//
+//	fmt.Println(emailParts.DisplayName()) // Output: John Doe
+//	fmt.Println(emailParts.Email())       // Output: john.doe@example.com
+func ParseAddress(address string, opts ...Option) (BEmailParts, error) {
+	return parseAddress(address, newOptions(opts))
+}
+
+func parseAddress(address string, o *options) (BEmailParts, error) {
+	address = strings.TrimSpace(address)
+	if !strings.HasSuffix(address, ">") {
+		return newEmailParts(address, o)
+	}
+
+	lt := indexUnquoted(address, '<')
+	if lt < 0 {
+		return nil, newEmailError(FieldEmail, address, ErrInvalidEmailFormat)
+	}
+	displayName, err := parseDisplayName(address[:lt])
+	if err != nil {
+		return nil, newEmailError(FieldDisplayName, strings.TrimSpace(address[:lt]), err)
+	}
+
+	e, err := parseEmailParts(address[lt+1:len(address)-1], o)
+	if err != nil {
+		return nil, err
+	}
+	e.displayName = displayName
+	return &e, nil
+}
+
+// indexUnquoted returns the index of the first c in s outside quoted strings, or -1 if there is none.
+func indexUnquoted(s string, c byte) int {
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case quoted && s[i] == '\\':
+			i++
+		case s[i] == '"':
+			quoted = !quoted
+		case !quoted && s[i] == c:
+			return i
+		}
+	}
+	return -1
+}
+
+// parseDisplayName decodes an RFC 5322 phrase: quoted strings are unquoted, runs of unquoted whitespace,
+// including folding, collapse into a single space, and RFC 2047 encoded words are decoded.
+func parseDisplayName(phrase string) (string, error) {
+	var b strings.Builder
+	space := false
+	for i := 0; i < len(phrase); i++ {
+		c := phrase[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			space = true
+			continue
+		case c == '"':
+			end := i + 1
+			for ; end < len(phrase) && phrase[end] != '"'; end++ {
+				if phrase[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(phrase) {
+				return "", ErrInvalidDisplayName
+			}
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			for j := i + 1; j < end; j++ {
+				if phrase[j] == '\\' {
+					j++
+				}
+				b.WriteByte(phrase[j])
+			}
+			i, space = end, false
+			continue
+		case strings.IndexByte(displayNameSpecials, c) >= 0:
+			return "", ErrInvalidDisplayName
+		}
+		if space && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteByte(c)
+		space = false
+	}
+
+	displayName := decodeEncodedWords(b.String())
+	if !isValidDisplayName(displayName) {
+		return "", ErrInvalidDisplayName
+	}
+	return displayName, nil
+}
+
+// decodeEncodedWords decodes the RFC 2047 encoded words in s, such as "=?UTF-8?B?Sm9zw6k=?=". Encoded words
+// are decoded even inside quoted strings, as many mail clients write them there. If s uses a charset other
+// than UTF-8, ISO-8859-1 or US-ASCII, it is returned unchanged.
+func decodeEncodedWords(s string) string {
+	if !strings.Contains(s, "=?") {
+		return s
+	}
+	decoded, err := new(mime.WordDecoder).DecodeHeader(s)
+	if err != nil {
+		return s
+	}
+	return decoded
+}
+
+// isValidDisplayName reports whether s contains no control characters, so that it cannot inject header
+// lines when written back into a message.
+func isValidDisplayName(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; (c < ' ' && c != '\t') || c == 0x7f {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,152 @@
+package bemailparts_test
+
+import (
+	"errors"
+	"github.com/bearaujus/bemailparts"
+	"testing"
+)
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		name            string
+		address         string
+		opts            []bemailparts.Option
+		wantEmail       string
+		wantDisplayName string
+		wantErr         error
+	}{
+		{
+			name:            "success display name",
+			address:         "John Doe <john.doe@example.com>",
+			wantEmail:       "john.doe@example.com",
+			wantDisplayName: "John Doe",
+		},
+		{
+			name:            "success quoted display name with comma",
+			address:         `"Doe, John" <john.doe@example.com>`,
+			wantEmail:       "john.doe@example.com",
+			wantDisplayName: "Doe, John",
+		},
+		{
+			name:            "success quoted display name with escapes",
+			address:         `"John \"JD\" Doe" <john.doe@example.com>`,
+			wantEmail:       "john.doe@example.com",
+			wantDisplayName: `John "JD" Doe`,
+		},
+		{
+			name:            "success folded display name",
+			address:         "  John\r\n  Q. Doe   <john.doe@example.com> ",
+			wantEmail:       "john.doe@example.com",
+			wantDisplayName: "John Q. Doe",
+		},
+		{
+			name:            "success utf-8 display name",
+			address:         "José Müller <jose@example.com>",
+			wantEmail:       "jose@example.com",
+			wantDisplayName: "José Müller",
+		},
+		{
+			name:            "success rfc 2047 base64 display name",
+			address:         "=?UTF-8?B?Sm9zw6kgTcO8bGxlcg==?= <jose@example.com>",
+			wantEmail:       "jose@example.com",
+			wantDisplayName: "José Müller",
+		},
+		{
+			name:            "success rfc 2047 quoted-printable display name",
+			address:         "=?ISO-8859-1?Q?Andr=E9?= Pirard <pirard@example.com>",
+			wantEmail:       "pirard@example.com",
+			wantDisplayName: "André Pirard",
+		},
+		{
+			name:            "success adjacent rfc 2047 words",
+			address:         `"=?UTF-8?Q?Jos=C3=A9?= =?UTF-8?Q?_M=C3=BCller?=" <jose@example.com>`,
+			wantEmail:       "jose@example.com",
+			wantDisplayName: "José Müller",
+		},
+		{
+			name:            "success unknown rfc 2047 charset kept",
+			address:         "=?KOI8-R?B?8NLJ18XU?= <ivan@example.com>",
+			wantEmail:       "ivan@example.com",
+			wantDisplayName: "=?KOI8-R?B?8NLJ18XU?=",
+		},
+		{
+			name:    "error rfc 2047 word decoding to control characters",
+			address: "=?UTF-8?Q?John=0D=0ABcc:_x?= <john@example.com>",
+			wantErr: bemailparts.ErrInvalidDisplayName,
+		},
+		{
+			name:      "success angle address without display name",
+			address:   "<john.doe@example.com>",
+			wantEmail: "john.doe@example.com",
+		},
+		{
+			name:      "success bare address",
+			address:   "john.doe@example.com",
+			wantEmail: "john.doe@example.com",
+		},
+		{
+			name:            "success options apply to address",
+			address:         "John <John.Doe@Example.com>",
+			opts:            []bemailparts.Option{bemailparts.WithLowercase()},
+			wantEmail:       "john.doe@example.com",
+			wantDisplayName: "John",
+		},
+		{
+			name:    "error unquoted comma in display name",
+			address: "Doe, John <john.doe@example.com>",
+			wantErr: bemailparts.ErrInvalidDisplayName,
+		},
+		{
+			name:    "error unterminated quoted display name",
+			address: `"John Doe <john.doe@example.com>`,
+			wantErr: bemailparts.ErrInvalidEmailFormat,
+		},
+		{
+			name:    "error control character in display name",
+			address: "John\x00Doe <john.doe@example.com>",
+			wantErr: bemailparts.ErrInvalidDisplayName,
+		},
+		{
+			name:    "error invalid address",
+			address: "John Doe <john.doe@>",
+			wantErr: bemailparts.ErrInvalidEmailFormat,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bemailparts.ParseAddress(tt.address, tt.opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParseAddress() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got.Email() != tt.wantEmail {
+				t.Errorf("Email() got = %v, want %v", got.Email(), tt.wantEmail)
+			}
+			if got.DisplayName() != tt.wantDisplayName {
+				t.Errorf("DisplayName() got = %q, want %q", got.DisplayName(), tt.wantDisplayName)
+			}
+		})
+	}
+}
+
+func TestSetDisplayName(t *testing.T) {
+	e, err := bemailparts.ParseAddress("John Doe <john.doe@example.com>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = e.SetDisplayName("Jane Doe"); err != nil {
+		t.Fatal(err)
+	}
+	if e.DisplayName() != "Jane Doe" || e.Email() != "john.doe@example.com" {
+		t.Errorf("DisplayName(), Email() got = %v, %v, want Jane Doe, john.doe@example.com", e.DisplayName(), e.Email())
+	}
+	if err = e.SetDisplayName("Jane\r\nBcc: x@example.com"); !errors.Is(err, bemailparts.ErrInvalidDisplayName) {
+		t.Errorf("SetDisplayName() error = %v, want %v", err, bemailparts.ErrInvalidDisplayName)
+	}
+	if e.DisplayName() != "Jane Doe" {
+		t.Errorf("DisplayName() got = %v after rejected setter, want Jane Doe", e.DisplayName())
+	}
+}
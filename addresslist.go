@@ -0,0 +1,273 @@
+package bemailparts
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// AddressError describes an entry of an address list that could not be parsed.
+type AddressError struct {
+	// Index is the position of the entry in the list, counting from zero.
+	Index int
+
+	// Address is the entry as it appears in the list, without surrounding whitespace.
+	Address string
+
+	// Err is the reason the entry was rejected, e.g. ErrInvalidEmailFormat.
+	Err error
+}
+
+func (e *AddressError) Error() string {
+	return fmt.Sprintf("address %d %q: %v", e.Index, e.Address, e.Err)
+}
+
+func (e *AddressError) Unwrap() error {
+	return e.Err
+}
+
+// AddressListError is returned by ParseAddressList and Parser.ParseList when some entries of the list could
+// not be parsed.
+type AddressListError struct {
+	Errors []*AddressError
+}
+
+func (e *AddressListError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns the errors of the failed entries, for Go 1.20 and later, which follow such lists.
+func (e *AddressListError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Is reports whether the error of any failed entry matches target, so that errors.Is matches any of them on
+// every Go version.
+func (e *AddressListError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error of a failed entry matching target, so that errors.As matches any of them on every
+// Go version.
+func (e *AddressListError) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseAddressList parses a comma-separated list of RFC 5322 mailboxes, as found in To and Cc headers or
+// CSV exports, using ParseAddress for each entry. Commas inside quoted display names, comments and angle
+// brackets do not separate entries, and empty entries are skipped. The members of groups such as
+// "team: alice@example.com, bob@example.com;" are returned like other entries; use ParseAddressGroups to
+// keep the groups.
+//
+// Parameters:
+//
+//	list: A list such as `a@x.com, Bob <b@y.org>, "Doe, John" <john@example.com>`.
+//	opts: Optional Options controlling validation of every address, e.g. WithRFC5322().
+//
+// Returns:
+//   - A BEmailParts instance per entry, in list order, with nil for the entries that could not be parsed.
+//   - An *AddressListError describing every failed entry, or nil if all entries were parsed.
+//
+// Example:
+//
+//	addresses, err := ParseAddressList(`a@x.com, "Doe, Bob" <b@y.org>, invalid`)
+//	var listErr *AddressListError
+//	if errors.As(err, &listErr) {
+//	    fmt.Println(listErr.Errors[0].Index) // Output: 2
+//	}
+//
+//	fmt.Println(addresses[1].DisplayName()) // Output: Doe, Bob
+func ParseAddressList(list string, opts ...Option) ([]BEmailParts, error) {
+	return parseAddressList(list, newOptions(opts))
+}
+
+func parseAddressList(list string, o *options) ([]BEmailParts, error) {
+	var addresses []BEmailParts
+	var listErr *AddressListError
+	for _, entry := range splitAddressList(list) {
+		if entry.address == "" {
+			continue
+		}
+
+		e, err := parseListEntry(entry, o)
+		if err != nil {
+			if listErr == nil {
+				listErr = &AddressListError{}
+			}
+			listErr.Errors = append(listErr.Errors, &AddressError{Index: len(addresses), Address: entry.address, Err: err})
+		}
+		addresses = append(addresses, e)
+	}
+
+	if listErr != nil {
+		return addresses, listErr
+	}
+	return addresses, nil
+}
+
+// AddressGroup is an RFC 5322 group of an address list, e.g. "team: alice@example.com, bob@example.com;".
+type AddressGroup struct {
+	// Name is the display name of the group, or an empty string for a mailbox outside any group.
+	Name string
+
+	// Addresses holds the members of the group in list order, with nil for the members that could not be
+	// parsed. It is empty for a group without members such as "undisclosed-recipients:;".
+	Addresses []BEmailParts
+}
+
+// ParseAddressGroups is like ParseAddressList, keeping the RFC 5322 groups of the list. Each mailbox outside
+// any group is returned as a group of its own with an empty Name. The Index of an AddressError counts
+// mailboxes across all groups, as in ParseAddressList.
+//
+// Example:
+//
+//	groups, err := ParseAddressGroups("team: alice@example.com, bob@example.com;, carol@example.com")
+//	if err != nil {
+//	    log.Fatalf("Invalid list: %v", err)
+//	}
+//
+//	fmt.Println(groups[0].Name)                 // Output: team
+//	fmt.Println(len(groups[0].Addresses))       // Output: 2
+//	fmt.Println(groups[1].Addresses[0].Email()) // Output: carol@example.com
+func ParseAddressGroups(list string, opts ...Option) ([]AddressGroup, error) {
+	return parseAddressGroups(list, newOptions(opts))
+}
+
+func parseAddressGroups(list string, o *options) ([]AddressGroup, error) {
+	var groups []AddressGroup
+	var listErr *AddressListError
+	index := 0
+	for _, entry := range splitAddressList(list) {
+		if !entry.grouped || entry.groupStart {
+			groups = append(groups, AddressGroup{})
+		}
+		group := &groups[len(groups)-1]
+		if entry.grouped {
+			group.Name, _ = parseDisplayName(entry.group)
+		}
+		if entry.address == "" {
+			continue
+		}
+
+		e, err := parseListEntry(entry, o)
+		if err != nil {
+			if listErr == nil {
+				listErr = &AddressListError{}
+			}
+			listErr.Errors = append(listErr.Errors, &AddressError{Index: index, Address: entry.address, Err: err})
+		}
+		group.Addresses = append(group.Addresses, e)
+		index++
+	}
+
+	if listErr != nil {
+		return groups, listErr
+	}
+	return groups, nil
+}
+
+// parseListEntry parses the mailbox of entry, rejecting it with ErrInvalidDisplayName if its group has a
+// malformed name.
+func parseListEntry(entry addressListEntry, o *options) (BEmailParts, error) {
+	if entry.grouped {
+		if _, err := parseDisplayName(entry.group); err != nil {
+			return nil, err
+		}
+	}
+	return parseAddress(entry.address, o)
+}
+
+// addressListEntry is a mailbox of an address list and the group it belongs to.
+type addressListEntry struct {
+	// address is the mailbox without surrounding whitespace. It is empty for the only entry of a group
+	// without members.
+	address string
+
+	// group is the unparsed name of the group, if grouped is true.
+	group   string
+	grouped bool
+
+	// groupStart is true for the first entry of a group.
+	groupStart bool
+}
+
+// splitAddressList splits list at the commas and group delimiters outside quoted strings, comments, angle
+// brackets and domain literals. Empty mailboxes are skipped, except that a group without members yields a
+// single entry with an empty address.
+func splitAddressList(list string) []addressListEntry {
+	var entries []addressListEntry
+	var group string
+	grouped, groupStart := false, false
+	add := func(address string) {
+		if address = strings.TrimSpace(address); address != "" {
+			entries = append(entries, addressListEntry{address: address, group: group, grouped: grouped, groupStart: groupStart})
+			groupStart = false
+		}
+	}
+	endGroup := func() {
+		if groupStart {
+			entries = append(entries, addressListEntry{group: group, grouped: true, groupStart: true})
+		}
+		group, grouped, groupStart = "", false, false
+	}
+
+	start, depth, quoted, angled, bracketed := 0, 0, false, false, false
+	for i := 0; i < len(list); i++ {
+		switch c := list[i]; {
+		case c == '\\' && (quoted || depth > 0):
+			i++
+		case quoted:
+			quoted = c != '"'
+		case c == '"' && depth == 0:
+			quoted = true
+		case c == '(':
+			depth++
+		case c == ')' && depth > 0:
+			depth--
+		case depth > 0:
+		case c == '[':
+			bracketed = true
+		case c == ']':
+			bracketed = false
+		case bracketed:
+		case c == '<':
+			angled = true
+		case c == '>':
+			angled = false
+		case angled:
+		case c == ',':
+			add(list[start:i])
+			start = i + 1
+		case c == ':' && !grouped:
+			group, grouped, groupStart = list[start:i], true, true
+			start = i + 1
+		case c == ';' && grouped:
+			add(list[start:i])
+			endGroup()
+			start = i + 1
+		}
+	}
+	add(list[start:])
+	if grouped {
+		endGroup()
+	}
+	return entries
+}
@@ -0,0 +1,211 @@
+package bemailparts_test
+
+import (
+	"errors"
+	"github.com/bearaujus/bemailparts"
+	"testing"
+)
+
+func TestParseAddressList(t *testing.T) {
+	tests := []struct {
+		name             string
+		list             string
+		wantEmails       []string
+		wantDisplayNames []string
+		wantErrIndexes   []int
+	}{
+		{
+			name:             "success mixed entries",
+			list:             "a@x.com, Bob <b@y.org>",
+			wantEmails:       []string{"a@x.com", "b@y.org"},
+			wantDisplayNames: []string{"", "Bob"},
+		},
+		{
+			name:             "success quoted display names with commas",
+			list:             `"Doe, John" <john@example.com>,"Roe, Jane (HR)" <jane@example.com>`,
+			wantEmails:       []string{"john@example.com", "jane@example.com"},
+			wantDisplayNames: []string{"Doe, John", "Roe, Jane (HR)"},
+		},
+		{
+			name:             "success escaped quote in display name",
+			list:             `"John \", Jr" <john@example.com>, jane@example.com`,
+			wantEmails:       []string{"john@example.com", "jane@example.com"},
+			wantDisplayNames: []string{`John ", Jr`, ""},
+		},
+		{
+			name:             "success empty entries skipped",
+			list:             " , a@x.com,, b@y.org , ",
+			wantEmails:       []string{"a@x.com", "b@y.org"},
+			wantDisplayNames: []string{"", ""},
+		},
+		{
+			name: "success empty list",
+			list: "  ",
+		},
+		{
+			name:             "error invalid entries reported by index",
+			list:             "invalid, a@x.com, Doe, John <john@example.com>",
+			wantEmails:       []string{"", "a@x.com", "", "john@example.com"},
+			wantDisplayNames: []string{"", "", "", "John"},
+			wantErrIndexes:   []int{0, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bemailparts.ParseAddressList(tt.list)
+			if len(got) != len(tt.wantEmails) {
+				t.Fatalf("ParseAddressList() got %d addresses, want %d", len(got), len(tt.wantEmails))
+			}
+			for i, e := range got {
+				if e == nil {
+					if tt.wantEmails[i] != "" {
+						t.Errorf("ParseAddressList() got nil at %d, want %v", i, tt.wantEmails[i])
+					}
+					continue
+				}
+				if e.Email() != tt.wantEmails[i] || e.DisplayName() != tt.wantDisplayNames[i] {
+					t.Errorf("ParseAddressList() got %q <%v> at %d, want %q <%v>",
+						e.DisplayName(), e.Email(), i, tt.wantDisplayNames[i], tt.wantEmails[i])
+				}
+			}
+
+			if tt.wantErrIndexes == nil {
+				if err != nil {
+					t.Errorf("ParseAddressList() error = %v, want nil", err)
+				}
+				return
+			}
+			var listErr *bemailparts.AddressListError
+			if !errors.As(err, &listErr) {
+				t.Fatalf("ParseAddressList() error = %v, want *AddressListError", err)
+			}
+			if len(listErr.Errors) != len(tt.wantErrIndexes) {
+				t.Fatalf("ParseAddressList() got errors %v, want indexes %v", listErr.Errors, tt.wantErrIndexes)
+			}
+			for i, index := range tt.wantErrIndexes {
+				if listErr.Errors[i].Index != index {
+					t.Errorf("ParseAddressList() got error index %d, want %d", listErr.Errors[i].Index, index)
+				}
+			}
+		})
+	}
+
+	t.Run("test errors is matches entry errors", func(t *testing.T) {
+		_, err := bemailparts.ParseAddressList("a@x.com, invalid")
+		if !errors.Is(err, bemailparts.ErrInvalidEmailFormat) {
+			t.Errorf("ParseAddressList() error = %v, want %v", err, bemailparts.ErrInvalidEmailFormat)
+		}
+	})
+
+	t.Run("test is and as methods match entry errors", func(t *testing.T) {
+		// Go before 1.20 does not follow Unwrap() []error, so the methods must match on their own.
+		_, err := bemailparts.ParseAddressList("a@x.com, invalid")
+		var listErr *bemailparts.AddressListError
+		if !errors.As(err, &listErr) {
+			t.Fatalf("ParseAddressList() error = %v, want an *AddressListError", err)
+		}
+		if !listErr.Is(bemailparts.ErrInvalidEmailFormat) || listErr.Is(bemailparts.ErrEmailTooLong) {
+			t.Errorf("Is() does not match only the errors of the entries")
+		}
+		var addressErr *bemailparts.AddressError
+		if !listErr.As(&addressErr) || addressErr.Index != 1 {
+			t.Errorf("As() got = %v, want the error of entry 1", addressErr)
+		}
+	})
+}
+
+func TestParseAddressGroups(t *testing.T) {
+	type group struct {
+		name   string
+		emails []string
+	}
+	tests := []struct {
+		name           string
+		list           string
+		want           []group
+		wantErrIndexes []int
+	}{
+		{
+			name: "success group",
+			list: "team:alice@example.com,bob@example.com;",
+			want: []group{{name: "team", emails: []string{"alice@example.com", "bob@example.com"}}},
+		},
+		{
+			name: "success groups mixed with mailboxes",
+			list: `carol@example.com, "Dev, Ops": Alice <alice@example.com>, bob@example.com;, dave@example.com`,
+			want: []group{
+				{emails: []string{"carol@example.com"}},
+				{name: "Dev, Ops", emails: []string{"alice@example.com", "bob@example.com"}},
+				{emails: []string{"dave@example.com"}},
+			},
+		},
+		{
+			name: "success empty group",
+			list: "undisclosed-recipients:;",
+			want: []group{{name: "undisclosed-recipients"}},
+		},
+		{
+			name: "success colon inside address literal and quotes",
+			list: `"a:b" <john@example.com>, team: jane@example.com;`,
+			want: []group{
+				{emails: []string{"john@example.com"}},
+				{name: "team", emails: []string{"jane@example.com"}},
+			},
+		},
+		{
+			name: "error invalid member",
+			list: "team: alice@example.com, invalid;, bob@example.com",
+			want: []group{
+				{name: "team", emails: []string{"alice@example.com", ""}},
+				{emails: []string{"bob@example.com"}},
+			},
+			wantErrIndexes: []int{1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bemailparts.ParseAddressGroups(tt.list)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseAddressGroups() got %d groups, want %d", len(got), len(tt.want))
+			}
+			for i, g := range got {
+				if g.Name != tt.want[i].name || len(g.Addresses) != len(tt.want[i].emails) {
+					t.Errorf("ParseAddressGroups() got group %q with %d addresses, want %q with %d",
+						g.Name, len(g.Addresses), tt.want[i].name, len(tt.want[i].emails))
+					continue
+				}
+				for j, e := range g.Addresses {
+					if (e == nil && tt.want[i].emails[j] != "") || (e != nil && e.Email() != tt.want[i].emails[j]) {
+						t.Errorf("ParseAddressGroups() got %v in group %q, want %v", e, g.Name, tt.want[i].emails[j])
+					}
+				}
+			}
+
+			var listErr *bemailparts.AddressListError
+			if tt.wantErrIndexes == nil {
+				if err != nil {
+					t.Errorf("ParseAddressGroups() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.As(err, &listErr) || len(listErr.Errors) != len(tt.wantErrIndexes) {
+				t.Fatalf("ParseAddressGroups() error = %v, want indexes %v", err, tt.wantErrIndexes)
+			}
+			for i, index := range tt.wantErrIndexes {
+				if listErr.Errors[i].Index != index {
+					t.Errorf("ParseAddressGroups() got error index %d, want %d", listErr.Errors[i].Index, index)
+				}
+			}
+		})
+	}
+
+	t.Run("test address list flattens groups", func(t *testing.T) {
+		got, err := bemailparts.ParseAddressList("team:alice@example.com,bob@example.com;, undisclosed:;")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 2 || got[0].Email() != "alice@example.com" || got[1].Email() != "bob@example.com" {
+			t.Errorf("ParseAddressList() got = %v, want [alice@example.com bob@example.com]", got)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package bemailparts
+
+import (
+	"net/netip"
+	"strings"
+)
+
+// ipv6LiteralTag is the tag prefixing IPv6 address literals, e.g. "[IPv6:2001:db8::1]" (RFC 5321 section 4.1.3).
+const ipv6LiteralTag = "IPv6:"
+
+// isAddressLiteral reports whether domain is written as an address literal, e.g. "[192.0.2.1]".
+func isAddressLiteral(domain string) bool {
+	return strings.HasPrefix(domain, "[")
+}
+
+// isCheckedAddressLiteral reports whether domain is an address literal that o validates with
+// validateAddressLiteral: with WithAllowIPDomain, and with WithRFC5321, whose Domain includes address
+// literals.
+func (o *options) isCheckedAddressLiteral(domain string) bool {
+	return (o.allowIPDomain || o.syntax == syntaxRFC5321) && isAddressLiteral(domain)
+}
+
+// validateAddressLiteral strictly validates an RFC 5321 address literal. IPv4 literals such as
+// "[192.0.2.1]" and IPv6 literals such as "[IPv6:2001:db8::1]" are accepted. Malformed literals return
+// ErrInvalidAddressLiteral, including IPv6 literals with a zone such as "[IPv6:fe80::1%eth0]": RFC 5321
+// has no syntax for zones, which only identify an interface of the host that wrote the address. Well-formed
+// general address literals using any other tag (e.g. "[x400:...]") return ErrUnsupportedAddressLiteral.
+func validateAddressLiteral(domain string) error {
+	if len(domain) < 2 || domain[0] != '[' || domain[len(domain)-1] != ']' {
+		return ErrInvalidAddressLiteral
+	}
+	literal := domain[1 : len(domain)-1]
+
+	if len(literal) >= len(ipv6LiteralTag) && strings.EqualFold(literal[:len(ipv6LiteralTag)], ipv6LiteralTag) {
+		addr, err := netip.ParseAddr(literal[len(ipv6LiteralTag):])
+		if err != nil || !addr.Is6() || addr.Zone() != "" {
+			return ErrInvalidAddressLiteral
+		}
+		return nil
+	}
+
+	if i := strings.IndexByte(literal, ':'); i >= 0 {
+		// An IPv6 address without its tag would otherwise pass as a general literal tagged e.g. "2001".
+		if _, err := netip.ParseAddr(literal); err == nil {
+			return ErrInvalidAddressLiteral
+		}
+		if isLDHLabel(literal[:i]) && i+1 < len(literal) && isGeneralLiteralContent(literal[i+1:]) {
+			return ErrUnsupportedAddressLiteral
+		}
+		return ErrInvalidAddressLiteral
+	}
+
+	if !isIPv4Literal(literal) {
+		return ErrInvalidAddressLiteral
+	}
+	return nil
+}
+
+// isIPv4Literal reports whether s is four dot-separated decimal numbers of 1 to 3 digits, each at most 255.
+func isIPv4Literal(s string) bool {
+	parts := strings.Split(s, domainSeparator)
+	if len(parts) != 4 {
+		return false
+	}
+	for _, part := range parts {
+		if len(part) == 0 || len(part) > 3 {
+			return false
+		}
+		n := 0
+		for i := 0; i < len(part); i++ {
+			if part[i] < '0' || part[i] > '9' {
+				return false
+			}
+			n = n*10 + int(part[i]-'0')
+		}
+		if n > 255 {
+			return false
+		}
+	}
+	return true
+}
+
+// isGeneralLiteralContent reports whether s is RFC 5321 dcontent: printable US-ASCII except '[', '\' and ']'.
+func isGeneralLiteralContent(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isDtext(s[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,190 @@
+package bemailparts_test
+
+import (
+	"errors"
+	"github.com/bearaujus/bemailparts"
+	"testing"
+)
+
+func TestNewWithAllowIPDomain(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		opts    []bemailparts.Option
+		wantErr error
+	}{
+		{
+			name:  "success ipv4",
+			email: "john@[192.0.2.1]",
+		},
+		{
+			name:  "success ipv6",
+			email: "john@[IPv6:2001:db8::1]",
+		},
+		{
+			name:  "success ipv6 with lowercase tag",
+			email: "john@[ipv6:2001:db8::1]",
+		},
+		{
+			name:  "success ipv4-mapped ipv6",
+			email: "john@[IPv6:::ffff:192.0.2.1]",
+		},
+		{
+			name:  "success with rfc5321",
+			email: "john@[192.0.2.1]",
+			opts:  []bemailparts.Option{bemailparts.WithRFC5321()},
+		},
+		{
+			name:  "success regular domain",
+			email: "john@example.com",
+		},
+		{
+			name:    "error ipv4 octet out of range",
+			email:   "john@[192.0.2.256]",
+			wantErr: bemailparts.ErrInvalidAddressLiteral,
+		},
+		{
+			name:    "error ipv4 with missing octet",
+			email:   "john@[192.0.2]",
+			wantErr: bemailparts.ErrInvalidAddressLiteral,
+		},
+		{
+			name:    "error ipv6 without tag",
+			email:   "john@[2001:db8::1]",
+			wantErr: bemailparts.ErrInvalidAddressLiteral,
+		},
+		{
+			name:    "error ipv6 tag with ipv4 address",
+			email:   "john@[IPv6:192.0.2.1]",
+			wantErr: bemailparts.ErrInvalidAddressLiteral,
+		},
+		{
+			name:    "error zoned ipv6",
+			email:   "john@[IPv6:fe80::1%eth0]",
+			wantErr: bemailparts.ErrInvalidAddressLiteral,
+		},
+		{
+			name:    "error zoned ipv6 with numeric zone",
+			email:   "john@[IPv6:fe80::1%1]",
+			wantErr: bemailparts.ErrInvalidAddressLiteral,
+		},
+		{
+			name:    "error zoned ipv6 with rfc5321",
+			email:   "john@[IPv6:fe80::1%eth0]",
+			opts:    []bemailparts.Option{bemailparts.WithRFC5321()},
+			wantErr: bemailparts.ErrInvalidAddressLiteral,
+		},
+		{
+			name:    "error zoned ipv6 without tag",
+			email:   "john@[fe80::1%eth0]",
+			wantErr: bemailparts.ErrInvalidAddressLiteral,
+		},
+		{
+			name:    "error malformed ipv6",
+			email:   "john@[IPv6:2001:db8:::1]",
+			wantErr: bemailparts.ErrInvalidAddressLiteral,
+		},
+		{
+			name:    "error unterminated literal",
+			email:   "john@[192.0.2.1",
+			wantErr: bemailparts.ErrInvalidAddressLiteral,
+		},
+		{
+			name:    "error unsupported literal type",
+			email:   "john@[x400:c=us;a=att]",
+			wantErr: bemailparts.ErrUnsupportedAddressLiteral,
+		},
+		{
+			name:    "error unsupported literal type with rfc5322",
+			email:   "john@[x400:c=us;a=att]",
+			opts:    []bemailparts.Option{bemailparts.WithRFC5322()},
+			wantErr: bemailparts.ErrUnsupportedAddressLiteral,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]bemailparts.Option{bemailparts.WithAllowIPDomain()}, tt.opts...)
+			got, err := bemailparts.New(tt.email, opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr == nil && got.Email() != tt.email {
+				t.Errorf("New() got = %v, want %v", got, tt.email)
+			}
+		})
+	}
+
+	t.Run("test address literal rejected without option", func(t *testing.T) {
+		if _, err := bemailparts.New("john@[192.0.2.1]"); err == nil {
+			t.Error("expecting an error on New() but got nil")
+		}
+	})
+
+	t.Run("test set domain to address literal", func(t *testing.T) {
+		e, err := bemailparts.New("john@example.com", bemailparts.WithAllowIPDomain())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = e.SetDomain("[IPv6:::1]"); err != nil {
+			t.Fatal(err)
+		}
+		if e.DomainName() != "" || e.DomainTLD() != "" {
+			t.Errorf("DomainName(), DomainTLD() got = %v, %v, want empty", e.DomainName(), e.DomainTLD())
+		}
+		if err = e.SetDomain("[300.0.0.1]"); !errors.Is(err, bemailparts.ErrInvalidAddressLiteral) {
+			t.Errorf("SetDomain() error = %v, want %v", err, bemailparts.ErrInvalidAddressLiteral)
+		}
+		if err = e.SetDomain("[IPv6:fe80::1%eth0]"); !errors.Is(err, bemailparts.ErrInvalidAddressLiteral) {
+			t.Errorf("SetDomain() error = %v, want %v", err, bemailparts.ErrInvalidAddressLiteral)
+		}
+	})
+
+	t.Run("test with allow address literal", func(t *testing.T) {
+		e, err := bemailparts.New("user@[192.168.10.25]", bemailparts.WithAllowAddressLiteral())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.Domain() != "[192.168.10.25]" || e.DomainName() != "" || e.DomainTLD() != "" {
+			t.Errorf("Domain(), DomainName(), DomainTLD() got = %v, %v, %v, want [192.168.10.25], empty, empty",
+				e.Domain(), e.DomainName(), e.DomainTLD())
+		}
+	})
+}
+
+func TestIPv6AddressLiteralRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		email string
+		opts  []bemailparts.Option
+	}{
+		{name: "compressed", email: "monitor@[IPv6:2001:db8::1]"},
+		{name: "uncompressed", email: "monitor@[IPv6:2001:0db8:0000:0000:0000:0000:0000:0001]"},
+		{name: "uppercase hex", email: "monitor@[IPv6:2001:DB8::1]"},
+		{name: "loopback", email: "monitor@[IPv6:::1]"},
+		{name: "rfc5322", email: "monitor@[IPv6:2001:db8::1]", opts: []bemailparts.Option{bemailparts.WithRFC5322()}},
+		{name: "rfc5321", email: "monitor@[IPv6:2001:db8::1]", opts: []bemailparts.Option{bemailparts.WithRFC5321()}},
+		{name: "html5", email: "monitor@[IPv6:2001:db8::1]", opts: []bemailparts.Option{bemailparts.WithHTML5Validation()}},
+		{name: "strict", email: "monitor@[IPv6:2001:db8::1]", opts: []bemailparts.Option{bemailparts.WithStrict()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]bemailparts.Option{bemailparts.WithAllowAddressLiteral()}, tt.opts...)
+			e, err := bemailparts.New(tt.email, opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = bemailparts.CheckInvariants(e, opts...); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := bemailparts.NewFromUsernameAndDomain(e.Username(), e.Domain(), opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Email() != tt.email {
+				t.Errorf("NewFromUsernameAndDomain() got = %v, want %v", got, tt.email)
+			}
+		})
+	}
+}
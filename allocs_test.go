@@ -0,0 +1,100 @@
+package bemailparts_test
+
+import (
+	"github.com/bearaujus/bemailparts"
+	"testing"
+)
+
+// benchmarkEmail is the address the hot paths are measured with.
+const benchmarkEmail = "test.username@test-domain.com"
+
+// TestAllocs guards the allocation counts of the hot paths, so changes to them cannot silently regress
+// throughput. Raise a limit only together with a benchmark below showing the cost is worth it.
+func TestAllocs(t *testing.T) {
+	const email = benchmarkEmail
+	e, err := bemailparts.New(email)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parser := bemailparts.NewParser()
+	arena := bemailparts.NewArena(0)
+
+	tests := []struct {
+		name string
+		max  float64
+		f    func()
+	}{
+		{name: "New", max: 1, f: func() { _, _ = bemailparts.New(email) }},
+		{name: "Parser.Parse", max: 1, f: func() { _, _ = parser.Parse(email) }},
+		{name: "Arena.New", max: 0, f: func() { _, _ = arena.New(email) }},
+		{name: "Validate", max: 0, f: func() { _ = bemailparts.Validate(email) }},
+		{name: "IsValid", max: 0, f: func() { _ = bemailparts.IsValid(email) }},
+		{name: "Parser.Validate", max: 0, f: func() { _ = parser.Validate(email) }},
+		{name: "Parser.IsValid", max: 0, f: func() { _ = parser.IsValid(email) }},
+		{name: "Email", max: 1, f: func() { _ = e.Email() }},
+		{name: "DomainName", max: 0, f: func() { _ = e.DomainName() }},
+		{name: "DomainTLD", max: 0, f: func() { _ = e.DomainTLD() }},
+		{name: "RegistrableDomain", max: 0, f: func() { _ = bemailparts.RegistrableDomain(e) }},
+		{name: "SetDomainTLD", max: 1, f: func() { _ = e.SetDomainTLD("com") }},
+		{name: "Canonical", max: 1, f: func() { _, _ = bemailparts.Canonical(email) }},
+		{name: "Canonical with tag", max: 3, f: func() { _, _ = bemailparts.Canonical("John.Doe+news@googlemail.com") }},
+
+		// Rejecting an address must not build the errors describing why.
+		{name: "IsValid john.doe@example", max: 0, f: func() { _ = bemailparts.IsValid("john.doe@example") }},
+		{name: "IsValid bad", max: 0, f: func() { _ = bemailparts.IsValid("bad") }},
+		{name: "IsValid a b@example.com", max: 0, f: func() { _ = bemailparts.IsValid("a b@example.com") }},
+		{name: "IsValid john@-x.com", max: 0, f: func() { _ = bemailparts.IsValid("john@-x.com") }},
+		{name: "IsValid john@example.123", max: 0, f: func() { _ = bemailparts.IsValid("john@example.123") }},
+		{name: "Parser.IsValid john.doe@example", max: 0, f: func() { _ = parser.IsValid("john.doe@example") }},
+		{name: "Parser.IsValid bad", max: 0, f: func() { _ = parser.IsValid("bad") }},
+		{name: "Parser.IsValid a b@example.com", max: 0, f: func() { _ = parser.IsValid("a b@example.com") }},
+		{name: "Parser.IsValid john@-x.com", max: 0, f: func() { _ = parser.IsValid("john@-x.com") }},
+		{name: "Parser.IsValid john@example.123", max: 0, f: func() { _ = parser.IsValid("john@example.123") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arena.New allocates a whole chunk at once, so average over more runs than a chunk holds.
+			if got := testing.AllocsPerRun(4096, tt.f); got > tt.max {
+				t.Errorf("AllocsPerRun() got = %v, want at most %v", got, tt.max)
+			}
+		})
+	}
+}
+
+func BenchmarkNew(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := bemailparts.New(benchmarkEmail); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParserParse(b *testing.B) {
+	parser := bemailparts.NewParser()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := parser.Parse(benchmarkEmail); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkArenaNew(b *testing.B) {
+	arena := bemailparts.NewArena(0)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := arena.New(benchmarkEmail); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCanonical(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := bemailparts.Canonical(benchmarkEmail); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
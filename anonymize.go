@@ -0,0 +1,267 @@
+package bemailparts
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// pseudonymLength is the number of hex characters kept from the keyed hash
+// when generating a pseudonymous username.
+const pseudonymLength = 16
+
+// emailFinderPattern locates address-shaped tokens inside arbitrary text such as CSV or JSONL lines,
+// including internationalized ones. It is looser than validation on purpose: every token it finds is either
+// rewritten or redacted, so that no address leaks into the output.
+const emailFinderPattern = `[\p{L}\p{M}\p{N}._%+-]+` + emailSeparator + `[\p{L}\p{M}\p{N}.-]+`
+
+var emailFinderRegex = regexp.MustCompile(emailFinderPattern)
+
+// redactedEmail replaces the address-shaped tokens of a dataset that are not valid addresses.
+const redactedEmail = "redacted@invalid"
+
+// datasetOptions parse the addresses of datasets, accepting internationalized ones so that they are
+// rewritten rather than redacted.
+var datasetOptions = newOptions([]Option{WithUnicodeLocalPart(), WithIDN()})
+
+// Anonymize replaces the username of an email address with a consistent pseudonym derived from key,
+// keeping the domain intact so the result can still be used for per-domain analytics.
+//
+// The pseudonym is a keyed hash of the whole address, ignoring case, so the same username on different
+// domains, which may belong to different people, gets unrelated pseudonyms. The same email and key always
+// produce the same pseudonym, and different keys produce unrelated pseudonyms.
+//
+// Example:
+//
+//	anon, err := Anonymize("john.doe@example.com", []byte("secret"))
+//	if err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	fmt.Println(anon) // Output: 876122cb51008a95@example.com
+func Anonymize(email string, key []byte) (string, error) {
+	return anonymize(email, key, defaultOptions)
+}
+
+func anonymize(email string, key []byte, o *options) (string, error) {
+	e, err := newEmailParts(email, o)
+	if err != nil {
+		return "", err
+	}
+	return anonymousEmail(e, key), nil
+}
+
+// AnonymizeDataset copies r to w, replacing every email address found in it with the pseudonym returned
+// by Anonymize. It works line by line on any text format, including CSV and JSONL, and leaves everything
+// other than the addresses untouched. Internationalized addresses are anonymized as well, and
+// address-shaped tokens that are not valid addresses, such as "john@example..com", are replaced with
+// "redacted@invalid" rather than copied. Lines are written in input order, so the same input and key always
+// produce byte-identical output.
+//
+// Example:
+//
+//	in, _ := os.Open("users.csv")
+//	out, _ := os.Create("users-anon.csv")
+//	if err := AnonymizeDataset(in, out, []byte("secret")); err != nil {
+//	    log.Fatalf("Failed to anonymize: %v", err)
+//	}
+func AnonymizeDataset(r io.Reader, w io.Writer, key []byte) error {
+	return rewriteDataset(r, w, func(email string) (string, error) {
+		return anonymize(email, key, datasetOptions)
+	})
+}
+
+// Synthesize replaces the username of an email address with a synthetic one of the same shape: every
+// lowercase letter becomes another lowercase letter, every uppercase letter another uppercase letter,
+// every digit another digit, and separators such as '.', '_', '+' and '-' stay where they are. The domain
+// is replaced with a reserved domain of RFC 2606 standing for its class of provider, so that staging systems
+// can never mail a real mailbox: "free-provider.example" for free mail providers (see IsFreeProvider),
+// "academic.example" for universities (see IsAcademic) and "corporate.example" for any other domain.
+// Domains that are already reserved, such as "example.com", are kept.
+//
+// Like Anonymize, the replacement is deterministic for a given email and key, ignoring case, which keeps
+// joins between synthesized tables intact.
+//
+// Example:
+//
+//	fake, err := Synthesize("John.Doe42@example.com", []byte("secret"))
+//	if err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	fmt.Println(fake) // Output: Agmz.Mbn44@example.com
+//
+//	fake, _ = Synthesize("John.Doe42@gmail.com", []byte("secret"))
+//	fmt.Println(fake) // Output: Rcdj.Usg51@free-provider.example
+func Synthesize(email string, key []byte) (string, error) {
+	return synthesizeEmail(email, key, defaultOptions)
+}
+
+func synthesizeEmail(email string, key []byte, o *options) (string, error) {
+	e, err := newEmailParts(email, o)
+	if err != nil {
+		return "", err
+	}
+	return syntheticEmail(e, key), nil
+}
+
+// SynthesizeDataset copies r to w, replacing every email address found in it with the synthetic address
+// returned by Synthesize. Like AnonymizeDataset, it works line by line on CSV, JSONL and other text formats,
+// and redacts address-shaped tokens that are not valid addresses.
+func SynthesizeDataset(r io.Reader, w io.Writer, key []byte) error {
+	return rewriteDataset(r, w, func(email string) (string, error) {
+		return synthesizeEmail(email, key, datasetOptions)
+	})
+}
+
+// ErasureTargets returns every representation of email that this package can derive from it: the address
+// itself, with its domain in ASCII form if it is internationalized, and its Canonical form, followed by its
+// Anonymize and Synthesize forms and its AuditRecord.InputHash under each of the given keys. Pass the key of
+// WithAuditLog among keys to find the audit records of the address as well. Internationalized addresses are
+// accepted, as in AnonymizeDataset. Data-deletion jobs can purge each returned value to remove all derived
+// copies of an address.
+//
+// Example:
+//
+//	targets, err := ErasureTargets("john.doe@example.com", []byte("2023-key"), []byte("2024-key"))
+//	if err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	for _, target := range targets {
+//	    db.DeleteWhereEmail(target)
+//	}
+func ErasureTargets(email string, keys ...[]byte) ([]string, error) {
+	e, err := newEmailParts(email, datasetOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	var targets []string
+	add := func(target string) {
+		if !seen[target] {
+			seen[target] = true
+			targets = append(targets, target)
+		}
+	}
+
+	add(e.Email())
+	if domain, err := ToASCII(e.Domain()); err == nil {
+		add(generateEmail(e.Username(), domain))
+	}
+	add(generateEmail(canonicalParts(strings.ToLower(e.Username()), strings.ToLower(e.Domain()))))
+	for _, key := range keys {
+		add(anonymousEmail(e, key))
+		add(syntheticEmail(e, key))
+		add(AuditHash(email, key))
+		add(AuditHash(e.Email(), key))
+	}
+	return targets, nil
+}
+
+// rewriteDataset streams r into w line by line, replacing each address-shaped token with the result of fn.
+// Tokens rejected by fn are replaced with redactedEmail. Dots and hyphens ending a token, such as the full
+// stop of a sentence, are kept as they are.
+func rewriteDataset(r io.Reader, w io.Writer, fn func(email string) (string, error)) error {
+	br := bufio.NewReader(r)
+	bw := bufio.NewWriter(w)
+	for {
+		line, err := br.ReadString('\n')
+		if len(line) > 0 {
+			line = emailFinderRegex.ReplaceAllStringFunc(line, func(token string) string {
+				email := strings.TrimRight(token, ".-")
+				replaced, fnErr := fn(email)
+				if fnErr != nil {
+					replaced = redactedEmail
+				}
+				return replaced + token[len(email):]
+			})
+			if _, wErr := bw.WriteString(line); wErr != nil {
+				return wErr
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// Reserved domains standing for the classes of provider of synthetic addresses.
+const (
+	syntheticFreeProviderDomain = "free-provider.example"
+	syntheticAcademicDomain     = "academic.example"
+	syntheticCorporateDomain    = "corporate.example"
+)
+
+// syntheticEmail returns the synthetic address of e, whose username is derived from the whole address.
+func syntheticEmail(e BEmailParts, key []byte) string {
+	return generateEmail(synthesize(e.Username(), strings.ToLower(e.Email()), key), syntheticDomain(e))
+}
+
+// syntheticDomain returns the reserved domain standing for the class of provider of the domain of e.
+func syntheticDomain(e BEmailParts) string {
+	switch {
+	case IsReservedDomain(e):
+		return e.Domain()
+	case IsFreeProvider(e):
+		return syntheticFreeProviderDomain
+	case IsAcademic(e):
+		return syntheticAcademicDomain
+	}
+	return syntheticCorporateDomain
+}
+
+// synthesize replaces the letters and digits of value with others of the same kind, drawn from a key stream
+// of seed. Letters outside ASCII become ASCII letters of the same case, so that no character of value is
+// kept.
+func synthesize(value, seed string, key []byte) string {
+	stream := keyStream(seed, key, len(value))
+	out := make([]rune, 0, len(value))
+	for i, r := range value {
+		switch {
+		case r >= '0' && r <= '9' || unicode.IsDigit(r):
+			r = rune('0' + stream[i]%10)
+		case unicode.IsUpper(r):
+			r = rune('A' + stream[i]%26)
+		case unicode.IsLetter(r) || unicode.IsMark(r):
+			r = rune('a' + stream[i]%26)
+		}
+		out = append(out, r)
+	}
+	return string(out)
+}
+
+// keyStream derives n pseudo-random bytes from value and key by chaining keyed hashes.
+func keyStream(value string, key []byte, n int) []byte {
+	stream := make([]byte, 0, n+sha256.Size)
+	block := []byte(value)
+	for len(stream) < n {
+		mac := hmac.New(sha256.New, key)
+		mac.Write(block)
+		block = mac.Sum(nil)
+		stream = append(stream, block...)
+	}
+	return stream[:n]
+}
+
+// anonymousEmail returns the address of e with its username replaced by the pseudonym of the address.
+func anonymousEmail(e BEmailParts, key []byte) string {
+	return generateEmail(pseudonym(strings.ToLower(e.Email()), key), e.Domain())
+}
+
+func pseudonym(value string, key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))[:pseudonymLength]
+}
@@ -0,0 +1,221 @@
+package bemailparts_test
+
+import (
+	"bytes"
+	"github.com/bearaujus/bemailparts"
+	"strings"
+	"testing"
+)
+
+func TestAnonymize(t *testing.T) {
+	key := []byte("secret")
+	got, err := bemailparts.Anonymize("john.doe@example.com", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "876122cb51008a95@example.com" {
+		t.Errorf("Anonymize() got = %v, want %v", got, "876122cb51008a95@example.com")
+	}
+
+	again, err := bemailparts.Anonymize("john.doe@example.com", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != got {
+		t.Errorf("Anonymize() is not consistent, got = %v and %v", got, again)
+	}
+
+	other, err := bemailparts.Anonymize("john.doe@example.com", []byte("other"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == got {
+		t.Errorf("Anonymize() with a different key got the same pseudonym %v", other)
+	}
+
+	otherDomain, err := bemailparts.Anonymize("john.doe@example.org", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if otherDomain[:16] == got[:16] {
+		t.Errorf("Anonymize() gave the same pseudonym to the same username on another domain, got = %v", otherDomain)
+	}
+
+	upper, err := bemailparts.Anonymize("John.Doe@Example.com", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if upper[:16] != got[:16] {
+		t.Errorf("Anonymize() is not case-insensitive, got = %v and %v", got, upper)
+	}
+
+	if _, err = bemailparts.Anonymize("!@#!@#", key); err == nil {
+		t.Error("expecting an error on Anonymize() but got nil")
+	}
+}
+
+func TestAnonymizeDataset(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "csv",
+			input: "id,email\n1,john.doe@example.com\n2,not-an-email\n",
+			want:  "id,email\n1,876122cb51008a95@example.com\n2,not-an-email\n",
+		},
+		{
+			name:  "jsonl without trailing newline",
+			input: `{"id":1,"email":"john.doe@example.com"}` + "\n" + `{"id":2,"to":["john.doe@example.com"]}`,
+			want:  `{"id":1,"email":"876122cb51008a95@example.com"}` + "\n" + `{"id":2,"to":["876122cb51008a95@example.com"]}`,
+		},
+		{
+			name:  "end of sentence",
+			input: "Write to john.doe@example.com.\n",
+			want:  "Write to 876122cb51008a95@example.com.\n",
+		},
+		{
+			name:  "empty label is redacted",
+			input: "1,john.doe@example..com\n",
+			want:  "1,redacted@invalid\n",
+		},
+		{
+			name:  "long username is redacted",
+			input: "1," + strings.Repeat("j", 65) + "@example.com\n",
+			want:  "1,redacted@invalid\n",
+		},
+		{
+			name:  "single label domain is redacted",
+			input: "1,john@localhost\n",
+			want:  "1,redacted@invalid\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := bemailparts.AnonymizeDataset(strings.NewReader(tt.input), &out, []byte("secret")); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("AnonymizeDataset() got = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestAnonymizeDatasetInternationalized(t *testing.T) {
+	input := "1,用户@example.com\n2,ivan@пример.рф\n"
+	var out bytes.Buffer
+	if err := bemailparts.AnonymizeDataset(strings.NewReader(input), &out, []byte("secret")); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out.String(), "用户") || strings.Contains(out.String(), "ivan") ||
+		strings.Contains(out.String(), "redacted") {
+		t.Errorf("AnonymizeDataset() got = %q, want every username anonymized", out.String())
+	}
+	if !strings.Contains(out.String(), "@пример.рф\n") {
+		t.Errorf("AnonymizeDataset() got = %q, want the domain kept", out.String())
+	}
+
+	out.Reset()
+	if err := bemailparts.SynthesizeDataset(strings.NewReader(input), &out, []byte("secret")); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out.String(), "用户") || strings.Contains(out.String(), "ivan") ||
+		strings.Contains(out.String(), "redacted") {
+		t.Errorf("SynthesizeDataset() got = %q, want every username synthesized", out.String())
+	}
+}
+
+func TestSynthesize(t *testing.T) {
+	key := []byte("secret")
+	got, err := bemailparts.Synthesize("John.Doe42@example.com", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "Agmz.Mbn44@example.com" {
+		t.Errorf("Synthesize() got = %v, want %v", got, "Agmz.Mbn44@example.com")
+	}
+
+	domains := []struct {
+		email string
+		want  string
+	}{
+		{email: "John.Doe42@gmail.com", want: "Rcdj.Usg51@free-provider.example"},
+		{email: "John.Doe42@cs.mit.edu", want: "Dogd.Xcv03@academic.example"},
+		{email: "John.Doe42@acme.com", want: "Sztc.Pvn99@corporate.example"},
+		{email: "John.Doe42@site.test", want: "@site.test"},
+	}
+	for _, tt := range domains {
+		if got, err = bemailparts.Synthesize(tt.email, key); err != nil || !strings.HasSuffix(got, tt.want) {
+			t.Errorf("Synthesize() got = %v, %v, want %v", got, err, tt.want)
+		}
+	}
+
+	a, _ := bemailparts.Synthesize("john@a.com", key)
+	b, _ := bemailparts.Synthesize("john@b.org", key)
+	if a == b {
+		t.Errorf("Synthesize() gave the same address %v to different addresses", a)
+	}
+
+	long := strings.Repeat("a", 50) + "@example.com"
+	got, err = bemailparts.Synthesize(long, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(long) || got == long {
+		t.Errorf("Synthesize() got = %v, want a different address of length %v", got, len(long))
+	}
+
+	if _, err = bemailparts.Synthesize("!@#!@#", key); err == nil {
+		t.Error("expecting an error on Synthesize() but got nil")
+	}
+}
+
+func TestSynthesizeDataset(t *testing.T) {
+	var out bytes.Buffer
+	input := "id,email\n1,John.Doe42@example.com\n"
+	if err := bemailparts.SynthesizeDataset(strings.NewReader(input), &out, []byte("secret")); err != nil {
+		t.Fatal(err)
+	}
+	if want := "id,email\n1,Agmz.Mbn44@example.com\n"; out.String() != want {
+		t.Errorf("SynthesizeDataset() got = %q, want %q", out.String(), want)
+	}
+}
+
+func TestErasureTargets(t *testing.T) {
+	got, err := bemailparts.ErasureTargets("John.Doe42@example.com", []byte("secret"), []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	anon, _ := bemailparts.Anonymize("John.Doe42@example.com", []byte("secret"))
+	want := []string{
+		"John.Doe42@example.com", "john.doe42@example.com", anon, "Agmz.Mbn44@example.com",
+		bemailparts.AuditHash("John.Doe42@example.com", []byte("secret")),
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("ErasureTargets() got = %v, want %v", got, want)
+	}
+
+	got, err = bemailparts.ErasureTargets("John.Doe+news@googlemail.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want = []string{"John.Doe+news@googlemail.com", "johndoe@gmail.com"}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("ErasureTargets() got = %v, want %v", got, want)
+	}
+
+	got, err = bemailparts.ErasureTargets("用户@bücher.de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want = []string{"用户@bücher.de", "用户@xn--bcher-kva.de"}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("ErasureTargets() got = %v, want %v", got, want)
+	}
+
+	if _, err = bemailparts.ErasureTargets("!@#!@#"); err == nil {
+		t.Error("expecting an error on ErasureTargets() but got nil")
+	}
+}
@@ -0,0 +1,57 @@
+package bemailparts
+
+import "sync"
+
+// defaultArenaChunkSize is the number of BEmailParts allocated at once by an Arena created with a
+// non-positive chunk size.
+const defaultArenaChunkSize = 1024
+
+// Arena creates BEmailParts instances in chunks instead of one allocation each. Jobs that parse millions of
+// addresses and keep them all in memory leave the garbage collector far fewer objects to track.
+//
+// The instances of a chunk are freed together once none of them is referenced anymore, so an Arena suits
+// addresses that share a lifetime, e.g. a dataset loaded for a single job.
+//
+// An Arena is safe for concurrent use.
+type Arena struct {
+	mu        sync.Mutex
+	opts      *options
+	chunkSize int
+	chunk     []bEmailParts
+}
+
+// NewArena creates an Arena allocating chunkSize instances at a time and applying opts to every address it
+// parses. A non-positive chunkSize selects a default of 1024.
+//
+// Example:
+//
+//	arena := NewArena(4096, WithLowercase())
+//	emailParts, err := arena.New("John.Doe@Example.com")
+//	if err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	fmt.Println(emailParts.Email()) // Output: john.doe@example.com
+func NewArena(chunkSize int, opts ...Option) *Arena {
+	if chunkSize <= 0 {
+		chunkSize = defaultArenaChunkSize
+	}
+	return &Arena{opts: newOptions(opts), chunkSize: chunkSize}
+}
+
+// New is like the package-level New, using the options of the Arena and storing the result in its current
+// chunk. Invalid addresses take no space in the Arena.
+func (a *Arena) New(email string) (BEmailParts, error) {
+	e, err := parseEmailParts(email, a.opts)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.chunk) == cap(a.chunk) {
+		a.chunk = make([]bEmailParts, 0, a.chunkSize)
+	}
+	a.chunk = append(a.chunk, e)
+	return &a.chunk[len(a.chunk)-1], nil
+}
@@ -0,0 +1,36 @@
+package bemailparts_test
+
+import (
+	"github.com/bearaujus/bemailparts"
+	"strconv"
+	"testing"
+)
+
+func TestArena(t *testing.T) {
+	arena := bemailparts.NewArena(2, bemailparts.WithLowercase())
+
+	var parts []bemailparts.BEmailParts
+	for i := 0; i < 5; i++ {
+		e, err := arena.New("User" + strconv.Itoa(i) + "@Example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts = append(parts, e)
+	}
+	if _, err := arena.New("invalid"); err == nil {
+		t.Error("expecting an error on New() but got nil")
+	}
+
+	if err := parts[0].SetUsername("changed"); err != nil {
+		t.Fatal(err)
+	}
+	for i, e := range parts {
+		want := "user" + strconv.Itoa(i) + "@example.com"
+		if i == 0 {
+			want = "changed@example.com"
+		}
+		if e.Email() != want {
+			t.Errorf("Email() got = %v, want %v", e.Email(), want)
+		}
+	}
+}
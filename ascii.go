@@ -0,0 +1,98 @@
+package bemailparts
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// The functions below match the default patterns (usernamePattern, domainNamePattern, domainTLDPattern,
+// domainPattern and emailPattern) byte by byte. They serve pure-ASCII input, which is the common case,
+// without regular expression overhead; input containing other characters takes the general path.
+
+// isASCII reports whether s contains only ASCII characters.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+func matchEmailASCII(s string) bool {
+	i := strings.LastIndexByte(s, emailSeparator[0])
+	return i >= 0 && matchUsernameASCII(s[:i]) && matchDomainASCII(s[i+1:])
+}
+
+func matchUsernameASCII(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; !isAlphaNumeric(c) && c != '.' && c != '_' && c != '%' && c != '+' && c != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+func matchDomainNameASCII(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; !isAlphaNumeric(c) && c != '.' && c != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+func matchDomainASCII(s string) bool {
+	i := strings.LastIndexByte(s, domainSeparator[0])
+	return i > 0 && matchDomainNameASCII(s[:i]) && isTLDLabel(s[i+1:])
+}
+
+func matchDomainTLDASCII(s string) bool {
+	labels := strings.TrimPrefix(s, domainSeparator)
+	for {
+		i := strings.IndexByte(labels, domainSeparator[0])
+		if i < 0 {
+			return isTLDLabel(labels)
+		}
+		if !isAlphaNumericOrHyphen(labels[:i]) {
+			return false
+		}
+		labels = labels[i+1:]
+	}
+}
+
+func isAlphaNumericOrHyphen(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; !isAlphaNumeric(c) && c != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// isTLDLabel reports whether s is made of letters or is an ACE label such as "xn--p1ai".
+func isTLDLabel(s string) bool {
+	return isLetters(s) || (len(s) > len(acePrefix) && strings.EqualFold(s[:len(acePrefix)], acePrefix) &&
+		isAlphaNumericOrHyphen(s[len(acePrefix):]))
+}
+
+func isLetters(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package bemailparts_test
+
+import (
+	"github.com/bearaujus/bemailparts"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// defaultEmailRegex is the documented default email pattern, which the ASCII fast path must agree with.
+var defaultEmailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.(?:[a-zA-Z]+|[xX][nN]--[a-zA-Z0-9-]+)$`)
+
+// isValidDefaultEmail reports whether email matches defaultEmailRegex and satisfies the length limits and
+// DNS label rules that the default validation applies on top of it.
+func isValidDefaultEmail(email string) bool {
+	if !defaultEmailRegex.MatchString(email) || len(email) > 254 {
+		return false
+	}
+	i := strings.LastIndex(email, "@")
+	if i > 64 || len(email)-i-1 > 255 {
+		return false
+	}
+	for _, label := range strings.Split(email[i+1:], ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+	}
+	return true
+}
+
+func TestNewNonASCII(t *testing.T) {
+	for _, email := range []string{"jöhn@example.com", "john@exämple.com", "john@example.cöm"} {
+		t.Run(email, func(t *testing.T) {
+			if _, err := bemailparts.New(email); err == nil {
+				t.Errorf("expecting an error on New(%q) but got nil", email)
+			}
+		})
+	}
+}
+
+func FuzzNewMatchesDefaultPattern(f *testing.F) {
+	for _, seed := range []string{
+		"test.username@test-domain.com",
+		"a@b.c",
+		"a@.b",
+		"a@b.",
+		"a@b.c1",
+		"a@b.xn--p1ai",
+		"a@b.xn--",
+		"a@b.XN--1-",
+		"a.b@c..d.e",
+		"a@-b.c",
+		"a@b-.c",
+		"a@@b.c",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, email string) {
+		_, err := bemailparts.New(email)
+		want := isValidDefaultEmail(email)
+		if (err == nil) != want {
+			t.Errorf("New(%q) error = %v, want valid %v", email, err, want)
+		}
+		if got := bemailparts.IsValid(email); got != want {
+			t.Errorf("IsValid(%q) got = %v, want %v", email, got, want)
+		}
+	})
+}
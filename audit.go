@@ -0,0 +1,177 @@
+package bemailparts
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// AuditSink receives an AuditRecord for every validation decision made under WithAuditLog. Implementations
+// append the records to durable storage and must be safe for concurrent use. Record is called synchronously,
+// so slow sinks should buffer.
+type AuditSink interface {
+	Record(record AuditRecord)
+}
+
+// AuditSinkFunc adapts a function to AuditSink.
+type AuditSinkFunc func(record AuditRecord)
+
+// Record calls f(record).
+func (f AuditSinkFunc) Record(record AuditRecord) {
+	f(record)
+}
+
+// AuditRecord describes a validation decision. It identifies the input by a keyed hash only, so audit logs
+// hold no addresses, and the addresses cannot be recovered from them by hashing candidate addresses.
+type AuditRecord struct {
+	// Time is when the decision was made.
+	Time time.Time
+
+	// InputHash is the hex-encoded HMAC-SHA256, under the key passed to WithAuditLog, of the validated input:
+	// the address passed to a constructor, or the address a setter would produce. It is empty if the key is.
+	InputHash string
+
+	// PolicyVersion is the version passed to WithAuditLog.
+	PolicyVersion string
+
+	// Checks names the checks applied, e.g. ["syntax:rfc5321", "length-limits", "dns-labels"].
+	Checks []string
+
+	// Accepted reports whether the input passed every check.
+	Accepted bool
+
+	// Code identifies the reason the input was rejected, or is empty if it was accepted.
+	Code ErrorCode
+}
+
+// WithAuditLog records every decision of the constructors and setters as an AuditRecord in sink, tagged with
+// policyVersion. Regulated senders can use it to prove which rules were applied to each address.
+//
+// The key is required: inputs are hashed with HMAC-SHA256 under it, so that an unkeyed hash of a known
+// address cannot be matched against the log. Keep it secret and stable; with the same key, AuditHash finds
+// the records of an address. Without a key, records are written with an empty InputHash.
+//
+// Example:
+//
+//	sink := AuditSinkFunc(func(record AuditRecord) {
+//	    auditLog.Append(record)
+//	})
+//	parser := NewParser(WithRFC5321(), WithAuditLog(sink, "2024-06-01", auditKey))
+func WithAuditLog(sink AuditSink, policyVersion string, key []byte) Option {
+	return func(o *options) {
+		o.auditSink = sink
+		o.policyVersion = policyVersion
+		o.auditKey = key
+	}
+}
+
+// AuditHash returns the AuditRecord.InputHash of input under key, e.g. to find or erase the audit records
+// of an address. Returns an empty string if key is empty.
+func AuditHash(input string, key []byte) string {
+	if len(key) == 0 {
+		return ""
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(input))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// audit records the decision err on input in the audit sink, if any.
+func (o *options) audit(input string, err error) {
+	if o.auditSink == nil {
+		return
+	}
+	code, _ := ErrorCodeOf(err)
+	o.auditSink.Record(AuditRecord{
+		Time:          o.now(),
+		InputHash:     AuditHash(input, o.auditKey),
+		PolicyVersion: o.policyVersion,
+		Checks:        o.checks(),
+		Accepted:      err == nil,
+		Code:          code,
+	})
+}
+
+// auditParts is like audit for the address made of username and domain, which is only built if needed.
+func (o *options) auditParts(username, domain string, err error) {
+	if o.auditSink == nil {
+		return
+	}
+	o.audit(generateEmail(username, domain), err)
+}
+
+// checks names the checks applied under o, in the order they run. Every option changing which addresses are
+// accepted adds a check.
+func (o *options) checks() []string {
+	var checks []string
+	if o.obsolete {
+		checks = append(checks, "obsolete-syntax")
+	}
+	if o.comments {
+		checks = append(checks, "comments")
+	}
+	if o.asciiOnly {
+		checks = append(checks, "ascii-only")
+	}
+	switch o.syntax {
+	case syntaxRFC5322:
+		checks = append(checks, "syntax:rfc5322")
+	case syntaxRFC5321:
+		checks = append(checks, "syntax:rfc5321", "dns-labels")
+	case syntaxHTML5:
+		checks = append(checks, "syntax:html5", "dns-labels")
+	default:
+		checks = append(checks, "syntax:default", "dns-labels")
+	}
+	if o.singleLabel {
+		checks = append(checks, "single-label-domain")
+	}
+	if o.idn {
+		checks = append(checks, "idn")
+	}
+	if o.unicode {
+		checks = append(checks, "unicode-local-part")
+	}
+	if o.strictDotAtom() {
+		checks = append(checks, "dot-atom")
+	}
+	if o.allowIPDomain {
+		checks = append(checks, "address-literal")
+	}
+	checks = append(checks, "length-limits")
+	if o.rejectReserved {
+		checks = append(checks, "reserved-domains")
+	}
+	if o.effectiveMinTLDLength() > 0 {
+		checks = append(checks, "min-tld-length")
+	}
+	if o.effectiveMaxTLDLength() < DefaultMaxTLDLength {
+		checks = append(checks, "max-tld-length")
+	}
+	if o.strict {
+		checks = append(checks, "numeric-tld")
+	}
+	if o.allowedTLDs != nil {
+		checks = append(checks, "allowed-tlds")
+	}
+	if o.knownTLDsOnly {
+		checks = append(checks, "known-tlds")
+	}
+	if o.requireRegistrable {
+		checks = append(checks, "registrable-domain")
+	}
+	if o.rejectDisposable {
+		checks = append(checks, "disposable-domains")
+	}
+	if o.rejectFreeProviders {
+		checks = append(checks, "free-providers")
+	}
+	if len(o.usernameValidators) > 0 {
+		checks = append(checks, "username-validators")
+	}
+	if len(o.domainValidators) > 0 {
+		checks = append(checks, "domain-validators")
+	}
+	return checks
+}
@@ -0,0 +1,171 @@
+package bemailparts_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/bearaujus/bemailparts"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+)
+
+type memoryAuditSink struct {
+	mu      sync.Mutex
+	records []bemailparts.AuditRecord
+}
+
+func (s *memoryAuditSink) Record(record bemailparts.AuditRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, record)
+}
+
+func TestWithAuditLog(t *testing.T) {
+	key := []byte("audit-key")
+	hash := func(s string) string {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(s))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+
+	sink := &memoryAuditSink{}
+	parser := bemailparts.NewParser(bemailparts.WithRFC5321(), bemailparts.WithStrict(), bemailparts.WithAuditLog(sink, "v7", key))
+
+	e, err := parser.Parse("john@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = parser.Parse("john@example.c"); err == nil {
+		t.Fatal("expecting an error on Parse() but got nil")
+	}
+	if err = e.SetUsername("jane"); err != nil {
+		t.Fatal(err)
+	}
+	if err = e.SetDomainTLD("-"); err == nil {
+		t.Fatal("expecting an error on SetDomainTLD() but got nil")
+	}
+	if _, err = parser.ParseFromFullParts("john", "example", "org"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		input    string
+		accepted bool
+		code     bemailparts.ErrorCode
+	}{
+		{input: "john@example.com", accepted: true},
+		{input: "john@example.c", code: bemailparts.CodeEmailDomainTLDTooShort},
+		{input: "jane@example.com", accepted: true},
+		{input: "jane@example.-", code: bemailparts.CodeInvalidEmailDomainTLDFormat},
+		{input: "john@example.org", accepted: true},
+	}
+	if len(sink.records) != len(want) {
+		t.Fatalf("got %d audit records, want %d", len(sink.records), len(want))
+	}
+	wantChecks := []string{"syntax:rfc5321", "dns-labels", "length-limits", "min-tld-length", "numeric-tld"}
+	for i, record := range sink.records {
+		if record.InputHash != hash(want[i].input) || record.InputHash != bemailparts.AuditHash(want[i].input, key) || record.Accepted != want[i].accepted || record.Code != want[i].code {
+			t.Errorf("record %d got = %+v, want %+v", i, record, want[i])
+		}
+		if record.PolicyVersion != "v7" || !reflect.DeepEqual(record.Checks, wantChecks) || record.Time.IsZero() {
+			t.Errorf("record %d got PolicyVersion = %v, Checks = %v, Time = %v", i, record.PolicyVersion, record.Checks, record.Time)
+		}
+	}
+}
+
+func TestWithAuditLogWithoutKey(t *testing.T) {
+	sink := &memoryAuditSink{}
+	if _, err := bemailparts.New("john@example.com", bemailparts.WithAuditLog(sink, "v7", nil)); err != nil {
+		t.Fatal(err)
+	}
+	if len(sink.records) != 1 || sink.records[0].InputHash != "" {
+		t.Errorf("audit records got = %+v, want one without InputHash", sink.records)
+	}
+}
+
+// TestAuditChecks fails when an Option of the package is added without being listed here, so that every
+// option changing which addresses are accepted is named in AuditRecord.Checks.
+func TestAuditChecks(t *testing.T) {
+	validating := map[string]bemailparts.Option{
+		"WithAllowAddressLiteral":      bemailparts.WithAllowAddressLiteral(),
+		"WithAllowIPDomain":            bemailparts.WithAllowIPDomain(),
+		"WithAllowSingleLabelDomain":   bemailparts.WithAllowSingleLabelDomain(),
+		"WithAllowedTLDs":              bemailparts.WithAllowedTLDs("com"),
+		"WithASCIIOnly":                bemailparts.WithASCIIOnly(),
+		"WithComments":                 bemailparts.WithComments(),
+		"WithDomainValidator":          bemailparts.WithDomainValidator(func(string) error { return nil }),
+		"WithHTML5Validation":          bemailparts.WithHTML5Validation(),
+		"WithIDN":                      bemailparts.WithIDN(),
+		"WithKnownTLDs":                bemailparts.WithKnownTLDs("corp"),
+		"WithKnownTLDsOnly":            bemailparts.WithKnownTLDsOnly(),
+		"WithMaxTLDLength":             bemailparts.WithMaxTLDLength(24),
+		"WithMinTLDLength":             bemailparts.WithMinTLDLength(2),
+		"WithObsoleteSyntax":           bemailparts.WithObsoleteSyntax(),
+		"WithRejectDisposableDomains":  bemailparts.WithRejectDisposableDomains(),
+		"WithRejectFreeProviders":      bemailparts.WithRejectFreeProviders(),
+		"WithRejectReservedDomains":    bemailparts.WithRejectReservedDomains(),
+		"WithRequireRegistrableDomain": bemailparts.WithRequireRegistrableDomain(),
+		"WithRFC5321":                  bemailparts.WithRFC5321(),
+		"WithRFC5322":                  bemailparts.WithRFC5322(),
+		"WithStrict":                   bemailparts.WithStrict(),
+		"WithUnicodeLocalPart":         bemailparts.WithUnicodeLocalPart(),
+		"WithUsernameValidator":        bemailparts.WithUsernameValidator(func(string) error { return nil }),
+	}
+	// Options that do not change which addresses are accepted on their own.
+	others := map[string]bool{
+		"WithAuditLog":      true,
+		"WithClock":         true,
+		"WithFreeProviders": true,
+		"WithHistory":       true,
+		"WithLowercase":     true,
+		"WithRandSource":    true,
+		"WithSampling":      true,
+	}
+
+	pkgs, err := parser.ParseDir(token.NewFileSet(), ".", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var options []string
+	for _, file := range pkgs["bemailparts"].Files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || !fn.Name.IsExported() || fn.Type.Results == nil || len(fn.Type.Results.List) != 1 {
+				continue
+			}
+			if result, ok := fn.Type.Results.List[0].Type.(*ast.Ident); ok && result.Name == "Option" {
+				options = append(options, fn.Name.Name)
+			}
+		}
+	}
+	sort.Strings(options)
+
+	checks := func(opts ...bemailparts.Option) []string {
+		sink := &memoryAuditSink{}
+		opts = append(opts, bemailparts.WithAuditLog(sink, "v1", []byte("audit-key")))
+		_, _ = bemailparts.New("john@example.com", opts...)
+		if len(sink.records) != 1 {
+			t.Fatalf("got %d audit records, want 1", len(sink.records))
+		}
+		return sink.records[0].Checks
+	}
+	defaults := checks()
+	for _, name := range options {
+		if others[name] {
+			continue
+		}
+		opt, ok := validating[name]
+		if !ok {
+			t.Errorf("%s is not listed in TestAuditChecks", name)
+			continue
+		}
+		if got := checks(opt); reflect.DeepEqual(got, defaults) {
+			t.Errorf("%s got Checks = %v, want them to differ from the defaults", name, got)
+		}
+	}
+}
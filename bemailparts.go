@@ -1,7 +1,6 @@
 package bemailparts
 
 import (
-	"fmt"
 	"regexp"
 	"strings"
 )
@@ -12,11 +11,21 @@ const (
 )
 
 const (
-	usernamePattern   = `^[a-zA-Z0-9._%+-]+`
-	domainNamePattern = `[a-zA-Z0-9.-]+`
-	domainTLDPattern  = `[a-zA-Z]+$`
-	domainPattern     = domainNamePattern + `\` + domainSeparator + domainTLDPattern
-	emailPattern      = usernamePattern + emailSeparator + domainPattern
+	usernameChars   = `[a-zA-Z0-9._%+-]+`
+	domainNameChars = `[a-zA-Z0-9.-]+`
+	domainTLDChars  = `(?:[a-zA-Z]+|[xX][nN]--[a-zA-Z0-9-]+)`
+	domainChars     = domainNameChars + `\` + domainSeparator + domainTLDChars
+)
+
+// Every pattern is anchored on both ends so that leading or trailing junk never passes validation.
+// The TLD is the public suffix of the domain, so it may span several labels, e.g. "co.id". Its
+// last label is made of letters, or is the ACE form of an internationalized TLD, e.g. "xn--p1ai" for "рф".
+const (
+	usernamePattern   = `^` + usernameChars + `$`
+	domainNamePattern = `^` + domainNameChars + `$`
+	domainTLDPattern  = `^\` + domainSeparator + `?(?:[a-zA-Z0-9-]+\` + domainSeparator + `)*` + domainTLDChars + `$`
+	domainPattern     = `^` + domainChars + `$`
+	emailPattern      = `^` + usernameChars + emailSeparator + domainChars + `$`
 )
 
 var (
@@ -36,19 +45,27 @@ type BEmailParts interface {
 
 	// Username returns the username part of the email (before the '@').
 	// Example: "john.doe" from "john.doe@example.com".
+	// Quoted usernames accepted by WithRFC5322 keep their quotes, e.g. `"john doe"`.
 	Username() string
 
 	// Domain returns the domain part of the email (after the '@').
 	// Example: "example.com" from "john.doe@example.com".
 	Domain() string
 
-	// DomainName returns the domain name (e.g., "example" in "example.com").
+	// DomainName returns the domain name (e.g., "example" in "example.com"), the part of the domain before
+	// the TLD, including any subdomains.
 	// Example: "example" from "john.doe@example.com".
+	// Example 2: "mail.example" from "john.doe@mail.example.co.uk".
+	// Returns an empty string for a domain literal such as "[192.0.2.1]".
 	DomainName() string
 
-	// DomainTLD returns the top-level domain (TLD) of the email (e.g., "com" in "example.com").
+	// DomainTLD returns the top-level domain (TLD) of the email (e.g., "com" in "example.com"): its public
+	// suffix, under which domains are registered, according to the embedded Public Suffix List.
 	// Example: ".com" from "john.doe@example.com".
 	// Example 2: ".co.id" from "john.doe@example.co.id".
+	// Example 3: ".co.uk" from "john.doe@mail.example.co.uk".
+	// A domain that is itself a public suffix, such as "co.uk", is split at its first dot.
+	// Returns an empty string for a domain without a dot or a domain literal such as "[192.0.2.1]".
 	DomainTLD() string
 
 	// DomainTLDWithoutDot returns the top-level domain (TLD)
@@ -57,6 +74,13 @@ type BEmailParts interface {
 	// Example 2: "co.id" from "john.doe@example.co.id".
 	DomainTLDWithoutDot() string
 
+	// Subdomain returns the part of the domain before the registrable domain, e.g. a departmental or
+	// regional host.
+	// Example: "mail" from "john.doe@mail.example.com".
+	// Example 2: "eu.mail" from "john.doe@eu.mail.example.co.uk".
+	// Returns an empty string if the domain has no subdomain or no registrable domain.
+	Subdomain() string
+
 	// SetUsername updates the username part of the email.
 	// Example: If called with "jane.doe", the updated email will be "jane.doe@example.com".
 	// Returns an error if the provided username is invalid.
@@ -77,14 +101,42 @@ type BEmailParts interface {
 	// Returns an error if the provided TLD is invalid.
 	SetDomainTLD(domainTLD string) error
 
+	// SetSubdomain updates the subdomain of the email, keeping its registrable domain. An empty subdomain
+	// removes it.
+	// Example: If called with "eu" on "john.doe@mail.example.com", the updated email will be
+	// "john.doe@eu.example.com".
+	// Returns an error if the provided subdomain is invalid, or ErrNoRegistrableDomain if the domain has no
+	// registrable domain to keep.
+	SetSubdomain(subdomain string) error
+
+	// Comments returns the RFC 5322 comments stripped from the address when it was parsed with
+	// WithComments, in order of appearance, or nil if there were none.
+	// Example: ["work"] from "john.doe(work)@example.com".
+	Comments() []string
+
+	// DisplayName returns the display name of an address parsed with ParseAddress, or an empty string if it
+	// has none.
+	// Example: "John Doe" from "John Doe <john.doe@example.com>".
+	DisplayName() string
+
+	// SetDisplayName updates the display name of the email. It does not affect Email.
+	// Example: If called with "Jane Doe", DisplayName returns "Jane Doe".
+	// Returns an error if the provided display name contains control characters.
+	SetDisplayName(displayName string) error
+
 	// String returns the string representation of the email address.
 	// Example: "john.doe@example.com"
 	String() string
 }
 
 type bEmailParts struct {
-	username string
-	domain   string
+	username    string
+	domain      string
+	displayName string
+	comments    []string
+	history     []edit
+	redo        []edit
+	opts        *options
 }
 
 // New creates a new instance of BEmailParts by parsing a full email address.
@@ -92,10 +144,14 @@ type bEmailParts struct {
 // Parameters:
 //
 //	email: A valid email address in the format "username@domain".
+//	opts: Optional Options controlling validation, e.g. WithRFC5322().
 //
 // Returns:
 //   - A BEmailParts instance representing the parsed email.
-//   - An error if the email format is invalid (e.g., missing '@' or invalid characters).
+//   - An error if the email format is invalid (e.g., missing '@' or invalid characters), or if the username is
+//     longer than 64 octets, the domain longer than 255 octets or the address longer than 254 octets.
+//     The error is a *ParseError telling where the problem is, which unwraps to errors such as
+//     ErrInvalidEmailFormat.
 //
 // Example:
 //
@@ -110,19 +166,8 @@ type bEmailParts struct {
 //	fmt.Println(emailParts.DomainName())          // Output: example
 //	fmt.Println(emailParts.DomainTLD())           // Output: .com
 //	fmt.Println(emailParts.DomainTLDWithoutDot()) // Output: com
-func New(email string) (BEmailParts, error) {
-	if !emailRegex.MatchString(email) {
-		return nil, ErrInvalidEmailFormat
-	}
-
-	parts := strings.Split(email, emailSeparator)
-	username := parts[0]
-	domain := parts[1]
-
-	return &bEmailParts{
-		username: username,
-		domain:   domain,
-	}, nil
+func New(email string, opts ...Option) (BEmailParts, error) {
+	return newEmailParts(email, newOptions(opts))
 }
 
 // NewFromUsernameAndDomain creates a new instance of BEmailParts from a username and domain.
@@ -131,6 +176,7 @@ func New(email string) (BEmailParts, error) {
 //
 //	username: The username part of the email (before the '@').
 //	domain: The domain part of the email (after the '@').
+//	opts: Optional Options controlling validation, e.g. WithRFC5322().
 //
 // Returns:
 //   - A BEmailParts instance representing the constructed email.
@@ -149,14 +195,8 @@ func New(email string) (BEmailParts, error) {
 //	fmt.Println(emailParts.DomainName())          // Output: example
 //	fmt.Println(emailParts.DomainTLD())           // Output: .com
 //	fmt.Println(emailParts.DomainTLDWithoutDot()) // Output: com
-func NewFromUsernameAndDomain(username, domain string) (BEmailParts, error) {
-	if !usernameRegex.MatchString(username) {
-		return nil, ErrInvalidEmailUsernameFormat
-	}
-	if !domainRegex.MatchString(domain) {
-		return nil, ErrInvalidEmailDomainFormat
-	}
-	return New(generateEmail(username, domain))
+func NewFromUsernameAndDomain(username, domain string, opts ...Option) (BEmailParts, error) {
+	return newFromUsernameAndDomain(username, domain, newOptions(opts))
 }
 
 // NewFromFullParts creates a new instance of BEmailParts from a username, domain name, and domain TLD.
@@ -166,6 +206,7 @@ func NewFromUsernameAndDomain(username, domain string) (BEmailParts, error) {
 //	username: The username part of the email (before the '@').
 //	domainName: The domain name (e.g., "example" in "example.com").
 //	domainTLD: The top-level domain (TLD) of the email (e.g., "com" in "example.com").
+//	opts: Optional Options controlling validation, e.g. WithRFC5322().
 //
 // Returns:
 //   - A BEmailParts instance representing the constructed email.
@@ -184,17 +225,91 @@ func NewFromUsernameAndDomain(username, domain string) (BEmailParts, error) {
 //	fmt.Println(emailParts.DomainName())          // Output: example
 //	fmt.Println(emailParts.DomainTLD())           // Output: .com
 //	fmt.Println(emailParts.DomainTLDWithoutDot()) // Output: com
-func NewFromFullParts(username, domainName, domainTLD string) (BEmailParts, error) {
-	if !domainNameRegex.MatchString(domainName) {
-		return nil, ErrInvalidEmailDomainNameFormat
+func NewFromFullParts(username, domainName, domainTLD string, opts ...Option) (BEmailParts, error) {
+	return newFromFullParts(username, domainName, domainTLD, newOptions(opts))
+}
+
+func newEmailParts(email string, o *options) (BEmailParts, error) {
+	e, err := parseEmailParts(email, o)
+	if err != nil {
+		return nil, err
 	}
-	if !domainTLDRegex.MatchString(domainTLD) {
-		return nil, ErrInvalidEmailDomainTLDFormat
+	return &e, nil
+}
+
+func parseEmailParts(email string, o *options) (bEmailParts, error) {
+	e, err := splitEmailParts(email, o)
+	o.audit(email, err)
+	if err == nil && o.sampleHook != nil {
+		o.sample(e.Email())
 	}
-	return NewFromUsernameAndDomain(username, generateDomain(domainName, domainTLD))
+	return e, err
+}
+
+func splitEmailParts(email string, o *options) (bEmailParts, error) {
+	email, comments, err := o.prepareEmail(email)
+	if err != nil {
+		return bEmailParts{}, err
+	}
+	username, domain, err := o.splitEmail(email)
+	if err != nil {
+		return bEmailParts{}, o.parseError(email, err)
+	}
+
+	return bEmailParts{
+		username: username,
+		domain:   domain,
+		comments: comments,
+		opts:     o,
+	}, nil
+}
+
+// prepareEmail rewrites obsolete forms, strips comments and normalizes email as the options require, returning
+// the address to validate and the comments removed from it.
+func (o *options) prepareEmail(email string) (string, []string, error) {
+	var comments []string
+	if modern, obsoleteComments, ok := o.normalizeObsolete(email); ok {
+		email, comments = modern, obsoleteComments
+	} else if o.comments {
+		var err error
+		var stripped string
+		if stripped, comments, err = stripCFWS(email); err != nil {
+			return "", nil, o.parseError(email, err)
+		}
+		email = stripped
+	}
+	return o.normalize(email), comments, nil
+}
+
+func newFromUsernameAndDomain(username, domain string, o *options) (BEmailParts, error) {
+	if err := o.validateUsername(o.normalize(username)); err != nil {
+		o.auditParts(username, domain, err)
+		return nil, newEmailError(FieldUsername, username, err)
+	}
+	if err := o.validateDomain(o.normalize(domain)); err != nil {
+		o.auditParts(username, domain, err)
+		return nil, newEmailError(FieldDomain, domain, err)
+	}
+	return newEmailParts(generateEmail(username, domain), o)
+}
+
+func newFromFullParts(username, domainName, domainTLD string, o *options) (BEmailParts, error) {
+	if err := o.validateDomainName(o.normalize(domainName)); err != nil {
+		o.auditParts(username, generateDomain(domainName, domainTLD), err)
+		return nil, newEmailError(FieldDomainName, domainName, err)
+	}
+	if err := o.validateDomainTLD(o.normalize(domainTLD)); err != nil {
+		o.auditParts(username, generateDomain(domainName, domainTLD), err)
+		return nil, newEmailError(FieldTLD, domainTLD, err)
+	}
+	return newFromUsernameAndDomain(username, generateDomain(domainName, domainTLD), o)
 }
 
 func (e *bEmailParts) Email() string {
+	if e.domain == "" {
+		// Only the domainless "Postmaster" recipient of ParseRcpt has no domain.
+		return e.username
+	}
 	return generateEmail(e.username, e.domain)
 }
 
@@ -207,11 +322,13 @@ func (e *bEmailParts) Domain() string {
 }
 
 func (e *bEmailParts) DomainName() string {
-	return e.domain[:strings.Index(e.domain, domainSeparator)]
+	domainName, _ := splitDomain(e.domain)
+	return domainName
 }
 
 func (e *bEmailParts) DomainTLD() string {
-	return e.domain[strings.Index(e.domain, domainSeparator):]
+	_, domainTLD := splitDomain(e.domain)
+	return domainTLD
 }
 
 func (e *bEmailParts) DomainTLDWithoutDot() string {
@@ -219,34 +336,70 @@ func (e *bEmailParts) DomainTLDWithoutDot() string {
 }
 
 func (e *bEmailParts) SetUsername(username string) error {
-	if !usernameRegex.MatchString(username) {
-		return ErrInvalidEmailUsernameFormat
+	username = e.opts.normalize(username)
+	err := e.opts.validateUsername(username)
+	if err == nil {
+		err = validateEmailLength(username, e.domain)
 	}
-	e.username = username
+	e.opts.auditParts(username, e.domain, err)
+	if err != nil {
+		return newEmailError(FieldUsername, username, err)
+	}
+	e.update(FieldUsername, username, e.domain)
 	return nil
 }
 
 func (e *bEmailParts) SetDomain(domain string) error {
-	if !domainRegex.MatchString(domain) {
-		return ErrInvalidEmailDomainFormat
+	return e.setDomain(FieldDomain, domain)
+}
+
+// setDomain sets the domain, recording the change as a change of field.
+func (e *bEmailParts) setDomain(field Field, domain string) error {
+	domain = e.opts.normalize(domain)
+	err := e.opts.validateDomain(domain)
+	if err == nil {
+		err = validateEmailLength(e.username, domain)
+	}
+	e.opts.auditParts(e.username, domain, err)
+	if err != nil {
+		return newEmailError(FieldDomain, domain, err)
 	}
-	e.domain = domain
+	e.update(field, e.username, domain)
 	return nil
 }
 
 func (e *bEmailParts) SetDomainName(domainName string) error {
-	if !domainNameRegex.MatchString(domainName) {
-		return ErrInvalidEmailDomainNameFormat
+	if err := e.opts.validateDomainName(domainName); err != nil {
+		e.opts.auditParts(e.username, generateDomain(domainName, e.DomainTLD()), err)
+		return newEmailError(FieldDomainName, domainName, err)
 	}
-	e.domain = generateDomain(domainName, e.DomainTLD())
-	return nil
+	return e.setDomain(FieldDomainName, generateDomain(domainName, e.DomainTLD()))
 }
 
 func (e *bEmailParts) SetDomainTLD(domainTLD string) error {
-	if !domainTLDRegex.MatchString(domainTLD) {
-		return ErrInvalidEmailDomainTLDFormat
+	if err := e.opts.validateDomainTLD(domainTLD); err != nil {
+		e.opts.auditParts(e.username, generateDomain(e.DomainName(), domainTLD), err)
+		return newEmailError(FieldTLD, domainTLD, err)
+	}
+	return e.setDomain(FieldTLD, generateDomain(e.DomainName(), domainTLD))
+}
+
+func (e *bEmailParts) Comments() []string {
+	if e.comments == nil {
+		return nil
+	}
+	return append([]string(nil), e.comments...)
+}
+
+func (e *bEmailParts) DisplayName() string {
+	return e.displayName
+}
+
+func (e *bEmailParts) SetDisplayName(displayName string) error {
+	if !isValidDisplayName(displayName) {
+		return newEmailError(FieldDisplayName, displayName, ErrInvalidDisplayName)
 	}
-	e.domain = generateDomain(e.DomainName(), domainTLD)
+	e.displayName = displayName
 	return nil
 }
 
@@ -254,13 +407,51 @@ func (e *bEmailParts) String() string {
 	return e.Email()
 }
 
+// DomainLabels returns the dot-separated labels of the domain of e, in order, e.g. to match
+// "*.corp.example.com" or count labels. The result is a new slice; modifying it does not affect e. Returns
+// nil for a domain literal such as "[192.0.2.1]".
+//
+// Example:
+//
+//	e, err := New("john.doe@mail.example.com")
+//	if err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	fmt.Println(DomainLabels(e)) // Output: [mail example com]
+func DomainLabels(e BEmailParts) []string {
+	domain := e.Domain()
+	if isAddressLiteral(domain) {
+		return nil
+	}
+	return strings.Split(domain, domainSeparator)
+}
+
+// generateEmail and generateDomain use plain concatenation, which allocates the result once at its exact
+// size; they are on the hot path when formatting large numbers of addresses.
 func generateEmail(username, domain string) string {
-	return fmt.Sprintf("%s%s%s", username, emailSeparator, domain)
+	return username + emailSeparator + domain
+}
+
+// splitDomain splits domain before its public suffix into the domain name and the TLD (with its leading
+// dot), e.g. "mail.example" and ".co.uk" for "mail.example.co.uk". A domain without a dot has no TLD, and a
+// domain literal such as "[192.0.2.1]" has neither.
+func splitDomain(domain string) (string, string) {
+	if strings.HasPrefix(domain, "[") {
+		return "", ""
+	}
+	if !strings.Contains(domain, domainSeparator) {
+		return domain, ""
+	}
+	return splitPublicSuffix(domain)
 }
 
 func generateDomain(domainName, domainTLD string) string {
+	if domainTLD == "" {
+		return domainName
+	}
 	if !strings.HasPrefix(domainTLD, domainSeparator) {
 		domainTLD = domainSeparator + domainTLD
 	}
-	return fmt.Sprintf("%s%s", domainName, domainTLD)
+	return domainName + domainTLD
 }
@@ -1,7 +1,10 @@
 package bemailparts_test
 
 import (
+	"errors"
 	"github.com/bearaujus/bemailparts"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -291,3 +294,270 @@ func TestNewFromFullParts(t *testing.T) {
 		})
 	}
 }
+
+func TestWholeStringValidation(t *testing.T) {
+	emails := []string{
+		"good@example.com<script>",
+		"<script>good@example.com",
+		" good@example.com",
+		"good@example.com ",
+		"good@example.com\n",
+		"go od@example.com",
+		"good@exa mple.com",
+		"good@@example.com",
+		"good@bad@example.com",
+	}
+	for _, email := range emails {
+		t.Run("new "+email, func(t *testing.T) {
+			if _, err := bemailparts.New(email); err == nil {
+				t.Errorf("expecting an error on New(%q) but got nil", email)
+			}
+		})
+	}
+
+	e, err := bemailparts.New("test.username@test-domain.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setters := []struct {
+		name string
+		set  func(string) error
+		args []string
+	}{
+		{
+			name: "SetUsername",
+			set:  e.SetUsername,
+			args: []string{"john<script>", "<script>john", "jo hn", "john\n", "john@doe"},
+		},
+		{
+			name: "SetDomain",
+			set:  e.SetDomain,
+			args: []string{"example.com<script>", "<script>example.com", "exa mple.com", "example.com\n", "a@example.com"},
+		},
+		{
+			name: "SetDomainName",
+			set:  e.SetDomainName,
+			args: []string{"example<script>", "<script>example", "exa mple", "example\n", "exa@mple"},
+		},
+		{
+			name: "SetDomainTLD",
+			set:  e.SetDomainTLD,
+			args: []string{"123abc", "com<script>", "c om", "com\n", "co@m"},
+		},
+	}
+	for _, tt := range setters {
+		for _, arg := range tt.args {
+			t.Run(tt.name+" "+arg, func(t *testing.T) {
+				if err := tt.set(arg); err == nil {
+					t.Errorf("expecting an error on %s(%q) but got nil", tt.name, arg)
+				}
+			})
+		}
+	}
+	if e.Email() != "test.username@test-domain.com" {
+		t.Errorf("Email() got = %v after rejected setters, want %v", e.Email(), "test.username@test-domain.com")
+	}
+
+	for _, tt := range []struct {
+		name             string
+		username, domain string
+	}{
+		{name: "username suffix junk", username: "john<script>", domain: "example.com"},
+		{name: "domain prefix junk", username: "john", domain: "<script>example.com"},
+	} {
+		t.Run("new from username and domain "+tt.name, func(t *testing.T) {
+			if _, err := bemailparts.NewFromUsernameAndDomain(tt.username, tt.domain); err == nil {
+				t.Error("expecting an error on NewFromUsernameAndDomain() but got nil")
+			}
+		})
+	}
+	if _, err = bemailparts.NewFromFullParts("john", "exa mple", "com"); err == nil {
+		t.Error("expecting an error on NewFromFullParts() but got nil")
+	}
+}
+
+func TestDomainLabels(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		opts    []bemailparts.Option
+		wantErr bool
+	}{
+		{name: "success hyphen inside label", email: "john@my-domain.co.id"},
+		{name: "success 63 character label", email: "john@" + strings.Repeat("a", 63) + ".com"},
+		{name: "error empty label", email: "john@a..b.com", wantErr: true},
+		{name: "error leading dot", email: "john@.example.com", wantErr: true},
+		{name: "error leading hyphen", email: "john@-foo.com", wantErr: true},
+		{name: "error trailing hyphen", email: "john@foo-.com", wantErr: true},
+		{name: "error trailing hyphen in tld label", email: "john@foo.co-.id", wantErr: true},
+		{name: "error 64 character label", email: "john@" + strings.Repeat("a", 64) + ".com", wantErr: true},
+		{
+			name:    "error 64 character label with rfc5321",
+			email:   "john@" + strings.Repeat("a", 64) + ".com",
+			opts:    []bemailparts.Option{bemailparts.WithRFC5321()},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := bemailparts.New(tt.email, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	e, err := bemailparts.New("john@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = e.SetDomainName("-example"); err == nil {
+		t.Error("expecting an error on SetDomainName() but got nil")
+	}
+	if err = e.SetDomainName("mail..example"); err == nil {
+		t.Error("expecting an error on SetDomainName() but got nil")
+	}
+	if err = e.SetDomainTLD(".co-.id"); err == nil {
+		t.Error("expecting an error on SetDomainTLD() but got nil")
+	}
+	if _, err = bemailparts.NewFromFullParts("john", "example-", "com"); err == nil {
+		t.Error("expecting an error on NewFromFullParts() but got nil")
+	}
+
+	t.Run("test accessor", func(t *testing.T) {
+		accessorTests := []struct {
+			email string
+			opts  []bemailparts.Option
+			want  []string
+		}{
+			{email: "john@mail.example.com", want: []string{"mail", "example", "com"}},
+			{email: "john@Example.CO.ID", want: []string{"Example", "CO", "ID"}},
+			{email: "john@localhost", opts: []bemailparts.Option{bemailparts.WithAllowSingleLabelDomain()}, want: []string{"localhost"}},
+			{email: "john@[192.0.2.1]", opts: []bemailparts.Option{bemailparts.WithAllowIPDomain()}, want: nil},
+		}
+		for _, tt := range accessorTests {
+			e, err := bemailparts.New(tt.email, tt.opts...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			got := bemailparts.DomainLabels(e)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DomainLabels() got = %q, want %q", got, tt.want)
+			}
+			if len(got) > 0 {
+				got[0] = "changed"
+				if bemailparts.DomainLabels(e)[0] == "changed" {
+					t.Error("DomainLabels() result aliases the email")
+				}
+			}
+		}
+	})
+}
+
+func TestLengthLimits(t *testing.T) {
+	// label63 is a maximal DNS label; four of them joined by dots make a 255 octet domain.
+	label63 := strings.Repeat("a", 63)
+	tests := []struct {
+		name    string
+		email   string
+		opts    []bemailparts.Option
+		wantErr error
+	}{
+		{
+			name:  "success 64 octet username",
+			email: strings.Repeat("a", 64) + "@example.com",
+		},
+		{
+			name:  "success 254 octet email",
+			email: strings.Repeat("a", 64) + "@" + label63 + "." + label63 + "." + strings.Repeat("a", 57) + ".com",
+		},
+		{
+			name:    "error 65 octet username",
+			email:   strings.Repeat("a", 65) + "@example.com",
+			wantErr: bemailparts.ErrEmailUsernameTooLong,
+		},
+		{
+			name:    "error 65 octet username with rfc5322",
+			email:   strings.Repeat("a", 65) + "@example.com",
+			opts:    []bemailparts.Option{bemailparts.WithRFC5322()},
+			wantErr: bemailparts.ErrEmailUsernameTooLong,
+		},
+		{
+			name:    "error 65 octet username with html5",
+			email:   strings.Repeat("a", 65) + "@example.com",
+			opts:    []bemailparts.Option{bemailparts.WithHTML5Validation()},
+			wantErr: bemailparts.ErrEmailUsernameTooLong,
+		},
+		{
+			name:    "error 256 octet domain",
+			email:   "a@" + strings.Repeat(label63+".", 4) + "com",
+			wantErr: bemailparts.ErrEmailDomainTooLong,
+		},
+		{
+			name:    "error 255 octet email",
+			email:   strings.Repeat("a", 64) + "@" + label63 + "." + label63 + "." + strings.Repeat("a", 58) + ".com",
+			wantErr: bemailparts.ErrEmailTooLong,
+		},
+		{
+			name:    "error kilometer long email",
+			email:   strings.Repeat("a", 1000) + "@example.com",
+			wantErr: bemailparts.ErrEmailUsernameTooLong,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := bemailparts.New(tt.email, tt.opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("test setters keep the address within 254 octets", func(t *testing.T) {
+		e, err := bemailparts.New("john@" + label63 + "." + label63 + "." + label63 + ".com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = e.SetUsername(strings.Repeat("a", 64)); !errors.Is(err, bemailparts.ErrEmailTooLong) {
+			t.Errorf("SetUsername() error = %v, want %v", err, bemailparts.ErrEmailTooLong)
+		}
+		if e.Username() != "john" {
+			t.Errorf("Username() got = %v after rejected setter, want john", e.Username())
+		}
+
+		e, err = bemailparts.New(strings.Repeat("a", 64) + "@example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = e.SetDomain(label63 + "." + label63 + "." + label63 + ".com"); !errors.Is(err, bemailparts.ErrEmailTooLong) {
+			t.Errorf("SetDomain() error = %v, want %v", err, bemailparts.ErrEmailTooLong)
+		}
+		if e.Domain() != "example.com" {
+			t.Errorf("Domain() got = %v after rejected setter, want example.com", e.Domain())
+		}
+	})
+}
+
+func BenchmarkEmail(b *testing.B) {
+	e, err := bemailparts.New("test.username@test-domain.com")
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = e.Email()
+	}
+}
+
+func BenchmarkSetDomainTLD(b *testing.B) {
+	e, err := bemailparts.New("test.username@test-domain.com")
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err = e.SetDomainTLD("co.id"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package bemailparts
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BounceType classifies a bounce reported for an address.
+type BounceType int
+
+const (
+	// SoftBounce is a temporary delivery failure, such as a full mailbox or a greylisting response.
+	SoftBounce BounceType = iota + 1
+
+	// HardBounce is a permanent delivery failure, such as an unknown mailbox.
+	HardBounce
+)
+
+// BounceDecision is the action a BouncePolicy recommends for an address.
+type BounceDecision int
+
+const (
+	// BounceKeep means the address can be mailed normally.
+	BounceKeep BounceDecision = iota
+
+	// BounceRetry means the address recently soft-bounced and mail should be retried later.
+	BounceRetry
+
+	// BounceSuppress means the address crossed a threshold and should no longer be mailed.
+	BounceSuppress
+)
+
+// String returns the lowercase name of the decision, e.g. "suppress".
+func (d BounceDecision) String() string {
+	switch d {
+	case BounceKeep:
+		return "keep"
+	case BounceRetry:
+		return "retry"
+	case BounceSuppress:
+		return "suppress"
+	default:
+		return "unknown"
+	}
+}
+
+// BounceThresholds configures when a BouncePolicy suppresses an address.
+type BounceThresholds struct {
+	// SoftBounces is the number of soft bounces within SoftBounceWindow that suppresses an address.
+	SoftBounces int
+
+	// SoftBounceWindow is how far back soft bounces are counted.
+	SoftBounceWindow time.Duration
+
+	// HardBounces is the number of hard bounces that suppresses an address.
+	HardBounces int
+}
+
+// DefaultBounceThresholds suppresses an address after 3 soft bounces in 7 days or a single hard bounce.
+var DefaultBounceThresholds = BounceThresholds{
+	SoftBounces:      3,
+	SoftBounceWindow: 7 * 24 * time.Hour,
+	HardBounces:      1,
+}
+
+// bounceKeyPrefix prefixes the Store keys of a BouncePolicy.
+const bounceKeyPrefix = "bounce/"
+
+// BouncePolicy ingests bounce classifications per address over time and decides whether each address
+// should be kept, retried, or suppressed.
+//
+// A BouncePolicy is safe for concurrent use. Processes sharing a Store should not record bounces for the same
+// address concurrently, as Record reads and then rewrites the history of the address.
+type BouncePolicy struct {
+	mu         sync.Mutex
+	thresholds BounceThresholds
+	store      Store
+}
+
+type bounceHistory struct {
+	soft []time.Time
+	hard int
+}
+
+// NewBouncePolicy creates a BouncePolicy applying the given thresholds, keeping bounces in memory.
+//
+// Example:
+//
+//	policy := NewBouncePolicy(DefaultBounceThresholds)
+//	decision, err := policy.Record("john.doe@example.com", SoftBounce, time.Now())
+//	if err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	fmt.Println(decision) // Output: retry
+func NewBouncePolicy(thresholds BounceThresholds) *BouncePolicy {
+	return NewBouncePolicyWithStore(thresholds, NewMemoryStore())
+}
+
+// NewBouncePolicyWithStore creates a BouncePolicy applying the given thresholds, keeping bounces in store
+// and using those already recorded there. If store fails, Decide suppresses addresses, so that they are not
+// mailed by mistake.
+//
+// Example:
+//
+//	policy := NewBouncePolicyWithStore(DefaultBounceThresholds, store)
+//	fmt.Println(policy.Decide("john.doe@example.com", time.Now())) // Output: the decision for the bounces in store
+func NewBouncePolicyWithStore(thresholds BounceThresholds, store Store) *BouncePolicy {
+	return &BouncePolicy{thresholds: thresholds, store: store}
+}
+
+// Record registers a bounce of the given type for email at time at and returns the resulting decision. Soft
+// bounces that fell out of the window at time at are forgotten.
+// Returns an error if email is invalid or the Store fails.
+func (p *BouncePolicy) Record(email string, typ BounceType, at time.Time) (BounceDecision, error) {
+	e, err := New(email)
+	if err != nil {
+		return BounceKeep, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	key := bounceKey(e.Email())
+	h, err := p.load(key)
+	if err != nil {
+		return BounceSuppress, err
+	}
+	switch typ {
+	case SoftBounce:
+		h.soft = append(h.soft, at)
+	case HardBounce:
+		h.hard++
+	}
+	h.soft = p.recentSoft(h, at)
+	if err = p.store.Put(key, h.encode(e.Email()), 0); err != nil {
+		return BounceSuppress, err
+	}
+	return p.decide(h, at), nil
+}
+
+// Decide returns the decision for email at time at, based on the bounces recorded so far. It does not change
+// them, so it can be called for any time, e.g. to preview a future decision.
+func (p *BouncePolicy) Decide(email string, at time.Time) BounceDecision {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	h, err := p.load(bounceKey(email))
+	if err != nil {
+		return BounceSuppress
+	}
+	return p.decide(h, at)
+}
+
+// Reset forgets every bounce recorded for email, e.g. after the recipient confirmed the address again.
+// Returns an error if the Store fails.
+func (p *BouncePolicy) Reset(email string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.store.Delete(bounceKey(email))
+}
+
+// load returns the history stored at key, which is empty if there is none.
+func (p *BouncePolicy) load(key string) (*bounceHistory, error) {
+	value, ok, err := p.store.Get(key)
+	if err != nil || !ok {
+		return &bounceHistory{}, err
+	}
+	return parseBounceHistory(value), nil
+}
+
+func (p *BouncePolicy) decide(h *bounceHistory, at time.Time) BounceDecision {
+	if p.thresholds.HardBounces > 0 && h.hard >= p.thresholds.HardBounces {
+		return BounceSuppress
+	}
+
+	recent := len(p.recentSoft(h, at))
+	if p.thresholds.SoftBounces > 0 && recent >= p.thresholds.SoftBounces {
+		return BounceSuppress
+	}
+	if recent > 0 {
+		return BounceRetry
+	}
+	return BounceKeep
+}
+
+// recentSoft returns the soft bounces of h within the window at time at, in a new slice.
+func (p *BouncePolicy) recentSoft(h *bounceHistory, at time.Time) []time.Time {
+	since := at.Add(-p.thresholds.SoftBounceWindow)
+	var recent []time.Time
+	for _, t := range h.soft {
+		if t.After(since) {
+			recent = append(recent, t)
+		}
+	}
+	return recent
+}
+
+func bounceKey(email string) string {
+	return bounceKeyPrefix + strings.ToLower(email)
+}
+
+// encode returns the Store value of h for email: the number of hard bounces and the times of the soft
+// bounces in Unix nanoseconds, separated by commas, followed by the address.
+func (h *bounceHistory) encode(email string) []byte {
+	fields := make([]string, 0, len(h.soft)+1)
+	fields = append(fields, strconv.Itoa(h.hard))
+	for _, t := range h.soft {
+		fields = append(fields, strconv.FormatInt(t.UnixNano(), 10))
+	}
+	return []byte(strings.Join(fields, ",") + "\n" + email)
+}
+
+// parseBounceHistory returns the history encoded by encode.
+func parseBounceHistory(value []byte) *bounceHistory {
+	metadata, _ := splitStoreValue(value)
+	fields := strings.Split(metadata, ",")
+	h := &bounceHistory{}
+	h.hard, _ = strconv.Atoi(fields[0])
+	for _, field := range fields[1:] {
+		nanos, _ := strconv.ParseInt(field, 10, 64)
+		h.soft = append(h.soft, time.Unix(0, nanos))
+	}
+	return h
+}
@@ -0,0 +1,85 @@
+package bemailparts_test
+
+import (
+	"github.com/bearaujus/bemailparts"
+	"testing"
+	"time"
+)
+
+func TestBouncePolicy(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	type event struct {
+		typ    bemailparts.BounceType
+		offset time.Duration
+		want   bemailparts.BounceDecision
+	}
+	tests := []struct {
+		name   string
+		events []event
+	}{
+		{
+			name: "soft bounces within window suppress",
+			events: []event{
+				{typ: bemailparts.SoftBounce, offset: 0, want: bemailparts.BounceRetry},
+				{typ: bemailparts.SoftBounce, offset: 2 * day, want: bemailparts.BounceRetry},
+				{typ: bemailparts.SoftBounce, offset: 4 * day, want: bemailparts.BounceSuppress},
+			},
+		},
+		{
+			name: "soft bounces outside window retry",
+			events: []event{
+				{typ: bemailparts.SoftBounce, offset: 0, want: bemailparts.BounceRetry},
+				{typ: bemailparts.SoftBounce, offset: 5 * day, want: bemailparts.BounceRetry},
+				{typ: bemailparts.SoftBounce, offset: 10 * day, want: bemailparts.BounceRetry},
+			},
+		},
+		{
+			name: "hard bounce suppresses",
+			events: []event{
+				{typ: bemailparts.HardBounce, offset: 0, want: bemailparts.BounceSuppress},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := bemailparts.NewBouncePolicy(bemailparts.DefaultBounceThresholds)
+			for i, ev := range tt.events {
+				got, err := policy.Record("john.doe@example.com", ev.typ, start.Add(ev.offset))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got != ev.want {
+					t.Errorf("Record() event %d got = %v, want %v", i, got, ev.want)
+				}
+			}
+		})
+	}
+
+	t.Run("test decide and reset", func(t *testing.T) {
+		policy := bemailparts.NewBouncePolicy(bemailparts.DefaultBounceThresholds)
+		if _, err := policy.Record("john.doe@example.com", bemailparts.SoftBounce, start); err != nil {
+			t.Fatal(err)
+		}
+		if got := policy.Decide("John.Doe@example.com", start.Add(day)); got != bemailparts.BounceRetry {
+			t.Errorf("Decide() got = %v, want %v", got, bemailparts.BounceRetry)
+		}
+		if got := policy.Decide("john.doe@example.com", start.Add(8*day)); got != bemailparts.BounceKeep {
+			t.Errorf("Decide() got = %v, want %v", got, bemailparts.BounceKeep)
+		}
+		// Deciding for a later time must not forget the bounces still recent at earlier times.
+		if got := policy.Decide("john.doe@example.com", start.Add(day)); got != bemailparts.BounceRetry {
+			t.Errorf("Decide() after a later Decide() got = %v, want %v", got, bemailparts.BounceRetry)
+		}
+
+		if err := policy.Reset("john.doe@example.com"); err != nil {
+			t.Fatal(err)
+		}
+		if got := policy.Decide("john.doe@example.com", start); got != bemailparts.BounceKeep {
+			t.Errorf("Decide() after Reset() got = %v, want %v", got, bemailparts.BounceKeep)
+		}
+		if _, err := policy.Record("!@#!@#", bemailparts.HardBounce, start); err == nil {
+			t.Error("expecting an error on Record() but got nil")
+		}
+	})
+}
@@ -0,0 +1,42 @@
+package bemailparts
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// CanonicalJSON returns a deterministic JSON encoding of the parts of e and the policy version passed to
+// WithAuditLog: every key is present, keys are sorted, there is no insignificant whitespace and HTML
+// characters such as '<' are not escaped. Equal addresses parsed under the same policy encode to the same
+// bytes in every service, so audit systems can hash and compare them.
+//
+// Example:
+//
+//	e, err := New("john.doe@example.com")
+//	if err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	fmt.Println(string(CanonicalJSON(e)))
+//	// Output: {"display_name":"","domain":"example.com","domain_name":"example","email":"john.doe@example.com",
+//	// "policy_version":"","registrable_domain":"example.com","subdomain":"","tld":"com","username":"john.doe"}
+//	// without line breaks.
+func CanonicalJSON(e BEmailParts) []byte {
+	// encoding/json writes the keys of a map in sorted order.
+	parts := map[string]string{
+		"display_name":       e.DisplayName(),
+		"domain":             e.Domain(),
+		"domain_name":        e.DomainName(),
+		"email":              e.Email(),
+		"policy_version":     optionsOf(e).policyVersion,
+		"registrable_domain": registrableDomain(e.Domain()),
+		"subdomain":          e.Subdomain(),
+		"tld":                e.DomainTLDWithoutDot(),
+		"username":           e.Username(),
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(parts) // Encoding strings cannot fail.
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+}
@@ -0,0 +1,81 @@
+package bemailparts_test
+
+import (
+	"encoding/json"
+	"github.com/bearaujus/bemailparts"
+	"testing"
+)
+
+func TestCanonicalJSON(t *testing.T) {
+	audit := bemailparts.WithAuditLog(bemailparts.AuditSinkFunc(func(bemailparts.AuditRecord) {}), "2024-06-01", []byte("audit-key"))
+
+	tests := []struct {
+		name string
+		new  func() (bemailparts.BEmailParts, error)
+		want string
+	}{
+		{
+			name: "without policy",
+			new:  func() (bemailparts.BEmailParts, error) { return bemailparts.New("john.doe@example.com") },
+			want: `{"display_name":"","domain":"example.com","domain_name":"example","email":"john.doe@example.com",` +
+				`"policy_version":"","registrable_domain":"example.com","subdomain":"","tld":"com","username":"john.doe"}`,
+		},
+		{
+			name: "with policy",
+			new: func() (bemailparts.BEmailParts, error) {
+				return bemailparts.New("john.doe@mail.example.co.uk", audit)
+			},
+			want: `{"display_name":"","domain":"mail.example.co.uk","domain_name":"mail.example",` +
+				`"email":"john.doe@mail.example.co.uk","policy_version":"2024-06-01",` +
+				`"registrable_domain":"example.co.uk","subdomain":"mail","tld":"co.uk","username":"john.doe"}`,
+		},
+		{
+			name: "display name is not escaped for html",
+			new: func() (bemailparts.BEmailParts, error) {
+				return bemailparts.ParseAddress(`"Smith & <Co>" <j@example.com>`)
+			},
+			want: `{"display_name":"Smith & <Co>","domain":"example.com","domain_name":"example","email":"j@example.com",` +
+				`"policy_version":"","registrable_domain":"example.com","subdomain":"","tld":"com","username":"j"}`,
+		},
+		{
+			name: "unicode",
+			new: func() (bemailparts.BEmailParts, error) {
+				return bemailparts.New("ivan@пример.рф", bemailparts.WithIDN())
+			},
+			want: `{"display_name":"","domain":"пример.рф","domain_name":"пример","email":"ivan@пример.рф",` +
+				`"policy_version":"","registrable_domain":"пример.рф","subdomain":"","tld":"рф","username":"ivan"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := tt.new()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := bemailparts.CanonicalJSON(e)
+			if string(got) != tt.want {
+				t.Errorf("CanonicalJSON() = %s, want %s", got, tt.want)
+			}
+			if !json.Valid(got) {
+				t.Errorf("CanonicalJSON() = %s, want valid JSON", got)
+			}
+		})
+	}
+
+	t.Run("test equal addresses encode equally", func(t *testing.T) {
+		e1, err := bemailparts.New("john.doe@example.com", audit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e2, err := bemailparts.New("jane.doe@example.com", audit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = e2.SetUsername("john.doe"); err != nil {
+			t.Fatal(err)
+		}
+		if string(bemailparts.CanonicalJSON(e1)) != string(bemailparts.CanonicalJSON(e2)) {
+			t.Errorf("CanonicalJSON() = %s and %s, want equal", bemailparts.CanonicalJSON(e1), bemailparts.CanonicalJSON(e2))
+		}
+	})
+}
@@ -0,0 +1,134 @@
+package bemailparts
+
+import "fmt"
+
+// stripCFWS removes RFC 5322 comments and folding whitespace from email, which may appear before and after
+// the username and the domain, e.g. "(home) john.doe (work) @ example.com". It returns the bare address and
+// the text of every top-level comment in order. Comments elsewhere, e.g. inside the username, are invalid.
+// Errors are *cfwsErrors locating the problem in email.
+func stripCFWS(email string) (string, []string, error) {
+	var comments []string
+	i, err := skipCFWS(email, 0, &comments)
+	if err != nil {
+		return "", nil, inPart(err, PartLocal)
+	}
+
+	start := i
+	if i < len(email) && email[i] == '"' {
+		if i = scanQuotedString(email, i); i < 0 {
+			return "", nil, &cfwsError{part: PartLocal, offset: start, msg: "unterminated quoted string"}
+		}
+	} else {
+		i = scanUntilCFWS(email, i, emailSeparator[0])
+	}
+	username := email[start:i]
+
+	if i, err = skipCFWS(email, i, &comments); err != nil {
+		return "", nil, inPart(err, PartLocal)
+	}
+	if i >= len(email) {
+		return "", nil, &cfwsError{offset: i, msg: "missing '@'"}
+	}
+	if email[i] != emailSeparator[0] {
+		return "", nil, &cfwsError{part: PartLocal, offset: i, msg: fmt.Sprintf("invalid character %q", email[i])}
+	}
+	if i, err = skipCFWS(email, i+1, &comments); err != nil {
+		return "", nil, inPart(err, PartDomain)
+	}
+
+	start = i
+	if i < len(email) && email[i] == '[' {
+		if i = scanDomainLiteral(email, i); i < 0 {
+			return "", nil, &cfwsError{part: PartDomain, offset: start, msg: "unterminated address literal"}
+		}
+	} else {
+		i = scanUntilCFWS(email, i, 0)
+	}
+	domain := email[start:i]
+
+	if i, err = skipCFWS(email, i, &comments); err != nil {
+		return "", nil, inPart(err, PartDomain)
+	}
+	if i != len(email) {
+		return "", nil, &cfwsError{part: PartDomain, offset: i, msg: fmt.Sprintf("invalid character %q", email[i])}
+	}
+	return generateEmail(username, domain), comments, nil
+}
+
+// cfwsError is a problem found by stripCFWS at offset in the address, in part, which is empty if the problem
+// concerns the whole address. It unwraps to ErrInvalidEmailFormat.
+type cfwsError struct {
+	part   string
+	offset int
+	msg    string
+}
+
+func (e *cfwsError) Error() string {
+	return ErrInvalidEmailFormat.Error()
+}
+
+func (e *cfwsError) Unwrap() error {
+	return ErrInvalidEmailFormat
+}
+
+// inPart returns err, a *cfwsError, as found in part.
+func inPart(err error, part string) error {
+	if e, ok := err.(*cfwsError); ok {
+		e.part = part
+	}
+	return err
+}
+
+// skipCFWS skips whitespace and comments starting at s[i], appending the comments to comments.
+// It returns the index of the first character after them, or a *cfwsError.
+func skipCFWS(s string, i int, comments *[]string) (int, error) {
+	for i < len(s) {
+		switch s[i] {
+		case ' ', '\t', '\r', '\n':
+			i++
+		case '(':
+			end := scanComment(s, i)
+			if end < 0 {
+				return 0, &cfwsError{offset: i, msg: "unterminated comment"}
+			}
+			*comments = append(*comments, s[i+1:end-1])
+			i = end
+		default:
+			return i, nil
+		}
+	}
+	return i, nil
+}
+
+// scanComment returns the index just past the possibly nested comment starting at s[i], or -1 if it is not
+// terminated.
+func scanComment(s string, i int) int {
+	depth := 0
+	for ; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return -1
+}
+
+// scanUntilCFWS returns the index of the first whitespace, comment, or stop character at or after s[i].
+// A stop of 0 disables the stop character.
+func scanUntilCFWS(s string, i int, stop byte) int {
+	for ; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == ' ', c == '\t', c == '\r', c == '\n', c == '(':
+			return i
+		case stop != 0 && c == stop:
+			return i
+		}
+	}
+	return i
+}
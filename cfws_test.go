@@ -0,0 +1,95 @@
+package bemailparts_test
+
+import (
+	"github.com/bearaujus/bemailparts"
+	"reflect"
+	"testing"
+)
+
+func TestNewWithComments(t *testing.T) {
+	tests := []struct {
+		name         string
+		email        string
+		opts         []bemailparts.Option
+		want         string
+		wantComments []string
+		wantErr      bool
+	}{
+		{
+			name:         "comment after username",
+			email:        "john.doe(work)@example.com",
+			want:         "john.doe@example.com",
+			wantComments: []string{"work"},
+		},
+		{
+			name:         "comment before username",
+			email:        "(comment)john@example.com",
+			want:         "john@example.com",
+			wantComments: []string{"comment"},
+		},
+		{
+			name:         "comments and folding whitespace everywhere",
+			email:        " (home) john.doe (work) @ (mail) example.com (end) ",
+			want:         "john.doe@example.com",
+			wantComments: []string{"home", "work", "mail", "end"},
+		},
+		{
+			name:         "nested comment with escapes",
+			email:        `john(a (nested) \) b)@example.com`,
+			want:         "john@example.com",
+			wantComments: []string{`a (nested) \) b`},
+		},
+		{
+			name:  "no comments",
+			email: "john@example.com",
+			want:  "john@example.com",
+		},
+		{
+			name:         "quoted username keeps parentheses",
+			email:        `"john (not a comment)"(comment)@example.com`,
+			opts:         []bemailparts.Option{bemailparts.WithRFC5322()},
+			want:         `"john (not a comment)"@example.com`,
+			wantComments: []string{"comment"},
+		},
+		{
+			name:    "comment inside username",
+			email:   "jo(x)hn@example.com",
+			wantErr: true,
+		},
+		{
+			name:    "unterminated comment",
+			email:   "john(work@example.com",
+			wantErr: true,
+		},
+		{
+			name:    "whitespace inside username",
+			email:   "john doe@example.com",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]bemailparts.Option{bemailparts.WithComments()}, tt.opts...)
+			got, err := bemailparts.New(tt.email, opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Email() != tt.want {
+				t.Errorf("New() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(got.Comments(), tt.wantComments) {
+				t.Errorf("Comments() got = %q, want %q", got.Comments(), tt.wantComments)
+			}
+		})
+	}
+
+	t.Run("test comments rejected without option", func(t *testing.T) {
+		if _, err := bemailparts.New("john.doe(work)@example.com"); err == nil {
+			t.Error("expecting an error on New() but got nil")
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package bemailparts
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Clock tells the current time. Substitute a fake Clock in tests to make time-dependent behavior, such as
+// quarantine expiry, deterministic.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function to Clock.
+type ClockFunc func() time.Time
+
+// Now calls f().
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// systemClock is the Clock used unless another one is configured.
+var systemClock Clock = ClockFunc(time.Now)
+
+// WithClock makes the options that record time, such as WithAuditLog, read it from clock. Passing nil
+// restores the system clock.
+//
+// Example:
+//
+//	fixed := ClockFunc(func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) })
+//	parser := NewParser(WithAuditLog(sink, "v1", auditKey), WithClock(fixed))
+func WithClock(clock Clock) Option {
+	return func(o *options) {
+		o.clock = clock
+	}
+}
+
+// WithRandSource makes the options that draw random numbers, such as WithSampling, draw them from src, so
+// their decisions can be reproduced with a fixed seed. Passing nil restores the default source.
+//
+// Example:
+//
+//	parser := NewParser(WithSampling(0.1, hook), WithRandSource(rand.NewSource(42)))
+func WithRandSource(src rand.Source) Option {
+	return func(o *options) {
+		o.rand = nil
+		if src != nil {
+			o.rand = &lockedRand{rand: rand.New(src)}
+		}
+	}
+}
+
+// lockedRand makes a *rand.Rand safe for concurrent use.
+type lockedRand struct {
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+func (r *lockedRand) Float64() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rand.Float64()
+}
+
+func (o *options) now() time.Time {
+	if o.clock == nil {
+		return systemClock.Now()
+	}
+	return o.clock.Now()
+}
+
+func (o *options) float64() float64 {
+	if o.rand == nil {
+		return rand.Float64()
+	}
+	return o.rand.Float64()
+}
@@ -0,0 +1,77 @@
+package bemailparts_test
+
+import (
+	"github.com/bearaujus/bemailparts"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock that only moves when told to.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestQuarantineSetClock(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	q := bemailparts.NewQuarantine(24 * time.Hour)
+	q.SetClock(clock)
+	if err := q.Add("john@example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	clock.Advance(23 * time.Hour)
+	if !q.IsQuarantined("john@example.com") || len(q.Eligible()) != 0 {
+		t.Error("address released before the quarantine period elapsed")
+	}
+
+	clock.Advance(time.Hour)
+	if q.IsQuarantined("john@example.com") {
+		t.Error("IsQuarantined() got = true, want false")
+	}
+	if got, want := q.Eligible(), []string{"john@example.com"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Eligible() got = %v, want %v", got, want)
+	}
+}
+
+func TestWithClock(t *testing.T) {
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sink := &memoryAuditSink{}
+	parser := bemailparts.NewParser(bemailparts.WithAuditLog(sink, "v1", []byte("audit-key")),
+		bemailparts.WithClock(bemailparts.ClockFunc(func() time.Time { return at })))
+	if _, err := parser.Parse("john@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if len(sink.records) != 1 || !sink.records[0].Time.Equal(at) {
+		t.Errorf("audit records got = %+v, want one at %v", sink.records, at)
+	}
+}
+
+func TestWithRandSource(t *testing.T) {
+	sample := func() []string {
+		var got []string
+		parser := bemailparts.NewParser(bemailparts.WithRandSource(rand.NewSource(42)),
+			bemailparts.WithSampling(0.5, func(email string) { got = append(got, email) }))
+		for _, email := range []string{"a@example.com", "b@example.com", "c@example.com", "d@example.com",
+			"e@example.com", "f@example.com", "g@example.com", "h@example.com"} {
+			if _, err := parser.Parse(email); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return got
+	}
+
+	first, second := sample(), sample()
+	if len(first) == 0 || !reflect.DeepEqual(first, second) {
+		t.Errorf("sampled %v then %v, want the same non-empty samples", first, second)
+	}
+}
@@ -0,0 +1,107 @@
+package bemailparts
+
+import (
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+// nameSimilarityThreshold is the Similarity from which NameMatchesLocalPart accepts a username as a spelling
+// of the name, tolerating typos.
+const nameSimilarityThreshold = 0.8
+
+// DomainMatchesWebsite reports whether the domain of email belongs to website, e.g. for checking that a
+// business signup uses an address of the company's own domain. The domain matches if it equals the host of
+// website or either is a subdomain of the other, ignoring case, a leading "www." and the port. website may
+// omit its scheme, e.g. "example.com/about".
+// Returns an error if email is invalid or website cannot be parsed.
+//
+// Example:
+//
+//	ok, err := DomainMatchesWebsite("jane@mail.example.com", "https://www.example.com/")
+//	if err != nil {
+//	    log.Fatalf("Invalid input: %v", err)
+//	}
+//
+//	fmt.Println(ok) // Output: true
+func DomainMatchesWebsite(email, website string) (bool, error) {
+	e, err := New(email)
+	if err != nil {
+		return false, err
+	}
+
+	website = strings.TrimSpace(website)
+	if !strings.Contains(website, "://") {
+		website = "http://" + website
+	}
+	u, err := url.Parse(website)
+	if err != nil {
+		return false, err
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	domain := strings.ToLower(e.Domain())
+	if host == "" || !strings.Contains(host, domainSeparator) {
+		return false, nil
+	}
+	return domain == host || strings.HasSuffix(domain, domainSeparator+host) ||
+		strings.HasSuffix(host, domainSeparator+domain), nil
+}
+
+// NameMatchesLocalPart reports whether the username of email is plausibly derived from fullName, e.g. for
+// flagging signups whose name and address disagree. Usernames are compared, ignoring case, digits,
+// separators and "+" tags, against the usual spellings of the name: first name, last name, both in either
+// order, and either with the other's initial ("jdoe", "johnd"). Spellings within a Similarity of 0.8 match,
+// to tolerate typos.
+// Returns an error if email is invalid.
+//
+// Example:
+//
+//	ok, err := NameMatchesLocalPart("j.doe84@example.com", "John Doe")
+//	if err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	fmt.Println(ok) // Output: true
+func NameMatchesLocalPart(email, fullName string) (bool, error) {
+	e, err := New(email)
+	if err != nil {
+		return false, err
+	}
+
+	username := strings.ToLower(e.Username())
+	if i := strings.IndexByte(username, '+'); i > 0 {
+		username = username[:i]
+	}
+	local := strings.Join(nameTokens(username), "")
+	names := nameTokens(strings.ToLower(fullName))
+	if local == "" || len(names) == 0 {
+		return false, nil
+	}
+
+	for _, spelling := range nameSpellings(names) {
+		if local == spelling || Similarity(local, spelling) >= nameSimilarityThreshold {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// nameTokens splits s into runs of letters.
+func nameTokens(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+}
+
+// nameSpellings returns the usual ways of writing the name made of tokens in a username.
+func nameSpellings(tokens []string) []string {
+	first, last := tokens[0], tokens[len(tokens)-1]
+	spellings := []string{first, strings.Join(tokens, "")}
+	if len(tokens) > 1 {
+		firstInitial, lastInitial := string([]rune(first)[:1]), string([]rune(last)[:1])
+		spellings = append(spellings, last, first+last, last+first, firstInitial+last, first+lastInitial,
+			last+firstInitial)
+	}
+	return spellings
+}
@@ -0,0 +1,71 @@
+package bemailparts_test
+
+import (
+	"github.com/bearaujus/bemailparts"
+	"testing"
+)
+
+func TestDomainMatchesWebsite(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		website string
+		want    bool
+		wantErr bool
+	}{
+		{name: "same domain", email: "jane@example.com", website: "https://example.com", want: true},
+		{name: "www and path", email: "jane@example.com", website: "https://www.example.com/about", want: true},
+		{name: "without scheme and with port", email: "jane@Example.com", website: "EXAMPLE.com:8080", want: true},
+		{name: "mail subdomain", email: "jane@mail.example.com", website: "example.com", want: true},
+		{name: "website subdomain", email: "jane@example.com", website: "shop.example.com", want: true},
+		{name: "different domain", email: "jane@gmail.com", website: "example.com", want: false},
+		{name: "suffix without dot", email: "jane@notexample.com", website: "example.com", want: false},
+		{name: "single label host", email: "jane@example.com", website: "http://com", want: false},
+		{name: "error invalid email", email: "invalid", website: "example.com", wantErr: true},
+		{name: "error invalid website", email: "jane@example.com", website: "http://exa mple.com", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bemailparts.DomainMatchesWebsite(tt.email, tt.website)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DomainMatchesWebsite() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("DomainMatchesWebsite() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNameMatchesLocalPart(t *testing.T) {
+	tests := []struct {
+		name     string
+		email    string
+		fullName string
+		want     bool
+		wantErr  bool
+	}{
+		{name: "first dot last", email: "john.doe@example.com", fullName: "John Doe", want: true},
+		{name: "initial and last with digits", email: "j.doe84@example.com", fullName: "John Doe", want: true},
+		{name: "last and first", email: "doe_john@example.com", fullName: "John Doe", want: true},
+		{name: "first and last initial", email: "johnd+news@example.com", fullName: "John Doe", want: true},
+		{name: "first name only", email: "john@example.com", fullName: "John Doe", want: true},
+		{name: "middle name", email: "johnquincydoe@example.com", fullName: "John Quincy Doe", want: true},
+		{name: "typo", email: "jonathan.smiht@example.com", fullName: "Jonathan Smith", want: true},
+		{name: "accented name", email: "jose.garcia@example.com", fullName: "José García", want: true},
+		{name: "unrelated", email: "alice@example.com", fullName: "John Doe", want: false},
+		{name: "no letters in name", email: "john@example.com", fullName: "12345", want: false},
+		{name: "error invalid email", email: "invalid", fullName: "John Doe", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bemailparts.NameMatchesLocalPart(tt.email, tt.fullName)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NameMatchesLocalPart() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NameMatchesLocalPart() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
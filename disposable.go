@@ -0,0 +1,76 @@
+package bemailparts
+
+import (
+	_ "embed"
+	"strings"
+	"sync"
+)
+
+// disposableDomainList lists a small hand-picked sample of the domains of disposable mailbox services, one
+// per line, after comment lines starting with '#'.
+//
+//go:embed disposable_domains.txt
+var disposableDomainList string
+
+var (
+	disposableDomainsOnce sync.Once
+	disposableDomainSet   map[string]bool
+)
+
+// loadDisposableDomains parses disposableDomainList on first use.
+func loadDisposableDomains() map[string]bool {
+	disposableDomainsOnce.Do(func() {
+		disposableDomainSet = map[string]bool{}
+		for _, line := range strings.Split(disposableDomainList, "\n") {
+			if domain := strings.TrimSpace(line); domain != "" && !strings.HasPrefix(domain, "#") {
+				disposableDomainSet[domain] = true
+			}
+		}
+	})
+	return disposableDomainSet
+}
+
+// IsDisposable reports whether the domain of e belongs to a disposable mailbox service handing out throwaway
+// addresses: it is, or is a subdomain of, a domain of the embedded list, such as "mailinator.com". The list
+// is a small hand-picked sample, so false does not mean the domain is not disposable.
+//
+// Example:
+//
+//	e, err := New("john@mailinator.com")
+//	if err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	fmt.Println(IsDisposable(e)) // Output: true
+func IsDisposable(e BEmailParts) bool {
+	return isDisposable(e.Domain())
+}
+
+// WithRejectDisposableDomains rejects addresses whose domain is disposable, as reported by IsDisposable,
+// with ErrEmailDomainDisposable, e.g. "john@mailinator.com". It also applies to the setters.
+//
+// Example:
+//
+//	_, err := New("john@mailinator.com", WithRejectDisposableDomains())
+//	fmt.Println(errors.Is(err, ErrEmailDomainDisposable)) // Output: true
+func WithRejectDisposableDomains() Option {
+	return func(o *options) {
+		o.rejectDisposable = true
+	}
+}
+
+// isDisposable reports whether domain is a disposable mailbox domain of the embedded list or a subdomain of
+// one.
+func isDisposable(domain string) bool {
+	if isAddressLiteral(domain) {
+		return false
+	}
+	labels := strings.Split(strings.ToLower(domain), domainSeparator)
+	disposable := loadDisposableDomains()
+	for i := 0; i < len(labels)-1; i++ {
+		if disposable[strings.Join(labels[i:], domainSeparator)] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package bemailparts_test
+
+import (
+	"errors"
+	"github.com/bearaujus/bemailparts"
+	"testing"
+)
+
+func TestIsDisposable(t *testing.T) {
+	tests := []struct {
+		name  string
+		email string
+		opts  []bemailparts.Option
+		want  bool
+	}{
+		{name: "listed domain", email: "john@mailinator.com", want: true},
+		{name: "subdomain of a listed domain", email: "john@inbox.mailinator.com", want: true},
+		{name: "case is ignored", email: "john@YopMail.com", want: true},
+		{name: "free provider", email: "john@gmail.com"},
+		{name: "listed domain as a subdomain", email: "john@mailinator.com.example.com"},
+		{name: "name of a listed domain", email: "john@mailinator.de"},
+		{
+			name:  "address literal",
+			email: "john@[192.0.2.1]",
+			opts:  []bemailparts.Option{bemailparts.WithAllowIPDomain()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := bemailparts.New(tt.email, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := bemailparts.IsDisposable(e); got != tt.want {
+				t.Errorf("IsDisposable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithRejectDisposableDomains(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		wantErr error
+	}{
+		{name: "disposable domain", email: "john@mailinator.com", wantErr: bemailparts.ErrEmailDomainDisposable},
+		{name: "subdomain", email: "john@inbox.mailinator.com", wantErr: bemailparts.ErrEmailDomainDisposable},
+		{name: "work address", email: "john@example.co"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := bemailparts.New(tt.email, bemailparts.WithRejectDisposableDomains())
+			if !errors.Is(err, tt.wantErr) || (err != nil) != (tt.wantErr != nil) {
+				t.Errorf("New() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("test setters reject disposable domains", func(t *testing.T) {
+		e, err := bemailparts.New("john@example.co", bemailparts.WithRejectDisposableDomains())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = e.SetDomain("yopmail.com"); !errors.Is(err, bemailparts.ErrEmailDomainDisposable) {
+			t.Errorf("SetDomain() error = %v, want %v", err, bemailparts.ErrEmailDomainDisposable)
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package bemailparts
+
+// Field names the part of an address an EmailError is about.
+type Field string
+
+const (
+	FieldEmail       Field = "email"
+	FieldUsername    Field = "username"
+	FieldDomain      Field = "domain"
+	FieldDomainName  Field = "domain_name"
+	FieldTLD         Field = "tld"
+	FieldSubdomain   Field = "subdomain"
+	FieldDisplayName Field = "display_name"
+)
+
+// EmailError describes why a value was rejected, for API responses: which Field, the offending Value and the
+// Code of the error. Constructors, setters and the Validate functions return it, directly or wrapped in a
+// *ParseError, so errors.As retrieves it from any error they return. It wraps the errors of this package,
+// so errors.Is keeps working, and its message is theirs.
+//
+// Example:
+//
+//	err := emailParts.SetUsername("john doe")
+//	var emailErr *EmailError
+//	if errors.As(err, &emailErr) {
+//	    fmt.Println(emailErr.Field, emailErr.Value, emailErr.Code) // Output: username john doe invalid_email_username_format
+//	}
+type EmailError struct {
+	Field Field
+	Value string
+	Code  ErrorCode
+	Err   error
+}
+
+func (e *EmailError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *EmailError) Unwrap() error {
+	return e.Err
+}
+
+// newEmailError wraps err, if not nil, in an *EmailError about value of field.
+func newEmailError(field Field, value string, err error) error {
+	if err == nil {
+		return nil
+	}
+	code, _ := ErrorCodeOf(err)
+	return &EmailError{Field: field, Value: value, Code: code, Err: err}
+}
@@ -0,0 +1,143 @@
+package bemailparts_test
+
+import (
+	"errors"
+	"github.com/bearaujus/bemailparts"
+	"testing"
+)
+
+func TestEmailError(t *testing.T) {
+	e, err := bemailparts.New("john.doe@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		err       error
+		wantField bemailparts.Field
+		wantValue string
+		wantCode  bemailparts.ErrorCode
+		wantErr   error
+	}{
+		{
+			name:      "new invalid username",
+			err:       errorOf(bemailparts.New("john doe@example.com")),
+			wantField: bemailparts.FieldUsername,
+			wantValue: "john doe",
+			wantCode:  bemailparts.CodeInvalidEmailFormat,
+			wantErr:   bemailparts.ErrInvalidEmailFormat,
+		},
+		{
+			name:      "new invalid domain",
+			err:       errorOf(bemailparts.New("john@example..com")),
+			wantField: bemailparts.FieldDomain,
+			wantValue: "example..com",
+			wantCode:  bemailparts.CodeInvalidEmailDomainFormat,
+			wantErr:   bemailparts.ErrInvalidEmailDomainFormat,
+		},
+		{
+			name:      "new missing at",
+			err:       errorOf(bemailparts.New("john.doe")),
+			wantField: bemailparts.FieldEmail,
+			wantValue: "john.doe",
+			wantCode:  bemailparts.CodeInvalidEmailFormat,
+			wantErr:   bemailparts.ErrInvalidEmailFormat,
+		},
+		{
+			name:      "new unterminated comment",
+			err:       errorOf(bemailparts.New("john(work@example.com", bemailparts.WithComments())),
+			wantField: bemailparts.FieldUsername,
+			wantValue: "john(work",
+			wantCode:  bemailparts.CodeInvalidEmailFormat,
+			wantErr:   bemailparts.ErrInvalidEmailFormat,
+		},
+		{
+			name:      "new comment without at",
+			err:       errorOf(bemailparts.New("john.doe (work)", bemailparts.WithComments())),
+			wantField: bemailparts.FieldEmail,
+			wantValue: "john.doe (work)",
+			wantCode:  bemailparts.CodeInvalidEmailFormat,
+			wantErr:   bemailparts.ErrInvalidEmailFormat,
+		},
+		{
+			name:      "new from parts invalid tld",
+			err:       errorOf(bemailparts.NewFromFullParts("john", "example", "c0m")),
+			wantField: bemailparts.FieldTLD,
+			wantValue: "c0m",
+			wantCode:  bemailparts.CodeInvalidEmailDomainTLDFormat,
+			wantErr:   bemailparts.ErrInvalidEmailDomainTLDFormat,
+		},
+		{
+			name:      "set username",
+			err:       e.SetUsername("john doe"),
+			wantField: bemailparts.FieldUsername,
+			wantValue: "john doe",
+			wantCode:  bemailparts.CodeInvalidEmailUsernameFormat,
+			wantErr:   bemailparts.ErrInvalidEmailUsernameFormat,
+		},
+		{
+			name:      "set domain name",
+			err:       e.SetDomainName("exa_mple"),
+			wantField: bemailparts.FieldDomainName,
+			wantValue: "exa_mple",
+			wantCode:  bemailparts.CodeInvalidEmailDomainNameFormat,
+			wantErr:   bemailparts.ErrInvalidEmailDomainNameFormat,
+		},
+		{
+			name:      "parse address missing angle bracket",
+			err:       errorOf(bemailparts.ParseAddress("John Doe john.doe@example.com>")),
+			wantField: bemailparts.FieldEmail,
+			wantValue: "John Doe john.doe@example.com>",
+			wantCode:  bemailparts.CodeInvalidEmailFormat,
+			wantErr:   bemailparts.ErrInvalidEmailFormat,
+		},
+		{
+			name:      "parse address invalid display name",
+			err:       errorOf(bemailparts.ParseAddress("John [Doe] <john.doe@example.com>")),
+			wantField: bemailparts.FieldDisplayName,
+			wantValue: "John [Doe]",
+			wantCode:  bemailparts.CodeInvalidDisplayName,
+			wantErr:   bemailparts.ErrInvalidDisplayName,
+		},
+		{
+			name:      "set display name",
+			err:       e.SetDisplayName("John\nDoe"),
+			wantField: bemailparts.FieldDisplayName,
+			wantValue: "John\nDoe",
+			wantCode:  bemailparts.CodeInvalidDisplayName,
+			wantErr:   bemailparts.ErrInvalidDisplayName,
+		},
+		{
+			name:      "validate domain",
+			err:       bemailparts.ValidateDomain("example"),
+			wantField: bemailparts.FieldDomain,
+			wantValue: "example",
+			wantCode:  bemailparts.CodeInvalidEmailDomainFormat,
+			wantErr:   bemailparts.ErrInvalidEmailDomainFormat,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var emailErr *bemailparts.EmailError
+			if !errors.As(tt.err, &emailErr) {
+				t.Fatalf("error = %v, want an *EmailError", tt.err)
+			}
+			if emailErr.Field != tt.wantField || emailErr.Value != tt.wantValue || emailErr.Code != tt.wantCode {
+				t.Errorf("EmailError = %+v, want field %q, value %q and code %q", emailErr, tt.wantField,
+					tt.wantValue, tt.wantCode)
+			}
+			if !errors.Is(tt.err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", tt.err, tt.wantErr)
+			}
+		})
+	}
+
+	if e.Email() != "john.doe@example.com" {
+		t.Errorf("Email() = %v, want unchanged after failed setters", e.Email())
+	}
+}
+
+func errorOf(_ bemailparts.BEmailParts, err error) error {
+	return err
+}
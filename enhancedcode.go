@@ -0,0 +1,178 @@
+package bemailparts
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// EnhancedCode is an RFC 3463 enhanced mail system status code (class, subject, detail), e.g. {5, 1, 3}.
+// It has the same layout as the EnhancedCode type of github.com/emersion/go-smtp.
+type EnhancedCode [3]int
+
+// Enhanced status code classes defined by RFC 3463.
+const (
+	EnhancedCodeClassSuccess   = 2
+	EnhancedCodeClassTransient = 4
+	EnhancedCodeClassPermanent = 5
+)
+
+// enhancedCodeDescriptions maps the subject and detail of every code defined by RFC 3463, and of the 5.6.7 code
+// of RFC 6531 returned by EnhancedCodeFor, to its description.
+var enhancedCodeDescriptions = map[[2]int]string{
+	{0, 0}: "Other undefined status",
+	{1, 0}: "Other address status",
+	{1, 1}: "Bad destination mailbox address",
+	{1, 2}: "Bad destination system address",
+	{1, 3}: "Bad destination mailbox address syntax",
+	{1, 4}: "Destination mailbox address ambiguous",
+	{1, 5}: "Destination address valid",
+	{1, 6}: "Destination mailbox has moved",
+	{1, 7}: "Bad sender's mailbox address syntax",
+	{1, 8}: "Bad sender's system address",
+	{2, 0}: "Other or undefined mailbox status",
+	{2, 1}: "Mailbox disabled, not accepting messages",
+	{2, 2}: "Mailbox full",
+	{2, 3}: "Message length exceeds administrative limit",
+	{2, 4}: "Mailing list expansion problem",
+	{3, 0}: "Other or undefined mail system status",
+	{3, 1}: "Mail system full",
+	{3, 2}: "System not accepting network messages",
+	{3, 3}: "System not capable of selected features",
+	{3, 4}: "Message too big for system",
+	{3, 5}: "System incorrectly configured",
+	{4, 0}: "Other or undefined network or routing status",
+	{4, 1}: "No answer from host",
+	{4, 2}: "Bad connection",
+	{4, 3}: "Directory server failure",
+	{4, 4}: "Unable to route",
+	{4, 5}: "Mail system congestion",
+	{4, 6}: "Routing loop detected",
+	{4, 7}: "Delivery time expired",
+	{5, 0}: "Other or undefined protocol status",
+	{5, 1}: "Invalid command",
+	{5, 2}: "Syntax error",
+	{5, 3}: "Too many recipients",
+	{5, 4}: "Invalid command arguments",
+	{5, 5}: "Wrong protocol version",
+	{6, 0}: "Other or undefined media error",
+	{6, 1}: "Media not supported",
+	{6, 2}: "Conversion required and prohibited",
+	{6, 3}: "Conversion required but not supported",
+	{6, 4}: "Conversion with loss performed",
+	{6, 5}: "Conversion failed",
+	{6, 7}: "Non-ASCII addresses not permitted for that sender/recipient",
+	{7, 0}: "Other or undefined security status",
+	{7, 1}: "Delivery not authorized, message refused",
+	{7, 2}: "Mailing list expansion prohibited",
+	{7, 3}: "Security conversion required but not possible",
+	{7, 4}: "Security features not supported",
+	{7, 5}: "Cryptographic failure",
+	{7, 6}: "Cryptographic algorithm not supported",
+	{7, 7}: "Message integrity failure",
+}
+
+// ParseEnhancedCode parses an enhanced status code such as "5.1.1". The class must be 2, 4, or 5, and the
+// subject and detail must have 1 to 3 ASCII digits, without a sign.
+//
+// Example:
+//
+//	code, err := ParseEnhancedCode("5.1.1")
+//	if err != nil {
+//	    log.Fatalf("Invalid code: %v", err)
+//	}
+//
+//	fmt.Println(code.IsPermanent()) // Output: true
+//	fmt.Println(code.Description()) // Output: Bad destination mailbox address
+func ParseEnhancedCode(s string) (EnhancedCode, error) {
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return EnhancedCode{}, ErrInvalidEnhancedCode
+	}
+
+	var code EnhancedCode
+	for i, part := range parts {
+		if len(part) > 3 || !isNumeric(part) {
+			return EnhancedCode{}, ErrInvalidEnhancedCode
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return EnhancedCode{}, ErrInvalidEnhancedCode
+		}
+		code[i] = n
+	}
+
+	switch code[0] {
+	case EnhancedCodeClassSuccess, EnhancedCodeClassTransient, EnhancedCodeClassPermanent:
+		return code, nil
+	default:
+		return EnhancedCode{}, ErrInvalidEnhancedCode
+	}
+}
+
+// EnhancedCodeFor returns the permanent enhanced status code describing err, which may wrap one of the
+// package's errors. Returns false if err has no matching code.
+//
+// Example:
+//
+//	_, err := New("invalid")
+//	code, _ := EnhancedCodeFor(err)
+//	fmt.Println(code) // Output: 5.1.3
+func EnhancedCodeFor(err error) (EnhancedCode, bool) {
+	switch {
+	case errors.Is(err, ErrInvalidEmailFormat),
+		errors.Is(err, ErrInvalidEmailUsernameFormat),
+		errors.Is(err, ErrInvalidEmailDomainFormat),
+		errors.Is(err, ErrInvalidEmailDomainNameFormat),
+		errors.Is(err, ErrInvalidEmailDomainTLDFormat),
+		errors.Is(err, ErrEmailUsernameTooLong),
+		errors.Is(err, ErrEmailDomainTooLong),
+		errors.Is(err, ErrEmailTooLong),
+		errors.Is(err, ErrInvalidIDN),
+		errors.Is(err, ErrEmailDomainTLDTooShort),
+		errors.Is(err, ErrEmailDomainTLDNumeric),
+		errors.Is(err, ErrEmailDomainTLDTooLong),
+		errors.Is(err, ErrEmailDomainTLDNotAllowed),
+		errors.Is(err, ErrInvalidDisplayName),
+		errors.Is(err, ErrInvalidAddressLiteral),
+		errors.Is(err, ErrUnsupportedAddressLiteral):
+		return EnhancedCode{5, 1, 3}, true
+	case errors.Is(err, ErrDomainWithoutMX), errors.Is(err, ErrEmailDomainReserved),
+		errors.Is(err, ErrEmailDomainTLDUnknown), errors.Is(err, ErrNoRegistrableDomain):
+		return EnhancedCode{5, 1, 2}, true
+	case errors.Is(err, ErrEmailRequiresSMTPUTF8), errors.Is(err, ErrEmailRequiresIDNA):
+		return EnhancedCode{5, 6, 7}, true
+	case errors.Is(err, ErrEmailSuppressed), errors.Is(err, ErrRejectedByValidator),
+		errors.Is(err, ErrEmailDomainDisposable), errors.Is(err, ErrEmailDomainFreeProvider):
+		return EnhancedCode{5, 7, 1}, true
+	default:
+		return EnhancedCode{}, false
+	}
+}
+
+// String returns the code in dotted form, e.g. "5.1.3".
+func (c EnhancedCode) String() string {
+	return fmt.Sprintf("%d.%d.%d", c[0], c[1], c[2])
+}
+
+// IsSuccess reports whether the code has class 2.
+func (c EnhancedCode) IsSuccess() bool {
+	return c[0] == EnhancedCodeClassSuccess
+}
+
+// IsTransient reports whether the code has class 4, meaning delivery may succeed if retried.
+func (c EnhancedCode) IsTransient() bool {
+	return c[0] == EnhancedCodeClassTransient
+}
+
+// IsPermanent reports whether the code has class 5, meaning delivery will not succeed if retried.
+func (c EnhancedCode) IsPermanent() bool {
+	return c[0] == EnhancedCodeClassPermanent
+}
+
+// Description returns the RFC 3463 description of the code's subject and detail, e.g. "Mailbox full" for
+// 4.2.2, or an empty string if the code is not defined by RFC 3463.
+func (c EnhancedCode) Description() string {
+	return enhancedCodeDescriptions[[2]int{c[1], c[2]}]
+}
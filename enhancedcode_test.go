@@ -0,0 +1,154 @@
+package bemailparts_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/bearaujus/bemailparts"
+	"testing"
+)
+
+func TestParseEnhancedCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    bemailparts.EnhancedCode
+		wantErr bool
+	}{
+		{
+			name: "success permanent",
+			s:    "5.1.1",
+			want: bemailparts.EnhancedCode{5, 1, 1},
+		},
+		{
+			name: "success transient with three digit detail",
+			s:    "4.2.100",
+			want: bemailparts.EnhancedCode{4, 2, 100},
+		},
+		{
+			name:    "error invalid class",
+			s:       "3.1.1",
+			wantErr: true,
+		},
+		{
+			name:    "error missing detail",
+			s:       "5.1",
+			wantErr: true,
+		},
+		{
+			name:    "error too many digits",
+			s:       "5.1.1000",
+			wantErr: true,
+		},
+		{
+			name:    "error not a number",
+			s:       "5.a.1",
+			wantErr: true,
+		},
+		{
+			name:    "error signed numbers",
+			s:       "5.+1.-0",
+			wantErr: true,
+		},
+		{
+			name:    "error non-ASCII digit",
+			s:       "5.١.1",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bemailparts.ParseEnhancedCode(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseEnhancedCode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseEnhancedCode() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnhancedCode(t *testing.T) {
+	code := bemailparts.EnhancedCode{4, 2, 2}
+	if code.String() != "4.2.2" {
+		t.Errorf("String() got = %v, want %v", code.String(), "4.2.2")
+	}
+	if !code.IsTransient() || code.IsPermanent() || code.IsSuccess() {
+		t.Errorf("IsTransient(), IsPermanent(), IsSuccess() got = %v, %v, %v, want true, false, false",
+			code.IsTransient(), code.IsPermanent(), code.IsSuccess())
+	}
+	if code.Description() != "Mailbox full" {
+		t.Errorf("Description() got = %v, want %v", code.Description(), "Mailbox full")
+	}
+	if (bemailparts.EnhancedCode{5, 6, 7}).Description() == "" {
+		t.Error("Description() of 5.6.7 is empty")
+	}
+	if (bemailparts.EnhancedCode{5, 9, 9}).Description() != "" {
+		t.Error("Description() of an undefined code is not empty")
+	}
+}
+
+func TestEnhancedCodeFor(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		want   bemailparts.EnhancedCode
+		wantOk bool
+	}{
+		{
+			name:   "invalid format",
+			err:    bemailparts.ErrInvalidEmailFormat,
+			want:   bemailparts.EnhancedCode{5, 1, 3},
+			wantOk: true,
+		},
+		{
+			name:   "wrapped suppression",
+			err:    fmt.Errorf("%w: bounce", bemailparts.ErrEmailSuppressed),
+			want:   bemailparts.EnhancedCode{5, 7, 1},
+			wantOk: true,
+		},
+		{
+			name:   "missing mx",
+			err:    bemailparts.ErrDomainWithoutMX,
+			want:   bemailparts.EnhancedCode{5, 1, 2},
+			wantOk: true,
+		},
+		{
+			name:   "reserved domain",
+			err:    bemailparts.ErrEmailDomainReserved,
+			want:   bemailparts.EnhancedCode{5, 1, 2},
+			wantOk: true,
+		},
+		{
+			name:   "domain is a public suffix",
+			err:    bemailparts.ErrNoRegistrableDomain,
+			want:   bemailparts.EnhancedCode{5, 1, 2},
+			wantOk: true,
+		},
+		{
+			name:   "free provider",
+			err:    bemailparts.ErrEmailDomainFreeProvider,
+			want:   bemailparts.EnhancedCode{5, 7, 1},
+			wantOk: true,
+		},
+		{
+			name:   "smtputf8 required",
+			err:    bemailparts.ErrEmailRequiresSMTPUTF8,
+			want:   bemailparts.EnhancedCode{5, 6, 7},
+			wantOk: true,
+		},
+		{
+			name: "unrelated error",
+			err:  errors.New("boom"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := bemailparts.EnhancedCodeFor(tt.err)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("EnhancedCodeFor() got = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
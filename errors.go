@@ -8,4 +8,150 @@ var (
 	ErrInvalidEmailDomainFormat     = errors.New("invalid email domain format")
 	ErrInvalidEmailDomainNameFormat = errors.New("invalid email domain name format")
 	ErrInvalidEmailDomainTLDFormat  = errors.New("invalid email domain tld format")
+	ErrEmailUsernameTooLong         = errors.New("email username too long")
+	ErrEmailDomainTooLong           = errors.New("email domain too long")
+	ErrInvalidAddressLiteral        = errors.New("invalid email address literal")
+	ErrUnsupportedAddressLiteral    = errors.New("unsupported email address literal")
+	ErrInvalidColumn                = errors.New("invalid column")
+	ErrDuplicateEmail               = errors.New("duplicate email")
+	ErrEmailSuppressed              = errors.New("email is suppressed")
+	ErrDomainWithoutMX              = errors.New("email domain has no mx record")
+	ErrInvalidEnhancedCode          = errors.New("invalid enhanced status code")
+	ErrInvariantViolation           = errors.New("invariant violation")
+	ErrEmailDomainTLDTooShort       = errors.New("email domain tld too short")
+	ErrInvalidDisplayName           = errors.New("invalid display name")
+	ErrEmailTooLong                 = errors.New("email too long")
+	ErrInvalidIDN                   = errors.New("invalid internationalized domain name")
+	ErrEmailDomainTLDNumeric        = errors.New("email domain tld is numeric")
+	ErrEmailDomainTLDTooLong        = errors.New("email domain tld too long")
+	ErrRejectedByValidator          = errors.New("rejected by validator")
+	ErrInvalidRewriteRule           = errors.New("invalid rewrite rule")
+	ErrEmailDomainTLDNotAllowed     = errors.New("email domain tld not allowed")
+	ErrEmailRequiresSMTPUTF8        = errors.New("email username requires smtputf8")
+	ErrEmailRequiresIDNA            = errors.New("email domain requires idna conversion")
+	ErrEmailDomainReserved          = errors.New("email domain is reserved")
+	ErrNoRegistrableDomain          = errors.New("email domain has no registrable domain")
+	ErrEmailColumnNotFound          = errors.New("no email column found")
+	ErrEmailDomainTLDUnknown        = errors.New("email domain tld unknown")
+	ErrEmailDomainDisposable        = errors.New("email domain is disposable")
+	ErrEmailDomainFreeProvider      = errors.New("email domain is a free provider")
 )
+
+// ErrorCode is a stable, machine-readable identifier of an error returned by this package, suitable for
+// mapping errors to client responses without matching on error messages.
+type ErrorCode string
+
+const (
+	CodeInvalidEmailFormat           ErrorCode = "invalid_email_format"
+	CodeInvalidEmailUsernameFormat   ErrorCode = "invalid_email_username_format"
+	CodeInvalidEmailDomainFormat     ErrorCode = "invalid_email_domain_format"
+	CodeInvalidEmailDomainNameFormat ErrorCode = "invalid_email_domain_name_format"
+	CodeInvalidEmailDomainTLDFormat  ErrorCode = "invalid_email_domain_tld_format"
+	CodeEmailUsernameTooLong         ErrorCode = "email_username_too_long"
+	CodeEmailDomainTooLong           ErrorCode = "email_domain_too_long"
+	CodeInvalidAddressLiteral        ErrorCode = "invalid_address_literal"
+	CodeUnsupportedAddressLiteral    ErrorCode = "unsupported_address_literal"
+	CodeInvalidColumn                ErrorCode = "invalid_column"
+	CodeDuplicateEmail               ErrorCode = "duplicate_email"
+	CodeEmailSuppressed              ErrorCode = "email_suppressed"
+	CodeDomainWithoutMX              ErrorCode = "domain_without_mx"
+	CodeInvalidEnhancedCode          ErrorCode = "invalid_enhanced_code"
+	CodeInvariantViolation           ErrorCode = "invariant_violation"
+	CodeEmailDomainTLDTooShort       ErrorCode = "email_domain_tld_too_short"
+	CodeInvalidDisplayName           ErrorCode = "invalid_display_name"
+	CodeEmailTooLong                 ErrorCode = "email_too_long"
+	CodeInvalidIDN                   ErrorCode = "invalid_idn"
+	CodeEmailDomainTLDNumeric        ErrorCode = "email_domain_tld_numeric"
+	CodeEmailDomainTLDTooLong        ErrorCode = "email_domain_tld_too_long"
+	CodeRejectedByValidator          ErrorCode = "rejected_by_validator"
+	CodeInvalidRewriteRule           ErrorCode = "invalid_rewrite_rule"
+	CodeEmailDomainTLDNotAllowed     ErrorCode = "email_domain_tld_not_allowed"
+	CodeEmailRequiresSMTPUTF8        ErrorCode = "email_requires_smtputf8"
+	CodeEmailRequiresIDNA            ErrorCode = "email_requires_idna"
+	CodeEmailDomainReserved          ErrorCode = "email_domain_reserved"
+	CodeNoRegistrableDomain          ErrorCode = "no_registrable_domain"
+	CodeEmailColumnNotFound          ErrorCode = "email_column_not_found"
+	CodeEmailDomainTLDUnknown        ErrorCode = "email_domain_tld_unknown"
+	CodeEmailDomainDisposable        ErrorCode = "email_domain_disposable"
+	CodeEmailDomainFreeProvider      ErrorCode = "email_domain_free_provider"
+)
+
+// ErrorCodesVersion is the version of the list returned by AllErrorCodes. It is incremented whenever codes
+// are added. Existing codes are never renamed or removed.
+const ErrorCodesVersion = 16
+
+// errorCodes pairs every error of this package with its code, in the order codes were introduced.
+var errorCodes = []struct {
+	code ErrorCode
+	err  error
+}{
+	{code: CodeInvalidEmailFormat, err: ErrInvalidEmailFormat},
+	{code: CodeInvalidEmailUsernameFormat, err: ErrInvalidEmailUsernameFormat},
+	{code: CodeInvalidEmailDomainFormat, err: ErrInvalidEmailDomainFormat},
+	{code: CodeInvalidEmailDomainNameFormat, err: ErrInvalidEmailDomainNameFormat},
+	{code: CodeInvalidEmailDomainTLDFormat, err: ErrInvalidEmailDomainTLDFormat},
+	{code: CodeEmailUsernameTooLong, err: ErrEmailUsernameTooLong},
+	{code: CodeEmailDomainTooLong, err: ErrEmailDomainTooLong},
+	{code: CodeInvalidAddressLiteral, err: ErrInvalidAddressLiteral},
+	{code: CodeUnsupportedAddressLiteral, err: ErrUnsupportedAddressLiteral},
+	{code: CodeInvalidColumn, err: ErrInvalidColumn},
+	{code: CodeDuplicateEmail, err: ErrDuplicateEmail},
+	{code: CodeEmailSuppressed, err: ErrEmailSuppressed},
+	{code: CodeDomainWithoutMX, err: ErrDomainWithoutMX},
+	{code: CodeInvalidEnhancedCode, err: ErrInvalidEnhancedCode},
+	{code: CodeInvariantViolation, err: ErrInvariantViolation},
+	{code: CodeEmailDomainTLDTooShort, err: ErrEmailDomainTLDTooShort},
+	{code: CodeInvalidDisplayName, err: ErrInvalidDisplayName},
+	{code: CodeEmailTooLong, err: ErrEmailTooLong},
+	{code: CodeInvalidIDN, err: ErrInvalidIDN},
+	{code: CodeEmailDomainTLDNumeric, err: ErrEmailDomainTLDNumeric},
+	{code: CodeEmailDomainTLDTooLong, err: ErrEmailDomainTLDTooLong},
+	{code: CodeRejectedByValidator, err: ErrRejectedByValidator},
+	{code: CodeInvalidRewriteRule, err: ErrInvalidRewriteRule},
+	{code: CodeEmailDomainTLDNotAllowed, err: ErrEmailDomainTLDNotAllowed},
+	{code: CodeEmailRequiresSMTPUTF8, err: ErrEmailRequiresSMTPUTF8},
+	{code: CodeEmailRequiresIDNA, err: ErrEmailRequiresIDNA},
+	{code: CodeEmailDomainReserved, err: ErrEmailDomainReserved},
+	{code: CodeNoRegistrableDomain, err: ErrNoRegistrableDomain},
+	{code: CodeEmailColumnNotFound, err: ErrEmailColumnNotFound},
+	{code: CodeEmailDomainTLDUnknown, err: ErrEmailDomainTLDUnknown},
+	{code: CodeEmailDomainDisposable, err: ErrEmailDomainDisposable},
+	{code: CodeEmailDomainFreeProvider, err: ErrEmailDomainFreeProvider},
+}
+
+// AllErrorCodes returns every error code of this package, in the order they were introduced. API gateways
+// can use it to test their error mappings exhaustively; see ErrorCodesVersion.
+func AllErrorCodes() []ErrorCode {
+	codes := make([]ErrorCode, len(errorCodes))
+	for i, entry := range errorCodes {
+		codes[i] = entry.code
+	}
+	return codes
+}
+
+// ErrorCodeOf returns the code of err, which may wrap one of the errors of this package.
+// Returns false if err is not an error of this package.
+//
+// Example:
+//
+//	_, err := New("invalid")
+//	code, _ := ErrorCodeOf(err)
+//	fmt.Println(code) // Output: invalid_email_format
+func ErrorCodeOf(err error) (ErrorCode, bool) {
+	for _, entry := range errorCodes {
+		if errors.Is(err, entry.err) {
+			return entry.code, true
+		}
+	}
+	return "", false
+}
+
+// Err returns the error identified by the code, or nil if the code is unknown.
+func (c ErrorCode) Err() error {
+	for _, entry := range errorCodes {
+		if entry.code == c {
+			return entry.err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package bemailparts_test
+
+import (
+	"fmt"
+	"github.com/bearaujus/bemailparts"
+	"testing"
+)
+
+func TestAllErrorCodes(t *testing.T) {
+	codes := bemailparts.AllErrorCodes()
+	if len(codes) == 0 {
+		t.Fatal("AllErrorCodes() returned no codes")
+	}
+
+	seen := map[bemailparts.ErrorCode]bool{}
+	for _, code := range codes {
+		if seen[code] {
+			t.Errorf("AllErrorCodes() returned %v twice", code)
+		}
+		seen[code] = true
+
+		err := code.Err()
+		if err == nil {
+			t.Errorf("Err() of %v got nil", code)
+			continue
+		}
+		if got, ok := bemailparts.ErrorCodeOf(fmt.Errorf("wrapped: %w", err)); !ok || got != code {
+			t.Errorf("ErrorCodeOf() got = %v, %v, want %v, true", got, ok, code)
+		}
+	}
+}
+
+func TestErrorCodeOf(t *testing.T) {
+	_, err := bemailparts.New("invalid")
+	if got, ok := bemailparts.ErrorCodeOf(err); !ok || got != bemailparts.CodeInvalidEmailFormat {
+		t.Errorf("ErrorCodeOf() got = %v, %v, want %v, true", got, ok, bemailparts.CodeInvalidEmailFormat)
+	}
+	if _, ok := bemailparts.ErrorCodeOf(fmt.Errorf("unrelated")); ok {
+		t.Error("ErrorCodeOf() of an unrelated error got ok = true")
+	}
+	if bemailparts.ErrorCode("unknown").Err() != nil {
+		t.Error("Err() of an unknown code is not nil")
+	}
+}
@@ -0,0 +1,95 @@
+package bemailparts
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+)
+
+// ImportMailchimp adds the members of a Mailchimp audience export (the "Email Address" column of the
+// cleaned or unsubscribed CSV) to the list with the given reason. Rows without a valid address are
+// skipped. Returns the number of addresses added.
+//
+// Example:
+//
+//	f, _ := os.Open("cleaned_members_export.csv")
+//	n, err := list.ImportMailchimp(f, SuppressionBounce)
+//	if err != nil {
+//	    log.Fatalf("Failed to import: %v", err)
+//	}
+func (l *SuppressionList) ImportMailchimp(r io.Reader, reason SuppressionReason) (int, error) {
+	return l.importCSV(r, "email address", reason)
+}
+
+// ImportSendGrid adds the addresses of a SendGrid suppression export (the "email" column of the bounces,
+// blocks, spam reports or unsubscribes CSV) to the list with the given reason. Rows without a valid address
+// are skipped. Returns the number of addresses added.
+func (l *SuppressionList) ImportSendGrid(r io.Reader, reason SuppressionReason) (int, error) {
+	return l.importCSV(r, "email", reason)
+}
+
+// ImportSES adds the addresses of an Amazon SES account-level suppression list, as returned in JSON by
+// ListSuppressedDestinations, to the list. SES reasons BOUNCE and COMPLAINT map to SuppressionBounce and
+// SuppressionComplaint. Entries without a valid address are skipped. Returns the number of addresses added.
+func (l *SuppressionList) ImportSES(r io.Reader) (int, error) {
+	var export struct {
+		SuppressedDestinationSummaries []struct {
+			EmailAddress string
+			Reason       string
+		}
+	}
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return 0, err
+	}
+
+	var n int
+	for _, summary := range export.SuppressedDestinationSummaries {
+		reason := SuppressionBounce
+		if strings.EqualFold(summary.Reason, "COMPLAINT") {
+			reason = SuppressionComplaint
+		}
+		if l.Add(strings.TrimSpace(summary.EmailAddress), reason) == nil {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (l *SuppressionList) importCSV(r io.Reader, column string, reason SuppressionReason) (int, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	col := -1
+	for i, name := range header {
+		if strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")), column) {
+			col = i
+			break
+		}
+	}
+	if col < 0 {
+		return 0, ErrInvalidColumn
+	}
+
+	var n int
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+		if col < len(record) && l.Add(strings.TrimSpace(record[col]), reason) == nil {
+			n++
+		}
+	}
+}
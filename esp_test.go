@@ -0,0 +1,58 @@
+package bemailparts_test
+
+import (
+	"github.com/bearaujus/bemailparts"
+	"strings"
+	"testing"
+)
+
+func TestSuppressionListImport(t *testing.T) {
+	t.Run("test import mailchimp", func(t *testing.T) {
+		list := bemailparts.NewSuppressionList()
+		input := "\ufeffEmail Address,First Name,CLEAN_TIME\n" +
+			"john.doe@example.com,John,2024-01-01 00:00:00\n" +
+			"broken,Jane,2024-01-01 00:00:00\n"
+		n, err := list.ImportMailchimp(strings.NewReader(input), bemailparts.SuppressionBounce)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 1 || !list.Contains("john.doe@example.com") {
+			t.Errorf("ImportMailchimp() got = %v, emails %v", n, list.Emails())
+		}
+	})
+
+	t.Run("test import sendgrid", func(t *testing.T) {
+		list := bemailparts.NewSuppressionList()
+		input := "created,email,reason,status\n" +
+			"1704067200,john.doe@example.com,550 5.1.1 unknown user,5.1.1\n"
+		n, err := list.ImportSendGrid(strings.NewReader(input), bemailparts.SuppressionBounce)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 1 || !list.Contains("john.doe@example.com") {
+			t.Errorf("ImportSendGrid() got = %v, emails %v", n, list.Emails())
+		}
+
+		if _, err = list.ImportSendGrid(strings.NewReader("created,address\n"), bemailparts.SuppressionBounce); err == nil {
+			t.Error("expecting an error on ImportSendGrid() but got nil")
+		}
+	})
+
+	t.Run("test import ses", func(t *testing.T) {
+		list := bemailparts.NewSuppressionList()
+		input := `{"SuppressedDestinationSummaries":[
+			{"EmailAddress":"john.doe@example.com","Reason":"BOUNCE","LastUpdateTime":"2024-01-01T00:00:00Z"},
+			{"EmailAddress":"jane.doe@example.com","Reason":"COMPLAINT","LastUpdateTime":"2024-01-01T00:00:00Z"}
+		]}`
+		n, err := list.ImportSES(strings.NewReader(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 2 {
+			t.Errorf("ImportSES() got = %v, want %v", n, 2)
+		}
+		if reason, _ := list.Reason("jane.doe@example.com"); reason != bemailparts.SuppressionComplaint {
+			t.Errorf("Reason() got = %v, want %v", reason, bemailparts.SuppressionComplaint)
+		}
+	})
+}
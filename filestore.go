@@ -0,0 +1,261 @@
+package bemailparts
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileStore is a Store keeping values in memory and persisting every change to an append-only log file, so
+// that a SuppressionList, a Quarantine or a BouncePolicy survives restarts. The log holds one JSON record
+// per line and is replayed by OpenFileStore; Compact rewrites it without overwritten, deleted and expired
+// values. Each change is synced to disk before Put or Delete returns.
+//
+// A FileStore is safe for concurrent use, but a log file must be opened by a single FileStore at a time.
+type FileStore struct {
+	mu      sync.Mutex
+	path    string
+	file    *os.File
+	clock   Clock
+	entries map[string]memoryStoreEntry
+}
+
+// fileStoreRecord is a line of the log of a FileStore: a value stored at Key until Expires, in Unix
+// nanoseconds (0 for never), or the deletion of Key.
+type fileStoreRecord struct {
+	Key     string `json:"key"`
+	Value   []byte `json:"value,omitempty"`
+	Expires int64  `json:"expires,omitempty"`
+	Deleted bool   `json:"deleted,omitempty"`
+}
+
+// OpenFileStore opens the FileStore logged in the file at path, creating the file if it does not exist.
+// A last line left incomplete by a crash is discarded. Returns an error if the file cannot be opened or
+// holds an invalid record.
+//
+// Example:
+//
+//	store, err := OpenFileStore("/var/lib/mailer/state.log")
+//	if err != nil {
+//	    log.Fatalf("Failed to open store: %v", err)
+//	}
+//	defer store.Close()
+//
+//	suppressions := NewSuppressionListWithStore(store)
+func OpenFileStore(path string) (*FileStore, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	s := &FileStore{path: path, file: file, clock: systemClock, entries: map[string]memoryStoreEntry{}}
+	if err = s.replay(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// replay applies the records of the log to s.entries, and truncates an incomplete last line.
+func (s *FileStore) replay() error {
+	r := bufio.NewReader(s.file)
+	var size int64
+	for n := 1; ; n++ {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			// A line without its newline was not completely written.
+			if err = s.file.Truncate(size); err != nil {
+				return err
+			}
+			_, err = s.file.Seek(size, io.SeekStart)
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		size += int64(len(line))
+
+		var record fileStoreRecord
+		if err = json.Unmarshal(bytes.TrimSpace(line), &record); err != nil {
+			return fmt.Errorf("%s: line %d: %w", s.path, n, err)
+		}
+		s.apply(record)
+	}
+}
+
+func (s *FileStore) apply(record fileStoreRecord) {
+	if record.Deleted {
+		delete(s.entries, record.Key)
+		return
+	}
+	entry := memoryStoreEntry{value: record.Value}
+	if record.Expires != 0 {
+		entry.expires = time.Unix(0, record.Expires)
+	}
+	s.entries[record.Key] = entry
+}
+
+// SetClock makes the FileStore read the current time from clock, e.g. to simulate the expiry of values in
+// tests. Passing nil restores the system clock.
+func (s *FileStore) SetClock(clock Clock) {
+	if clock == nil {
+		clock = systemClock
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clock = clock
+}
+
+func (s *FileStore) Get(key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil, false, os.ErrClosed
+	}
+	entry, ok := s.entries[key]
+	if !ok || entry.expired(s.clock.Now()) {
+		return nil, false, nil
+	}
+	return append([]byte(nil), entry.value...), true, nil
+}
+
+func (s *FileStore) Put(key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record := fileStoreRecord{Key: key, Value: append([]byte(nil), value...)}
+	if ttl > 0 {
+		record.Expires = s.clock.Now().Add(ttl).UnixNano()
+	}
+	return s.write(record)
+}
+
+func (s *FileStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.entries[key]; !ok {
+		return nil
+	}
+	return s.write(fileStoreRecord{Key: key, Deleted: true})
+}
+
+// write appends record to the log and applies it once it is synced.
+func (s *FileStore) write(record fileStoreRecord) error {
+	if s.file == nil {
+		return os.ErrClosed
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if _, err = s.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	if err = s.file.Sync(); err != nil {
+		return err
+	}
+	s.apply(record)
+	return nil
+}
+
+// Scan works on a snapshot of the matching values, so fn may call other methods of the FileStore.
+func (s *FileStore) Scan(prefix string, fn func(key string, value []byte) bool) error {
+	type pair struct {
+		key   string
+		value []byte
+	}
+
+	s.mu.Lock()
+	if s.file == nil {
+		s.mu.Unlock()
+		return os.ErrClosed
+	}
+	now := s.clock.Now()
+	var pairs []pair
+	for key, entry := range s.entries {
+		if strings.HasPrefix(key, prefix) && !entry.expired(now) {
+			pairs = append(pairs, pair{key: key, value: append([]byte(nil), entry.value...)})
+		}
+	}
+	s.mu.Unlock()
+
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].key < pairs[j].key })
+	for _, p := range pairs {
+		if !fn(p.key, p.value) {
+			break
+		}
+	}
+	return nil
+}
+
+// Compact rewrites the log with one record per unexpired value, so that it stops growing with every
+// change. The new log is written to a temporary file next to it and renamed over it, so a crash leaves
+// either the old or the new log in place.
+func (s *FileStore) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return os.ErrClosed
+	}
+
+	now := s.clock.Now()
+	keys := make([]string, 0, len(s.entries))
+	for key, entry := range s.entries {
+		if entry.expired(now) {
+			delete(s.entries, key)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	tmpPath := s.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(tmp)
+	for _, key := range keys {
+		entry := s.entries[key]
+		record := fileStoreRecord{Key: key, Value: entry.value}
+		if !entry.expires.IsZero() {
+			record.Expires = entry.expires.UnixNano()
+		}
+		line, _ := json.Marshal(record)
+		w.Write(line)
+		w.WriteByte('\n')
+	}
+	if err = w.Flush(); err == nil {
+		err = tmp.Sync()
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, s.path)
+	}
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+
+	s.file.Close()
+	s.file = tmp
+	return nil
+}
+
+// Close closes the log. The FileStore cannot be used afterwards; its methods return os.ErrClosed.
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return os.ErrClosed
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
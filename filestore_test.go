@@ -0,0 +1,132 @@
+package bemailparts_test
+
+import (
+	"errors"
+	"github.com/bearaujus/bemailparts"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.log")
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	open := func() *bemailparts.FileStore {
+		store, err := bemailparts.OpenFileStore(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		store.SetClock(clock)
+		return store
+	}
+	scan := func(store *bemailparts.FileStore) []string {
+		var pairs []string
+		if err := store.Scan("a/", func(key string, value []byte) bool {
+			pairs = append(pairs, key+"="+string(value))
+			return true
+		}); err != nil {
+			t.Fatal(err)
+		}
+		return pairs
+	}
+
+	store := open()
+	for key, ttl := range map[string]time.Duration{"a/1": time.Hour, "a/2": 0, "a/3": 0, "b/1": 0} {
+		if err := store.Put(key, []byte(key), ttl); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Put("a/2", []byte("two"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete("a/3"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := store.Get("a/1"); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("Get() after Close() error = %v, want %v", err, os.ErrClosed)
+	}
+
+	store = open()
+	if got, want := scan(store), []string{"a/1=a/1", "a/2=two"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Scan() after reopening got = %v, want %v", got, want)
+	}
+
+	clock.Advance(time.Hour)
+	if _, ok, _ := store.Get("a/1"); ok {
+		t.Error("Get() got = true after the ttl elapsed, want false")
+	}
+	before, _ := os.Stat(path)
+	if err := store.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	after, _ := os.Stat(path)
+	if after.Size() >= before.Size() {
+		t.Errorf("Compact() got size %v, want less than %v", after.Size(), before.Size())
+	}
+	if err := store.Put("a/4", []byte("four"), 0); err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	store = open()
+	defer store.Close()
+	if got, want := scan(store), []string{"a/2=two", "a/4=four"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Scan() after compacting got = %v, want %v", got, want)
+	}
+	if value, ok, err := store.Get("b/1"); err != nil || !ok || string(value) != "b/1" {
+		t.Errorf("Get() got = %s, %v, %v, want b/1, true, nil", value, ok, err)
+	}
+}
+
+func TestFileStoreRecovery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.log")
+	store, err := bemailparts.OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := bemailparts.NewSuppressionListWithStore(store)
+	if err = list.Add("john@example.com", bemailparts.SuppressionComplaint); err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	// Simulate a crash in the middle of writing a record.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"key":"suppression/jane@exa`)
+	f.Close()
+
+	store, err = bemailparts.OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list = bemailparts.NewSuppressionListWithStore(store)
+	if err = list.Add("jane@example.com", bemailparts.SuppressionBounce); err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	store, err = bemailparts.OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	list = bemailparts.NewSuppressionListWithStore(store)
+	if got, want := list.Emails(), []string{"jane@example.com", "john@example.com"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Emails() got = %v, want %v", got, want)
+	}
+
+	if err = os.WriteFile(path, []byte("not json\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = bemailparts.OpenFileStore(path); err == nil {
+		t.Error("expecting an error on OpenFileStore() but got nil")
+	}
+}
@@ -0,0 +1,129 @@
+package bemailparts
+
+import (
+	_ "embed"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// freeProviderList lists the domains of consumer mailbox providers, one per line, after comment lines
+// starting with '#'. "name.*" matches the domain "name" under any public suffix.
+//
+//go:embed free_providers.txt
+var freeProviderList string
+
+// freeProviderWildcard ends the entries matching a name under any public suffix, such as "yahoo.*".
+const freeProviderWildcard = ".*"
+
+var (
+	freeProvidersOnce sync.Once
+	freeProviderSet   map[string]bool
+)
+
+// loadFreeProviders parses freeProviderList on first use.
+func loadFreeProviders() map[string]bool {
+	freeProvidersOnce.Do(func() {
+		freeProviderSet = map[string]bool{}
+		for _, line := range strings.Split(freeProviderList, "\n") {
+			if entry := strings.TrimSpace(line); entry != "" && !strings.HasPrefix(entry, "#") {
+				freeProviderSet[entry] = true
+			}
+		}
+	})
+	return freeProviderSet
+}
+
+// FreeProviders returns the embedded entries IsFreeProvider matches, sorted: the domains of consumer mailbox
+// providers, e.g. "gmail.com", and names matching any public suffix, e.g. "yahoo.*". The result is a copy;
+// use WithFreeProviders to change the entries an instance matches.
+func FreeProviders() []string {
+	embedded := loadFreeProviders()
+	entries := make([]string, 0, len(embedded))
+	for entry := range embedded {
+		entries = append(entries, entry)
+	}
+	sort.Strings(entries)
+	return entries
+}
+
+// WithFreeProviders changes the providers IsFreeProvider matches: it adds the entries of add to the embedded
+// ones and removes those of remove, e.g. to treat a provider's business offering as a work address. An entry
+// is a domain, e.g. "mail.example", or a name followed by ".*" to match it under any public suffix, e.g.
+// "example.*" for "example.com" and "example.co.uk". Entries are compared ignoring case, and removing
+// "yahoo.*" does not affect an entry "yahoo.com", and the other way around. Passing the option several times
+// applies every call in order.
+//
+// Example:
+//
+//	parser := NewParser(WithFreeProviders([]string{"freemail.*"}, []string{"fastmail.com"}))
+//	e, _ := parser.Parse("john.doe@freemail.co.id")
+//	fmt.Println(IsFreeProvider(e)) // Output: true
+func WithFreeProviders(add, remove []string) Option {
+	return func(o *options) {
+		if o.freeProviders == nil {
+			o.freeProviders = loadFreeProviders()
+		}
+		providers := make(map[string]bool, len(o.freeProviders)+len(add))
+		for entry := range o.freeProviders {
+			providers[entry] = true
+		}
+		for _, entry := range add {
+			providers[strings.ToLower(strings.TrimSpace(entry))] = true
+		}
+		for _, entry := range remove {
+			delete(providers, strings.ToLower(strings.TrimSpace(entry)))
+		}
+		o.freeProviders = providers
+	}
+}
+
+// IsFreeProvider reports whether the domain of e belongs to a consumer mailbox provider giving out free
+// addresses, so that business flows can require a work address. The providers are embedded and can be
+// changed per instance with WithFreeProviders; subdomains of a provider do not match.
+//
+// Example:
+//
+//	e, err := New("john@yahoo.co.jp")
+//	if err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	fmt.Println(IsFreeProvider(e)) // Output: true
+func IsFreeProvider(e BEmailParts) bool {
+	return optionsOf(e).isFreeProvider(e.Domain())
+}
+
+// WithRejectFreeProviders rejects addresses whose domain belongs to a free mailbox provider, as reported by
+// IsFreeProvider, with ErrEmailDomainFreeProvider, so that business flows can require a work address. The
+// providers can be changed with WithFreeProviders. It also applies to the setters.
+//
+// Example:
+//
+//	_, err := New("john@gmail.com", WithRejectFreeProviders())
+//	fmt.Println(errors.Is(err, ErrEmailDomainFreeProvider)) // Output: true
+func WithRejectFreeProviders() Option {
+	return func(o *options) {
+		o.rejectFreeProviders = true
+	}
+}
+
+// isFreeProvider reports whether domain matches an entry of the free providers of o.
+func (o *options) isFreeProvider(domain string) bool {
+	if isAddressLiteral(domain) {
+		return false
+	}
+	providers := o.freeProviders
+	if providers == nil {
+		providers = loadFreeProviders()
+	}
+	domain = strings.ToLower(domain)
+	if providers[domain] {
+		return true
+	}
+	if registrableDomain(domain) != domain {
+		return false
+	}
+	name := domain[:strings.Index(domain, domainSeparator)]
+	return providers[name+freeProviderWildcard]
+}
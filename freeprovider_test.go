@@ -0,0 +1,132 @@
+package bemailparts_test
+
+import (
+	"errors"
+	"github.com/bearaujus/bemailparts"
+	"sort"
+	"testing"
+)
+
+func TestIsFreeProvider(t *testing.T) {
+	tests := []struct {
+		name  string
+		email string
+		opts  []bemailparts.Option
+		want  bool
+	}{
+		{name: "provider", email: "john@gmail.com", want: true},
+		{name: "case is ignored", email: "john@QQ.com", want: true},
+		{name: "any public suffix", email: "john@yahoo.co.jp", want: true},
+		{name: "any public suffix again", email: "john@yandex.ru", want: true},
+		{name: "work address", email: "john@example.com"},
+		{name: "subdomain of a provider", email: "john@mail.gmail.com"},
+		{name: "subdomain under any public suffix", email: "john@mail.yahoo.com"},
+		{name: "name of a provider", email: "john@gmail.example.com"},
+		{
+			name:  "address literal",
+			email: "john@[192.0.2.1]",
+			opts:  []bemailparts.Option{bemailparts.WithAllowIPDomain()},
+		},
+		{
+			name:  "single label domain",
+			email: "john@yahoo",
+			opts:  []bemailparts.Option{bemailparts.WithAllowSingleLabelDomain()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := bemailparts.New(tt.email, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := bemailparts.IsFreeProvider(e); got != tt.want {
+				t.Errorf("IsFreeProvider() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("test options change the providers per instance", func(t *testing.T) {
+		opt := bemailparts.WithFreeProviders([]string{" FreeMail.* "}, []string{"gmail.com"})
+		tests := []struct {
+			email string
+			opts  []bemailparts.Option
+			want  bool
+		}{
+			{email: "john@freemail.co.id", opts: []bemailparts.Option{opt}, want: true},
+			{email: "john@gmail.com", opts: []bemailparts.Option{opt}},
+			{email: "john@qq.com", opts: []bemailparts.Option{opt}, want: true},
+			{
+				email: "john@gmail.com",
+				opts:  []bemailparts.Option{opt, bemailparts.WithFreeProviders([]string{"gmail.com"}, nil)},
+				want:  true,
+			},
+			{email: "john@freemail.co.id"},
+			{email: "john@gmail.com", want: true},
+		}
+		for _, tt := range tests {
+			e, err := bemailparts.New(tt.email, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := bemailparts.IsFreeProvider(e); got != tt.want {
+				t.Errorf("IsFreeProvider() = %v for %v with %d options, want %v", got, tt.email, len(tt.opts), tt.want)
+			}
+		}
+	})
+}
+
+func TestWithRejectFreeProviders(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		opts    []bemailparts.Option
+		wantErr error
+	}{
+		{name: "provider", email: "john@gmail.com", wantErr: bemailparts.ErrEmailDomainFreeProvider},
+		{name: "any public suffix", email: "john@yahoo.co.jp", wantErr: bemailparts.ErrEmailDomainFreeProvider},
+		{name: "work address", email: "john@example.co"},
+		{
+			name:  "provider removed",
+			email: "john@gmail.com",
+			opts:  []bemailparts.Option{bemailparts.WithFreeProviders(nil, []string{"gmail.com"})},
+		},
+		{
+			name:    "provider added",
+			email:   "john@freemail.co.id",
+			opts:    []bemailparts.Option{bemailparts.WithFreeProviders([]string{"freemail.*"}, nil)},
+			wantErr: bemailparts.ErrEmailDomainFreeProvider,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]bemailparts.Option{bemailparts.WithRejectFreeProviders()}, tt.opts...)
+			_, err := bemailparts.New(tt.email, opts...)
+			if !errors.Is(err, tt.wantErr) || (err != nil) != (tt.wantErr != nil) {
+				t.Errorf("New() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("test error explains the rejection", func(t *testing.T) {
+		_, err := bemailparts.New("john@gmail.com", bemailparts.WithRejectFreeProviders())
+		want := "domain is a free mailbox provider at position 5 in domain"
+		if err == nil || err.Error() != want {
+			t.Errorf("New() error = %v, want %v", err, want)
+		}
+	})
+}
+
+func TestFreeProviders(t *testing.T) {
+	got := bemailparts.FreeProviders()
+	if !sort.StringsAreSorted(got) {
+		t.Errorf("FreeProviders() = %v, want sorted", got)
+	}
+	i := sort.SearchStrings(got, "yahoo.*")
+	if i == len(got) || got[i] != "yahoo.*" {
+		t.Fatalf("FreeProviders() = %v, want yahoo.*", got)
+	}
+	got[i] = "changed"
+	if again := bemailparts.FreeProviders(); again[i] != "yahoo.*" {
+		t.Error("FreeProviders() result aliases the providers")
+	}
+}
@@ -0,0 +1,121 @@
+package bemailparts
+
+import "time"
+
+// Mutation is a change made to a BEmailParts by one of its setters, as recorded with WithHistory.
+type Mutation struct {
+	// Time is when the change was made, as read from the Clock of WithClock.
+	Time time.Time
+
+	// Field is the part that was set: FieldUsername, FieldDomain, FieldDomainName, FieldTLD or
+	// FieldSubdomain.
+	Field Field
+
+	// Before and After are the full address before and after the change.
+	Before string
+	After  string
+}
+
+// WithHistory makes every BEmailParts record the changes made by SetUsername, SetDomain, SetDomainName,
+// SetDomainTLD and SetSubdomain, available through History, so that interactive tools can show how an
+// address was edited.
+// Failed calls and calls leaving the address unchanged are not recorded. Recorded changes can be reverted
+// with Undo and reapplied with Redo.
+//
+// Example:
+//
+//	emailParts, err := New("john.doe@example.com", WithHistory())
+//	if err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	_ = emailParts.SetDomainTLD("org")
+//	for _, m := range History(emailParts) {
+//	    fmt.Println(m.Field, m.Before, m.After) // Output: tld john.doe@example.com john.doe@example.org
+//	}
+func WithHistory() Option {
+	return func(o *options) {
+		o.history = true
+	}
+}
+
+// edit is a recorded change, with the username and domain before and after it.
+type edit struct {
+	mutation                     Mutation
+	beforeUsername, beforeDomain string
+	afterUsername, afterDomain   string
+}
+
+// History returns the changes made by the setters of e, oldest first, when it was created with WithHistory,
+// or nil otherwise. See WithHistory for an example.
+func History(e BEmailParts) []Mutation {
+	p, ok := e.(*bEmailParts)
+	if !ok || p.history == nil {
+		return nil
+	}
+	mutations := make([]Mutation, len(p.history))
+	for i, h := range p.history {
+		mutations[i] = h.mutation
+	}
+	return mutations
+}
+
+// Undo reverts the last change recorded in the History of e, removing it from History so that Redo can
+// reapply it. Changes made by setters afterwards discard the changes that could be redone. Returns false if
+// there is no change to undo, including when e was created without WithHistory.
+//
+// Example:
+//
+//	emailParts, err := New("john.doe@example.com", WithHistory())
+//	if err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	_ = emailParts.SetDomainTLD("org")
+//	Undo(emailParts)
+//	fmt.Println(emailParts.Email()) // Output: john.doe@example.com
+//	Redo(emailParts)
+//	fmt.Println(emailParts.Email()) // Output: john.doe@example.org
+func Undo(e BEmailParts) bool {
+	p, ok := e.(*bEmailParts)
+	if !ok || len(p.history) == 0 {
+		return false
+	}
+	last := p.history[len(p.history)-1]
+	p.history = p.history[:len(p.history)-1]
+	p.redo = append(p.redo, last)
+	p.username, p.domain = last.beforeUsername, last.beforeDomain
+	return true
+}
+
+// Redo reapplies the last change of e reverted by Undo, adding it back to its History. Returns false if
+// there is no change to redo. See Undo for an example.
+func Redo(e BEmailParts) bool {
+	p, ok := e.(*bEmailParts)
+	if !ok || len(p.redo) == 0 {
+		return false
+	}
+	next := p.redo[len(p.redo)-1]
+	p.redo = p.redo[:len(p.redo)-1]
+	p.history = append(p.history, next)
+	p.username, p.domain = next.afterUsername, next.afterDomain
+	return true
+}
+
+// update sets the username and domain of e, recording the change as a change of field if WithHistory is set.
+// A recorded change discards the changes that could be redone. Nothing is allocated without WithHistory.
+func (e *bEmailParts) update(field Field, username, domain string) {
+	changed := username != e.username || domain != e.domain
+	if e.opts.history && changed {
+		e.history = append(e.history, edit{
+			mutation: Mutation{Time: e.opts.now(), Field: field, Before: e.Email(),
+				After: generateEmail(username, domain)},
+			beforeUsername: e.username,
+			beforeDomain:   e.domain,
+			afterUsername:  username,
+			afterDomain:    domain,
+		})
+		e.redo = nil
+	}
+	e.username, e.domain = username, domain
+}
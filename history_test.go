@@ -0,0 +1,106 @@
+package bemailparts_test
+
+import (
+	"github.com/bearaujus/bemailparts"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHistory(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	e, err := bemailparts.New("john.doe@example.com", bemailparts.WithHistory(), bemailparts.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := clock.now
+
+	if err = e.SetDomainTLD("org"); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(time.Minute)
+	if err = e.SetUsername("jane.doe"); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(time.Minute)
+	if err = e.SetDomainName("example"); err != nil {
+		t.Fatal(err)
+	}
+	if err = e.SetDomain("-invalid.com"); err == nil {
+		t.Fatal("SetDomain() error = nil, want an error")
+	}
+	if err = e.SetDomain("mail.example.net"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []bemailparts.Mutation{
+		{Time: start, Field: bemailparts.FieldTLD, Before: "john.doe@example.com", After: "john.doe@example.org"},
+		{Time: start.Add(time.Minute), Field: bemailparts.FieldUsername, Before: "john.doe@example.org", After: "jane.doe@example.org"},
+		{Time: start.Add(2 * time.Minute), Field: bemailparts.FieldDomain, Before: "jane.doe@example.org", After: "jane.doe@mail.example.net"},
+	}
+	if got := bemailparts.History(e); !reflect.DeepEqual(got, want) {
+		t.Errorf("History() got = %+v, want %+v", got, want)
+	}
+
+	t.Run("test undo and redo", func(t *testing.T) {
+		e, err := bemailparts.New("john.doe@example.com", bemailparts.WithHistory())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bemailparts.Undo(e) || bemailparts.Redo(e) {
+			t.Fatal("Undo() or Redo() got true without changes")
+		}
+		if err = e.SetDomainTLD("org"); err != nil {
+			t.Fatal(err)
+		}
+		if err = e.SetUsername("jane.doe"); err != nil {
+			t.Fatal(err)
+		}
+
+		steps := []struct {
+			name        string
+			do          func() bool
+			want        string
+			wantHistory int
+		}{
+			{name: "undo username", do: func() bool { return bemailparts.Undo(e) }, want: "john.doe@example.org", wantHistory: 1},
+			{name: "undo tld", do: func() bool { return bemailparts.Undo(e) }, want: "john.doe@example.com", wantHistory: 0},
+			{name: "redo tld", do: func() bool { return bemailparts.Redo(e) }, want: "john.doe@example.org", wantHistory: 1},
+		}
+		for _, step := range steps {
+			if !step.do() {
+				t.Fatalf("%s got false", step.name)
+			}
+			if e.Email() != step.want || len(bemailparts.History(e)) != step.wantHistory {
+				t.Errorf("%s: Email() = %v with %d changes, want %v with %d", step.name, e.Email(),
+					len(bemailparts.History(e)), step.want, step.wantHistory)
+			}
+		}
+
+		if err = e.SetDomainName("test"); err != nil {
+			t.Fatal(err)
+		}
+		if bemailparts.Redo(e) {
+			t.Error("Redo() got true after a new change")
+		}
+		if e.Email() != "john.doe@test.org" || e.DomainName() != "test" || e.DomainTLD() != ".org" {
+			t.Errorf("Email() got = %v, want %v", e.Email(), "john.doe@test.org")
+		}
+	})
+
+	t.Run("test without option", func(t *testing.T) {
+		e, err := bemailparts.New("john.doe@example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = e.SetUsername("jane.doe"); err != nil {
+			t.Fatal(err)
+		}
+		if got := bemailparts.History(e); got != nil {
+			t.Errorf("History() got = %+v, want nil", got)
+		}
+		if bemailparts.Undo(e) {
+			t.Error("Undo() got true without WithHistory")
+		}
+	})
+}
@@ -0,0 +1,20 @@
+package bemailparts
+
+import "regexp"
+
+// The patterns below mirror the valid email address definition of the WHATWG HTML Living Standard
+// (input[type=email]), split into parts.
+const (
+	html5UsernameChars   = "[a-zA-Z0-9.!#$%&'*+/=?^_`{|}~-]+"
+	html5LabelPattern    = `[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?`
+	html5HostPattern     = html5LabelPattern + `(?:\` + domainSeparator + html5LabelPattern + `)*`
+	html5UsernamePattern = `^` + html5UsernameChars + `$`
+	html5DomainPattern   = `^` + html5HostPattern + `$`
+	html5EmailPattern    = `^` + html5UsernameChars + emailSeparator + html5HostPattern + `$`
+)
+
+var (
+	html5UsernameRegex = regexp.MustCompile(html5UsernamePattern)
+	html5DomainRegex   = regexp.MustCompile(html5DomainPattern)
+	html5EmailRegex    = regexp.MustCompile(html5EmailPattern)
+)
@@ -0,0 +1,84 @@
+package bemailparts_test
+
+import (
+	"github.com/bearaujus/bemailparts"
+	"strings"
+	"testing"
+)
+
+func TestNewWithHTML5Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		wantErr bool
+	}{
+		{
+			name:  "success",
+			email: "john.doe@example.com",
+		},
+		{
+			name:  "success single label domain",
+			email: "admin@localhost",
+		},
+		{
+			name:  "success specials and dots accepted by browsers",
+			email: ".john..doe!#$'@example.com",
+		},
+		{
+			name:  "success numeric tld",
+			email: "john@example.123",
+		},
+		{
+			name:  "success 63 character label",
+			email: "john@" + strings.Repeat("a", 63) + ".com",
+		},
+		{
+			name:    "error 64 character label",
+			email:   "john@" + strings.Repeat("a", 64) + ".com",
+			wantErr: true,
+		},
+		{
+			name:    "error label with leading hyphen",
+			email:   "john@-example.com",
+			wantErr: true,
+		},
+		{
+			name:    "error empty label",
+			email:   "john@example..com",
+			wantErr: true,
+		},
+		{
+			name:    "error quoted local part",
+			email:   `"john doe"@example.com`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bemailparts.New(tt.email, bemailparts.WithHTML5Validation())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got.Email() != tt.email {
+				t.Errorf("New() got = %v, want %v", got, tt.email)
+			}
+		})
+	}
+
+	t.Run("test single label domain parts", func(t *testing.T) {
+		e, err := bemailparts.New("admin@localhost", bemailparts.WithHTML5Validation())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.DomainName() != "localhost" || e.DomainTLD() != "" || e.DomainTLDWithoutDot() != "" {
+			t.Errorf("DomainName(), DomainTLD() got = %v, %v, want localhost and empty", e.DomainName(), e.DomainTLD())
+		}
+		if err = e.SetDomainTLD("local"); err != nil {
+			t.Fatal(err)
+		}
+		if e.Email() != "admin@localhost.local" {
+			t.Errorf("Email() got = %v, want %v", e.Email(), "admin@localhost.local")
+		}
+	})
+}
@@ -0,0 +1,154 @@
+package bemailparts
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// acePrefix marks a label encoded with Punycode, an A-label (RFC 5890 section 2.3.2.1).
+const acePrefix = "xn--"
+
+// idnDotReplacer maps the full stops that IDNA treats as label separators to ".".
+var idnDotReplacer = strings.NewReplacer("。", ".", "．", ".", "｡", ".")
+
+// ToASCII converts domain to its ASCII-compatible encoding (ACE), e.g. "bücher.de" to "xn--bcher-kva.de".
+// The domain is lowercased, and each label must be in Unicode Normalization Form C and made of code points
+// that RFC 5892 lists as PVALID, such as lowercase letters, marks and digits; fullwidth forms such as "ａ"
+// are not. Labels must not start with a mark, start or end with a hyphen, or encode to more than 63 octets.
+// This is a subset of IDNA2008: code points valid only in context, such as the zero width joiner, are
+// rejected, and the Bidi rule of RFC 5893 is not applied. ASCII labels are lowercased and otherwise kept;
+// existing A-labels must decode to a valid label.
+//
+// Parameters:
+//
+//	domain: A domain in Unicode or ACE form, or a mix of both.
+//
+// Returns:
+//   - The domain in ACE form.
+//   - ErrInvalidIDN if a label cannot be converted.
+//
+// Example:
+//
+//	domain, err := ToASCII("bücher.de")
+//	if err != nil {
+//	    log.Fatalf("Invalid domain: %v", err)
+//	}
+//
+//	fmt.Println(domain) // Output: xn--bcher-kva.de
+func ToASCII(domain string) (string, error) {
+	labels := strings.Split(strings.ToLower(idnDotReplacer.Replace(domain)), domainSeparator)
+	for i, label := range labels {
+		ascii, err := labelToASCII(label)
+		if err != nil {
+			return "", err
+		}
+		labels[i] = ascii
+	}
+	return strings.Join(labels, domainSeparator), nil
+}
+
+// ToUnicode converts domain to its Unicode form, e.g. "xn--bcher-kva.de" to "bücher.de". It is the inverse
+// of ToASCII and validates the labels the same way.
+//
+// Example:
+//
+//	domain, err := ToUnicode("xn--bcher-kva.de")
+//	if err != nil {
+//	    log.Fatalf("Invalid domain: %v", err)
+//	}
+//
+//	fmt.Println(domain) // Output: bücher.de
+func ToUnicode(domain string) (string, error) {
+	labels := strings.Split(strings.ToLower(idnDotReplacer.Replace(domain)), domainSeparator)
+	for i, label := range labels {
+		if _, err := labelToASCII(label); err != nil {
+			return "", err
+		}
+		if strings.HasPrefix(label, acePrefix) {
+			// labelToASCII has checked that the label decodes.
+			labels[i], _ = punycodeDecode(label[len(acePrefix):])
+		}
+	}
+	return strings.Join(labels, domainSeparator), nil
+}
+
+// labelToASCII converts a lowercased label to its A-label if it is not ASCII, and validates A-labels.
+// Empty labels are returned as is, for the domain validation to reject.
+func labelToASCII(label string) (string, error) {
+	if isASCII(label) {
+		if !strings.HasPrefix(label, acePrefix) {
+			return label, nil
+		}
+		decoded, err := punycodeDecode(label[len(acePrefix):])
+		if err != nil || isASCII(decoded) {
+			return "", ErrInvalidIDN
+		}
+		if encoded, err := encodeULabel(decoded); err != nil || encoded != label {
+			return "", ErrInvalidIDN
+		}
+		return label, nil
+	}
+	return encodeULabel(label)
+}
+
+// encodeULabel validates a non-ASCII label and encodes it to its A-label.
+func encodeULabel(label string) (string, error) {
+	if !utf8.ValidString(label) || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+		return "", ErrInvalidIDN
+	}
+	// Hyphens in the third and fourth positions are reserved for tagged labels such as A-labels.
+	if runes := []rune(label); len(runes) >= 4 && runes[2] == '-' && runes[3] == '-' {
+		return "", ErrInvalidIDN
+	}
+	tables := loadIDNATables()
+	for i, r := range label {
+		if i == 0 && unicode.IsMark(r) || !tables.isPVALID(r) {
+			return "", ErrInvalidIDN
+		}
+	}
+	if !tables.isNFC(label) {
+		return "", ErrInvalidIDN
+	}
+
+	encoded, err := punycodeEncode(label)
+	if err != nil {
+		return "", ErrInvalidIDN
+	}
+	encoded = acePrefix + encoded
+	if len(encoded) > maxLabelLength {
+		return "", ErrInvalidIDN
+	}
+	return encoded, nil
+}
+
+// WithIDN accepts internationalized domains such as "bücher.de" and validates A-labels such as
+// "xn--bcher-kva.de". Domains are validated in their ACE form, as converted by ToASCII, under the selected
+// syntax, and keep the form they were given in. Use ToASCII to hand them to systems that expect ASCII.
+// Internationalized TLDs such as "рф" or "中国" are accepted in DomainTLD and SetDomainTLD too; their ACE
+// forms, such as "xn--p1ai", are accepted even without this option.
+//
+// Example:
+//
+//	emailParts, err := New("jürgen@bücher.de", WithIDN())
+//	if err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	fmt.Println(emailParts.Domain()) // Output: bücher.de
+//	ascii, _ := ToASCII(emailParts.Domain())
+//	fmt.Println(ascii)               // Output: xn--bcher-kva.de
+func WithIDN() Option {
+	return func(o *options) {
+		o.idn = true
+	}
+}
+
+// toASCIIDomain returns the ACE form of domain if WithIDN is set and domain needs converting or checking,
+// and domain itself otherwise.
+func (o *options) toASCIIDomain(domain string) (string, error) {
+	if !o.idn || (isASCII(domain) && !strings.Contains(strings.ToLower(domain), acePrefix)) {
+		return domain, nil
+	}
+	return ToASCII(domain)
+}
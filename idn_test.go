@@ -0,0 +1,188 @@
+package bemailparts_test
+
+import (
+	"errors"
+	"github.com/bearaujus/bemailparts"
+	"strings"
+	"testing"
+)
+
+func TestToASCII(t *testing.T) {
+	tests := []struct {
+		name    string
+		unicode string
+		ascii   string
+		wantErr bool
+	}{
+		{name: "german", unicode: "bücher.de", ascii: "xn--bcher-kva.de"},
+		{name: "german city", unicode: "münchen.de", ascii: "xn--mnchen-3ya.de"},
+		{name: "chinese", unicode: "例子.中国", ascii: "xn--fsqu00a.xn--fiqs8s"},
+		{name: "russian", unicode: "пример.рф", ascii: "xn--e1afmkfd.xn--p1ai"},
+		{name: "rfc 3492 chinese sample", unicode: "他们为什么不说中文.com", ascii: "xn--ihqwcrb4cv8a8dqg056pqjye.com"},
+		{name: "error punctuation", unicode: "ليهمابتكلموشعربي؟.com", wantErr: true},
+		{name: "ascii", unicode: "example.com", ascii: "example.com"},
+		{name: "error leading hyphen", unicode: "-bücher.de", wantErr: true},
+		{name: "error hyphens in third and fourth position", unicode: "bü--cher.de", wantErr: true},
+		{name: "error symbol", unicode: "☃.com", wantErr: true},
+		{name: "error leading mark", unicode: "́bücher.de", wantErr: true},
+		{name: "error label too long", unicode: strings.Repeat("ü", 60) + ".de", wantErr: true},
+		{name: "sharp s", unicode: "straße.de", ascii: "xn--strae-oqa.de"},
+		{name: "composed accent", unicode: "caf\u00e9.fr", ascii: "xn--caf-dma.fr"},
+		{name: "error decomposed accent", unicode: "cafe\u0301.fr", wantErr: true},
+		{name: "error fullwidth letters", unicode: "ＡＢＣ.com", wantErr: true},
+		{name: "error titlecase digraph", unicode: "ǅx.com", wantErr: true},
+		{name: "error zero width joiner", unicode: "ab\u200dc.com", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bemailparts.ToASCII(tt.unicode)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToASCII() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, bemailparts.ErrInvalidIDN) {
+					t.Errorf("ToASCII() error = %v, want %v", err, bemailparts.ErrInvalidIDN)
+				}
+				return
+			}
+			if got != tt.ascii {
+				t.Errorf("ToASCII() got = %v, want %v", got, tt.ascii)
+			}
+			if back, err := bemailparts.ToUnicode(got); err != nil || back != tt.unicode {
+				t.Errorf("ToUnicode() got = %v, %v, want %v", back, err, tt.unicode)
+			}
+		})
+	}
+
+	t.Run("test mapping", func(t *testing.T) {
+		if got, _ := bemailparts.ToASCII("BÜCHER。DE"); got != "xn--bcher-kva.de" {
+			t.Errorf("ToASCII() got = %v, want xn--bcher-kva.de", got)
+		}
+	})
+
+	t.Run("test invalid a-labels", func(t *testing.T) {
+		for _, domain := range []string{"xn--.de", "xn--abc.de", "xn--bcher-kva!.de", "xn--example.com", "xn--" + strings.Repeat("z", 60) + ".com"} {
+			if _, err := bemailparts.ToUnicode(domain); !errors.Is(err, bemailparts.ErrInvalidIDN) {
+				t.Errorf("ToUnicode(%q) error = %v, want %v", domain, err, bemailparts.ErrInvalidIDN)
+			}
+		}
+	})
+}
+
+func TestWithIDN(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		opts    []bemailparts.Option
+		wantErr error
+	}{
+		{name: "success unicode domain", email: "juergen@bücher.de"},
+		{name: "success ace domain", email: "juergen@xn--bcher-kva.de"},
+		{name: "success with unicode username", email: "jürgen@bücher.de", opts: []bemailparts.Option{bemailparts.WithUnicodeLocalPart()}},
+		{name: "success rfc5321", email: "juergen@bücher.de", opts: []bemailparts.Option{bemailparts.WithRFC5321()}},
+		{name: "success html5", email: "juergen@bücher.de", opts: []bemailparts.Option{bemailparts.WithHTML5Validation()}},
+		{name: "success cyrillic tld", email: "ivan@пример.рф"},
+		{name: "success chinese tld", email: "li@例子.中国"},
+		{name: "success ace tld", email: "ivan@xn--e1afmkfd.xn--p1ai"},
+		{name: "success cyrillic tld with strict", email: "ivan@пример.рф", opts: []bemailparts.Option{bemailparts.WithStrict()}},
+		{name: "error unicode username without option", email: "jürgen@bücher.de", wantErr: bemailparts.ErrInvalidEmailUsernameFormat},
+		{name: "error invalid label", email: "juergen@☃.com", wantErr: bemailparts.ErrInvalidIDN},
+		{name: "error invalid a-label", email: "juergen@xn--abc.com", wantErr: bemailparts.ErrInvalidIDN},
+		{name: "error invalid ace tld", email: "ivan@example.xn--abc", wantErr: bemailparts.ErrInvalidIDN},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]bemailparts.Option{bemailparts.WithIDN()}, tt.opts...)
+			got, err := bemailparts.New(tt.email, opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr == nil && got.Email() != tt.email {
+				t.Errorf("New() got = %v, want %v", got, tt.email)
+			}
+		})
+	}
+
+	t.Run("test unicode domain rejected without option", func(t *testing.T) {
+		if _, err := bemailparts.New("juergen@bücher.de"); err == nil {
+			t.Error("expecting an error on New() but got nil")
+		}
+	})
+
+	t.Run("test set unicode domain parts", func(t *testing.T) {
+		e, err := bemailparts.New("juergen@example.de", bemailparts.WithIDN())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = e.SetDomainName("bücher"); err != nil {
+			t.Fatal(err)
+		}
+		if e.Domain() != "bücher.de" || e.DomainName() != "bücher" {
+			t.Errorf("Domain(), DomainName() got = %v, %v, want bücher.de, bücher", e.Domain(), e.DomainName())
+		}
+		if err = bemailparts.CheckInvariants(e, bemailparts.WithIDN()); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("test unicode tld", func(t *testing.T) {
+		e, err := bemailparts.New("ivan@пример.рф", bemailparts.WithIDN())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.DomainName() != "пример" || e.DomainTLD() != ".рф" || e.DomainTLDWithoutDot() != "рф" {
+			t.Errorf("DomainName(), DomainTLD() got = %v, %v, want пример, .рф", e.DomainName(), e.DomainTLD())
+		}
+		if err = e.SetDomainTLD("中国"); err != nil {
+			t.Fatal(err)
+		}
+		if e.Email() != "ivan@пример.中国" {
+			t.Errorf("Email() got = %v, want ivan@пример.中国", e.Email())
+		}
+		if err = e.SetDomainTLD(".xn--p1ai"); err != nil {
+			t.Fatal(err)
+		}
+		if e.Domain() != "пример.xn--p1ai" {
+			t.Errorf("Domain() got = %v, want пример.xn--p1ai", e.Domain())
+		}
+		if err = e.SetDomainTLD("☃"); !errors.Is(err, bemailparts.ErrInvalidIDN) {
+			t.Errorf("SetDomainTLD() error = %v, want %v", err, bemailparts.ErrInvalidIDN)
+		}
+		if err = bemailparts.CheckInvariants(e, bemailparts.WithIDN()); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("test ace tld without option", func(t *testing.T) {
+		e, err := bemailparts.New("ivan@example.xn--p1ai")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.DomainTLD() != ".xn--p1ai" {
+			t.Errorf("DomainTLD() got = %v, want .xn--p1ai", e.DomainTLD())
+		}
+		if _, err = bemailparts.New("ivan@example.рф"); err == nil {
+			t.Error("expecting an error on New() but got nil")
+		}
+	})
+}
+
+func FuzzToASCII(f *testing.F) {
+	for _, seed := range []string{"bücher.de", "例子.中国", "xn--bcher-kva.de", "example.com", "a-ü.b"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, domain string) {
+		ascii, err := bemailparts.ToASCII(domain)
+		if err != nil {
+			return
+		}
+		unicode, err := bemailparts.ToUnicode(ascii)
+		if err != nil {
+			t.Fatalf("ToUnicode(%q) error = %v", ascii, err)
+		}
+		if again, err := bemailparts.ToASCII(unicode); err != nil || again != ascii {
+			t.Errorf("ToASCII(%q) got = %v, %v, want %v", unicode, again, err, ascii)
+		}
+	})
+}
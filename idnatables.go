@@ -0,0 +1,187 @@
+package bemailparts
+
+import (
+	_ "embed"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// idnaTableList holds the code point properties ToASCII checks labels against, derived from the Unicode
+// Character Database; see its header for the format.
+//
+//go:embed idna_tables.txt
+var idnaTableList string
+
+// idnaTables are the parsed entries of idnaTableList.
+type idnaTables struct {
+	// pvalid holds the sorted, disjoint ranges of non-ASCII PVALID code points.
+	pvalid []runeRange
+
+	// ccc holds the sorted, disjoint ranges of code points with a non-zero canonical combining class.
+	ccc []runeRange
+
+	// decompositions maps code points to their full canonical decomposition.
+	decompositions map[rune][]rune
+
+	// compositions maps the two code points of a primary composite to it, and composable holds the second
+	// code points of the pairs.
+	compositions map[[2]rune]rune
+	composable   map[rune]bool
+}
+
+type runeRange struct {
+	lo, hi rune
+	value  int
+}
+
+var (
+	idnaTablesOnce sync.Once
+	idnaTablesData *idnaTables
+)
+
+// loadIDNATables parses idnaTableList on first use.
+func loadIDNATables() *idnaTables {
+	idnaTablesOnce.Do(func() {
+		t := &idnaTables{
+			decompositions: map[rune][]rune{},
+			compositions:   map[[2]rune]rune{},
+			composable:     map[rune]bool{},
+		}
+		for _, line := range strings.Split(idnaTableList, "\n") {
+			fields := strings.Fields(line)
+			if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+				continue
+			}
+			switch fields[0] {
+			case "pvalid":
+				lo, hi := parseRuneRange(fields[1])
+				t.pvalid = append(t.pvalid, runeRange{lo: lo, hi: hi})
+			case "ccc":
+				lo, hi := parseRuneRange(fields[1])
+				class, _ := strconv.Atoi(fields[2])
+				t.ccc = append(t.ccc, runeRange{lo: lo, hi: hi, value: class})
+			case "compose", "decompose":
+				runes := make([]rune, len(fields)-1)
+				for i, field := range fields[1:] {
+					runes[i] = parseRune(field)
+				}
+				t.decompositions[runes[0]] = runes[1:]
+				if fields[0] == "compose" {
+					t.compositions[[2]rune{runes[1], runes[2]}] = runes[0]
+					t.composable[runes[2]] = true
+				}
+			}
+		}
+		// Expand decompositions whose parts decompose further, so that each is looked up once.
+		for r := range t.decompositions {
+			t.decompositions[r] = t.decompose(nil, r)
+		}
+		idnaTablesData = t
+	})
+	return idnaTablesData
+}
+
+func parseRuneRange(s string) (rune, rune) {
+	if i := strings.Index(s, ".."); i >= 0 {
+		return parseRune(s[:i]), parseRune(s[i+2:])
+	}
+	r := parseRune(s)
+	return r, r
+}
+
+func parseRune(s string) rune {
+	r, _ := strconv.ParseUint(s, 16, 32)
+	return rune(r)
+}
+
+// lookup returns the value of the range in ranges holding r, and whether there is one.
+func lookup(ranges []runeRange, r rune) (int, bool) {
+	i := sort.Search(len(ranges), func(i int) bool { return ranges[i].hi >= r })
+	if i < len(ranges) && ranges[i].lo <= r {
+		return ranges[i].value, true
+	}
+	return 0, false
+}
+
+// isPVALID reports whether r may appear in a U-label: a lowercase ASCII letter, digit or hyphen, or a code
+// point PVALID under RFC 5892.
+func (t *idnaTables) isPVALID(r rune) bool {
+	if r < 0x80 {
+		return 'a' <= r && r <= 'z' || '0' <= r && r <= '9' || r == '-'
+	}
+	_, ok := lookup(t.pvalid, r)
+	return ok
+}
+
+func (t *idnaTables) combiningClass(r rune) int {
+	class, _ := lookup(t.ccc, r)
+	return class
+}
+
+// decompose appends the full canonical decomposition of r to dst.
+func (t *idnaTables) decompose(dst []rune, r rune) []rune {
+	parts, ok := t.decompositions[r]
+	if !ok {
+		return append(dst, r)
+	}
+	for _, part := range parts {
+		dst = t.decompose(dst, part)
+	}
+	return dst
+}
+
+// isNFC reports whether s is in Unicode Normalization Form C. Hangul syllables are taken as is: the
+// conjoining jamo they compose from are not PVALID, so labels holding them are rejected anyway.
+func (t *idnaTables) isNFC(s string) bool {
+	quick := true
+	for _, r := range s {
+		if _, ok := t.decompositions[r]; ok || t.composable[r] || t.combiningClass(r) != 0 {
+			quick = false
+			break
+		}
+	}
+	return quick || t.nfc(s) == s
+}
+
+// nfc returns s in Normalization Form C: canonically decomposed, reordered, and recomposed (UAX #15).
+func (t *idnaTables) nfc(s string) string {
+	var runes []rune
+	for _, r := range s {
+		runes = t.decompose(runes, r)
+	}
+	// Reorder each run of non-starters by combining class, keeping the order of equal classes.
+	for start := 0; start < len(runes); {
+		if t.combiningClass(runes[start]) == 0 {
+			start++
+			continue
+		}
+		end := start
+		for end < len(runes) && t.combiningClass(runes[end]) != 0 {
+			end++
+		}
+		run := runes[start:end]
+		sort.SliceStable(run, func(i, j int) bool { return t.combiningClass(run[i]) < t.combiningClass(run[j]) })
+		start = end
+	}
+
+	composed := runes[:0]
+	starter, lastClass := -1, 0
+	for _, r := range runes {
+		class := t.combiningClass(r)
+		// r is blocked from the last starter by a character between them of class 0 or not lower than r's.
+		if starter >= 0 && (len(composed)-1 == starter || lastClass != 0 && lastClass < class) {
+			if c, ok := t.compositions[[2]rune{composed[starter], r}]; ok {
+				composed[starter] = c
+				continue
+			}
+		}
+		if class == 0 {
+			starter = len(composed)
+		}
+		composed = append(composed, r)
+		lastClass = class
+	}
+	return string(composed)
+}
@@ -0,0 +1,313 @@
+package bemailparts
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Severity is how serious an Issue found by Inspect is.
+type Severity int
+
+const (
+	// SeverityWarning is a problem that does not make the address invalid, such as a lookalike domain.
+	SeverityWarning Severity = iota + 1
+
+	// SeverityError is a problem that makes the address invalid.
+	SeverityError
+)
+
+// String returns the lowercase name of the severity, e.g. "error".
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// Issue is a problem found by Inspect.
+type Issue struct {
+	Severity Severity
+
+	// Part is PartLocal or PartDomain, or an empty string if the issue concerns the whole address.
+	Part string
+
+	// Offset is the byte offset in Report.Email where the issue is, or -1 if it is not known.
+	Offset int
+
+	// Msg describes the issue, e.g. "invalid character ' '".
+	Msg string
+
+	// Code identifies the issue, e.g. CodeInvalidEmailUsernameFormat.
+	Code ErrorCode
+}
+
+// Report lists the issues Inspect found in an address.
+type Report struct {
+	// Email is the address that was inspected, after lowercasing with WithLowercase, removing comments with
+	// WithComments and rewriting obsolete forms with WithObsoleteSyntax.
+	Email string
+
+	// Issues holds the issues found, in order of Offset.
+	Issues []Issue
+}
+
+// Valid reports whether r has no issue of SeverityError, i.e. whether the address is valid.
+func (r Report) Valid() bool {
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError {
+			return false
+		}
+	}
+	return true
+}
+
+// Inspect checks email like Validate but does not stop at the first problem: it reports every problem it
+// finds, such as an address that is too long, a malformed label, disallowed characters or a missing top-level
+// domain, so that forms can show them all at once. Valid addresses are checked for lookalike domains, which
+// are reported as warnings.
+//
+// Parameters:
+//
+//	email: The email address to inspect.
+//	opts: Optional Options controlling validation, e.g. WithRFC5322().
+//
+// Returns:
+//   - A Report whose Valid method agrees with Validate.
+//
+// Example:
+//
+//	report := Inspect("john doe@exa_mple")
+//	for _, issue := range report.Issues {
+//	    fmt.Println(issue.Severity, issue.Offset, issue.Msg)
+//	}
+//	// Output:
+//	// error 4 invalid character ' '
+//	// error 12 invalid character '_'
+//	// error 17 missing top-level domain
+func Inspect(email string, opts ...Option) Report {
+	o := newOptions(opts)
+	if modern, _, ok := o.normalizeObsolete(email); ok {
+		email = modern
+	} else if o.comments {
+		stripped, _, err := stripCFWS(email)
+		if err != nil {
+			var parseErr *ParseError
+			errors.As(o.parseError(email, err), &parseErr)
+			return Report{Email: email, Issues: []Issue{issueOf(err, parseErr.Part, parseErr.Offset, parseErr.Msg)}}
+		}
+		email = stripped
+	}
+	email = o.normalize(email)
+
+	r := Report{Email: email}
+	if _, _, err := o.splitEmail(email); err != nil {
+		r.Issues = o.inspectProblems(email)
+		sort.SliceStable(r.Issues, func(i, j int) bool { return r.Issues[i].Offset < r.Issues[j].Offset })
+		if len(r.Issues) == 0 {
+			var parseErr *ParseError
+			errors.As(o.parseError(email, err), &parseErr)
+			r.Issues = append(r.Issues, issueOf(err, parseErr.Part, parseErr.Offset, parseErr.Msg))
+		}
+		return r
+	}
+
+	at := strings.LastIndex(email, emailSeparator)
+	if risk := domainSpoofRisk(email[at+1:]); risk.Risky() {
+		msg := "domain mixes scripts"
+		if risk.Lookalike {
+			msg = fmt.Sprintf("domain looks like %q", risk.Skeleton)
+		}
+		r.Issues = append(r.Issues, Issue{Severity: SeverityWarning, Part: PartDomain, Offset: at + 1, Msg: msg})
+	}
+	return r
+}
+
+// issueOf returns an Issue of SeverityError with the code of err.
+func issueOf(err error, part string, offset int, msg string) Issue {
+	code, _ := ErrorCodeOf(err)
+	return Issue{Severity: SeverityError, Part: part, Offset: offset, Msg: msg, Code: code}
+}
+
+// inspectProblems returns every problem it recognizes in email, which is invalid.
+func (o *options) inspectProblems(email string) []Issue {
+	var issues []Issue
+	if len(email) > maxEmailLength {
+		issues = append(issues, issueOf(ErrEmailTooLong, "", maxEmailLength,
+			fmt.Sprintf("address longer than %d octets", maxEmailLength)))
+	}
+	at := strings.LastIndex(email, emailSeparator)
+	if at < 0 {
+		return append(issues, issueOf(ErrInvalidEmailFormat, "", len(email), "missing '@'"))
+	}
+	username, domain := email[:at], email[at+1:]
+
+	if err := o.validateUsername(username); err != nil {
+		issues = append(issues, o.inspectUsername(username)...)
+		if errors.Is(err, ErrRejectedByValidator) {
+			issues = append(issues, issueOf(err, PartLocal, 0, err.Error()))
+		}
+	}
+	if err := o.validateDomain(domain); errors.Is(err, ErrRejectedByValidator) {
+		issues = append(issues, issueOf(err, PartDomain, at+1, err.Error()))
+	} else if err != nil {
+		if o.isCheckedAddressLiteral(domain) {
+			return append(issues, issueOf(err, PartDomain, at+1, err.Error()))
+		}
+		for _, issue := range o.inspectDomain(domain) {
+			issue.Offset += at + 1
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// inspectUsername returns the problems of username, like locateUsernameProblem but all of them.
+func (o *options) inspectUsername(username string) []Issue {
+	problem := func(err error, offset int, msg string) Issue {
+		return issueOf(err, PartLocal, offset, msg)
+	}
+	if username == "" {
+		return []Issue{problem(ErrInvalidEmailUsernameFormat, 0, "empty local part")}
+	}
+	if strings.HasPrefix(username, `"`) {
+		return []Issue{problem(ErrInvalidEmailUsernameFormat, 0, "invalid quoted string")}
+	}
+
+	var issues []Issue
+	if o.asciiOnly && !isASCII(username) {
+		offset, msg := asciiOnlyProblem(PartLocal, username)
+		issues = append(issues, problem(ErrEmailRequiresSMTPUTF8, offset, msg))
+	}
+	for _, i := range invalidRunes(username, o.isUsernameChar) {
+		r, _ := utf8.DecodeRuneInString(username[i:])
+		if o.asciiOnly && r >= utf8.RuneSelf {
+			continue
+		}
+		issues = append(issues, problem(ErrInvalidEmailUsernameFormat, i, fmt.Sprintf("invalid character %q", r)))
+	}
+	if strings.HasPrefix(username, domainSeparator) {
+		issues = append(issues, problem(ErrInvalidEmailUsernameFormat, 0, "local part starts with '.'"))
+	}
+	for i := 1; i < len(username); i++ {
+		if username[i] == '.' && username[i-1] == '.' {
+			issues = append(issues, problem(ErrInvalidEmailUsernameFormat, i, "consecutive dots"))
+		}
+	}
+	if len(username) > 1 && strings.HasSuffix(username, domainSeparator) {
+		issues = append(issues, problem(ErrInvalidEmailUsernameFormat, len(username)-1, "local part ends with '.'"))
+	}
+	if len(username) > maxUsernameLength {
+		issues = append(issues, problem(ErrEmailUsernameTooLong, maxUsernameLength,
+			fmt.Sprintf("local part longer than %d octets", maxUsernameLength)))
+	}
+	return issues
+}
+
+// inspectDomain returns the problems of domain, like locateDomainProblem but all of them. Offsets are
+// relative to domain.
+func (o *options) inspectDomain(domain string) []Issue {
+	problem := func(err error, offset int, msg string) Issue {
+		return issueOf(err, PartDomain, offset, msg)
+	}
+	if domain == "" {
+		return []Issue{problem(ErrInvalidEmailDomainFormat, 0, "empty domain")}
+	}
+
+	var issues []Issue
+	if o.asciiOnly && !isASCII(domain) {
+		offset, msg := asciiOnlyProblem(PartDomain, domain)
+		issues = append(issues, problem(ErrEmailRequiresIDNA, offset, msg))
+	}
+	for _, i := range invalidRunes(domain, o.isDomainChar) {
+		r, _ := utf8.DecodeRuneInString(domain[i:])
+		if o.asciiOnly && r >= utf8.RuneSelf {
+			continue
+		}
+		issues = append(issues, problem(ErrInvalidEmailDomainFormat, i, fmt.Sprintf("invalid character %q", r)))
+	}
+
+	start := 0
+	for _, label := range strings.Split(domain, domainSeparator) {
+		switch {
+		case label == "":
+			issues = append(issues, problem(ErrInvalidEmailDomainFormat, start, "empty label"))
+		case len(label) > maxLabelLength:
+			issues = append(issues, problem(ErrInvalidEmailDomainFormat, start+maxLabelLength,
+				fmt.Sprintf("label longer than %d octets", maxLabelLength)))
+		case label[0] == '-':
+			issues = append(issues, problem(ErrInvalidEmailDomainFormat, start, "label starts with '-'"))
+		}
+		if len(label) > 1 && label[len(label)-1] == '-' {
+			issues = append(issues, problem(ErrInvalidEmailDomainFormat, start+len(label)-1, "label ends with '-'"))
+		}
+		start += len(label) + len(domainSeparator)
+	}
+	if len(domain) > maxDomainLength {
+		issues = append(issues, problem(ErrEmailDomainTooLong, maxDomainLength,
+			fmt.Sprintf("domain longer than %d octets", maxDomainLength)))
+	}
+
+	last := strings.LastIndex(domain, domainSeparator)
+	if o.syntax == syntaxDefault && !(o.singleLabel && last < 0) {
+		if last < 0 {
+			return append(issues, problem(ErrInvalidEmailFormat, len(domain), "missing top-level domain"))
+		}
+		if tld := domain[last+1:]; tld != "" && (!o.idn || isASCII(tld)) && !isTLDLabel(tld) {
+			issues = append(issues, problem(ErrInvalidEmailDomainTLDFormat, last+1,
+				"top-level domain must contain only letters"))
+		}
+	}
+	if tld, minTLDLength := lastDomainLabel(domain), o.effectiveMinTLDLength(); tld != "" && len(tld) < minTLDLength {
+		issues = append(issues, problem(ErrEmailDomainTLDTooShort, last+1,
+			fmt.Sprintf("top-level domain shorter than %d characters", minTLDLength)))
+	}
+	if tld, maxTLDLength := lastDomainLabel(domain), o.effectiveMaxTLDLength(); len(tld) > maxTLDLength &&
+		len(tld) <= maxLabelLength {
+		issues = append(issues, problem(ErrEmailDomainTLDTooLong, last+1+maxTLDLength,
+			fmt.Sprintf("top-level domain longer than %d characters", maxTLDLength)))
+	}
+	if tld := lastDomainLabel(domain); o.strict && isNumeric(tld) {
+		issues = append(issues, problem(ErrEmailDomainTLDNumeric, last+1, "top-level domain is numeric"))
+	}
+	if tld, err := o.toASCIIDomain(lastDomainLabel(domain)); tld != "" && err == nil && !o.isAllowedTLD(tld) {
+		issues = append(issues, problem(ErrEmailDomainTLDNotAllowed, last+1, "top-level domain is not allowed"))
+	}
+	if tld, err := o.toASCIIDomain(lastDomainLabel(domain)); tld != "" && err == nil && !o.isKnownTLD(tld) {
+		issues = append(issues, problem(ErrEmailDomainTLDUnknown, last+1, "unknown top-level domain"))
+	}
+	if use, ok := reservedDomainUse(domain); o.rejectReserved && ok {
+		issues = append(issues, problem(ErrEmailDomainReserved, 0, "domain is reserved for "+use))
+	}
+	if ascii, err := o.toASCIIDomain(domain); err == nil && !isAddressLiteral(ascii) && last >= 0 {
+		if o.requireRegistrable && registrableDomain(ascii) == "" {
+			issues = append(issues, problem(ErrNoRegistrableDomain, 0, "domain is a public suffix"))
+		}
+		if o.rejectDisposable && isDisposable(ascii) {
+			issues = append(issues, problem(ErrEmailDomainDisposable, 0, "domain is a disposable mailbox service"))
+		}
+		if o.rejectFreeProviders && o.isFreeProvider(ascii) {
+			issues = append(issues, problem(ErrEmailDomainFreeProvider, 0, "domain is a free mailbox provider"))
+		}
+	}
+	return issues
+}
+
+// invalidRunes returns the offsets of the runes of s rejected by valid. Invalid UTF-8 is rejected.
+func invalidRunes(s string, valid func(r rune) bool) []int {
+	var offsets []int
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if (r == utf8.RuneError && size == 1) || !valid(r) {
+			offsets = append(offsets, i)
+		}
+		i += size
+	}
+	return offsets
+}
@@ -0,0 +1,109 @@
+package bemailparts_test
+
+import (
+	"github.com/bearaujus/bemailparts"
+	"reflect"
+	"testing"
+)
+
+func TestInspect(t *testing.T) {
+	type issue struct {
+		severity bemailparts.Severity
+		offset   int
+		msg      string
+	}
+	tests := []struct {
+		name       string
+		email      string
+		opts       []bemailparts.Option
+		wantValid  bool
+		wantIssues []issue
+	}{
+		{
+			name:      "valid",
+			email:     "john.doe@example.com",
+			wantValid: true,
+		},
+		{
+			name:      "every problem",
+			email:     "john doe@exa_mple",
+			wantValid: false,
+			wantIssues: []issue{
+				{severity: bemailparts.SeverityError, offset: 4, msg: "invalid character ' '"},
+				{severity: bemailparts.SeverityError, offset: 12, msg: "invalid character '_'"},
+				{severity: bemailparts.SeverityError, offset: 17, msg: "missing top-level domain"},
+			},
+		},
+		{
+			name:      "ascii only",
+			email:     "jü..rgen@bücher.de",
+			opts:      []bemailparts.Option{bemailparts.WithASCIIOnly(), bemailparts.WithIDN()},
+			wantValid: false,
+			wantIssues: []issue{
+				{severity: bemailparts.SeverityError, offset: 1, msg: "non-ASCII character 'ü' requires SMTPUTF8"},
+				{severity: bemailparts.SeverityError, offset: 4, msg: "consecutive dots"},
+				{severity: bemailparts.SeverityError, offset: 10, msg: `domain requires IDNA conversion to "xn--bcher-kva.de"`},
+			},
+		},
+		{
+			name:      "bad labels",
+			email:     "john@-b-.c0",
+			wantValid: false,
+			wantIssues: []issue{
+				{severity: bemailparts.SeverityError, offset: 5, msg: "label starts with '-'"},
+				{severity: bemailparts.SeverityError, offset: 7, msg: "label ends with '-'"},
+				{severity: bemailparts.SeverityError, offset: 9, msg: "top-level domain must contain only letters"},
+			},
+		},
+		{
+			name:      "missing at",
+			email:     "john.doe",
+			wantValid: false,
+			wantIssues: []issue{
+				{severity: bemailparts.SeverityError, offset: 8, msg: "missing '@'"},
+			},
+		},
+		{
+			name:      "tld too short",
+			email:     "john@example.c",
+			opts:      []bemailparts.Option{bemailparts.WithStrict()},
+			wantValid: false,
+			wantIssues: []issue{
+				{severity: bemailparts.SeverityError, offset: 13, msg: "top-level domain shorter than 2 characters"},
+			},
+		},
+		{
+			name:      "lookalike domain",
+			email:     "john@pаypal.com",
+			opts:      []bemailparts.Option{bemailparts.WithIDN()},
+			wantValid: true,
+			wantIssues: []issue{
+				{severity: bemailparts.SeverityWarning, offset: 5, msg: `domain looks like "paypal.com"`},
+			},
+		},
+		{
+			name:      "corporate policy",
+			email:     "john@mailinator.com",
+			opts:      bemailparts.PolicyCorporateSignup(),
+			wantValid: false,
+			wantIssues: []issue{
+				{severity: bemailparts.SeverityError, offset: 5, msg: "domain is a disposable mailbox service"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := bemailparts.Inspect(tt.email, tt.opts...)
+			if report.Valid() != tt.wantValid {
+				t.Errorf("Valid() = %v, want %v", report.Valid(), tt.wantValid)
+			}
+			var gotIssues []issue
+			for _, i := range report.Issues {
+				gotIssues = append(gotIssues, issue{severity: i.Severity, offset: i.Offset, msg: i.Msg})
+			}
+			if !reflect.DeepEqual(gotIssues, tt.wantIssues) {
+				t.Errorf("Issues = %+v, want %+v", gotIssues, tt.wantIssues)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package bemailparts
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CheckInvariants verifies the round-trip guarantees of p and returns an error wrapping
+// ErrInvariantViolation describing the first one that does not hold:
+//   - Email() and String() are equal and made of Username(), '@', and Domain().
+//   - Domain() is made of DomainName() and DomainTLD(), unless it is an address literal.
+//   - DomainTLDWithoutDot() is DomainTLD() without its leading dot.
+//   - Domain() is made of Subdomain() and RegistrableDomain, if it has a registrable domain.
+//   - Parsing Email() again with opts yields the same parts.
+//   - Setting any part to its current value on that copy leaves every other part unchanged.
+//
+// opts must be the Options p was created with. p itself is never modified, so CheckInvariants can be called
+// from fuzz targets and from downstream tests on types embedding BEmailParts.
+//
+// Example:
+//
+//	emailParts, _ := New("john.doe@example.com")
+//	if err := CheckInvariants(emailParts); err != nil {
+//	    log.Fatalf("Broken invariant: %v", err)
+//	}
+func CheckInvariants(p BEmailParts, opts ...Option) error {
+	if p.Email() != p.String() {
+		return invariantViolation("Email() %q differs from String() %q", p.Email(), p.String())
+	}
+	if p.Email() != generateEmail(p.Username(), p.Domain()) {
+		return invariantViolation("Email() %q is not Username() %q at Domain() %q", p.Email(), p.Username(), p.Domain())
+	}
+	if !isAddressLiteral(p.Domain()) && p.Domain() != p.DomainName()+p.DomainTLD() {
+		return invariantViolation("Domain() %q is not DomainName() %q followed by DomainTLD() %q",
+			p.Domain(), p.DomainName(), p.DomainTLD())
+	}
+	if p.DomainTLDWithoutDot() != strings.TrimPrefix(p.DomainTLD(), domainSeparator) {
+		return invariantViolation("DomainTLDWithoutDot() %q does not match DomainTLD() %q", p.DomainTLDWithoutDot(), p.DomainTLD())
+	}
+	if registrable := RegistrableDomain(p); registrable != "" &&
+		p.Domain() != strings.TrimPrefix(generateDomain(p.Subdomain(), registrable), domainSeparator) {
+		return invariantViolation("Domain() %q is not Subdomain() %q followed by RegistrableDomain %q",
+			p.Domain(), p.Subdomain(), registrable)
+	}
+
+	q, err := New(p.Email(), opts...)
+	if err != nil {
+		return invariantViolation("Email() %q does not parse again: %v", p.Email(), err)
+	}
+	if err = compareParts(p, q, "parsing Email() again"); err != nil {
+		return err
+	}
+
+	if err = q.SetUsername(p.Username()); err != nil {
+		return invariantViolation("SetUsername(%q) failed: %v", p.Username(), err)
+	}
+	if err = compareParts(p, q, "SetUsername() with the current value"); err != nil {
+		return err
+	}
+	if err = q.SetDomain(p.Domain()); err != nil {
+		return invariantViolation("SetDomain(%q) failed: %v", p.Domain(), err)
+	}
+	if err = compareParts(p, q, "SetDomain() with the current value"); err != nil {
+		return err
+	}
+	if isAddressLiteral(p.Domain()) {
+		return nil
+	}
+	if err = q.SetDomainName(p.DomainName()); err != nil {
+		return invariantViolation("SetDomainName(%q) failed: %v", p.DomainName(), err)
+	}
+	if err = compareParts(p, q, "SetDomainName() with the current value"); err != nil {
+		return err
+	}
+	if p.DomainTLD() == "" {
+		return nil
+	}
+	if err = q.SetDomainTLD(p.DomainTLD()); err != nil {
+		return invariantViolation("SetDomainTLD(%q) failed: %v", p.DomainTLD(), err)
+	}
+	return compareParts(p, q, "SetDomainTLD() with the current value")
+}
+
+func compareParts(want, got BEmailParts, after string) error {
+	switch {
+	case got.Username() != want.Username():
+		return invariantViolation("Username() changed from %q to %q after %s", want.Username(), got.Username(), after)
+	case got.Domain() != want.Domain():
+		return invariantViolation("Domain() changed from %q to %q after %s", want.Domain(), got.Domain(), after)
+	case got.DomainName() != want.DomainName():
+		return invariantViolation("DomainName() changed from %q to %q after %s", want.DomainName(), got.DomainName(), after)
+	case got.DomainTLD() != want.DomainTLD():
+		return invariantViolation("DomainTLD() changed from %q to %q after %s", want.DomainTLD(), got.DomainTLD(), after)
+	}
+	return nil
+}
+
+func invariantViolation(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvariantViolation, fmt.Sprintf(format, args...))
+}
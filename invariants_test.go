@@ -0,0 +1,106 @@
+package bemailparts_test
+
+import (
+	"errors"
+	"github.com/bearaujus/bemailparts"
+	"testing"
+)
+
+type brokenEmailParts struct {
+	bemailparts.BEmailParts
+}
+
+func (b brokenEmailParts) String() string {
+	return "broken"
+}
+
+func TestCheckInvariants(t *testing.T) {
+	tests := []struct {
+		name  string
+		email string
+		opts  []bemailparts.Option
+	}{
+		{
+			name:  "default",
+			email: "test.username@test-domain.co.id",
+		},
+		{
+			name:  "rfc5322 quoted",
+			email: `"john doe"@example.com`,
+			opts:  []bemailparts.Option{bemailparts.WithRFC5322()},
+		},
+		{
+			name:  "address literal",
+			email: "john@[IPv6:2001:db8::1]",
+			opts:  []bemailparts.Option{bemailparts.WithAllowIPDomain()},
+		},
+		{
+			name:  "single label domain",
+			email: "admin@localhost",
+			opts:  []bemailparts.Option{bemailparts.WithHTML5Validation()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := bemailparts.New(tt.email, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = bemailparts.CheckInvariants(e, tt.opts...); err != nil {
+				t.Errorf("CheckInvariants() error = %v", err)
+			}
+		})
+	}
+
+	t.Run("test violation detected", func(t *testing.T) {
+		e, err := bemailparts.New("john.doe@example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = bemailparts.CheckInvariants(brokenEmailParts{e})
+		if !errors.Is(err, bemailparts.ErrInvariantViolation) {
+			t.Errorf("CheckInvariants() error = %v, want %v", err, bemailparts.ErrInvariantViolation)
+		}
+	})
+}
+
+func FuzzNew(f *testing.F) {
+	for _, seed := range []string{
+		"test.username@test-domain.com",
+		"test.update.username@test-update-domain-name.co.id",
+		`"john doe"@example.com`,
+		"john@[192.0.2.1]",
+		"a@b.c",
+		"0@.0.A",
+	} {
+		f.Add(seed)
+	}
+	optionSets := [][]bemailparts.Option{
+		nil,
+		{bemailparts.WithRFC5322()},
+		{bemailparts.WithRFC5321(), bemailparts.WithAllowIPDomain()},
+		{bemailparts.WithHTML5Validation()},
+		{bemailparts.WithAllowSingleLabelDomain(), bemailparts.WithStrict()},
+		{bemailparts.WithUnicodeLocalPart(), bemailparts.WithRFC5322()},
+		{bemailparts.WithIDN(), bemailparts.WithUnicodeLocalPart()},
+		{bemailparts.WithObsoleteSyntax(), bemailparts.WithComments()},
+	}
+	f.Fuzz(func(t *testing.T, email string) {
+		for _, opts := range optionSets {
+			e, err := bemailparts.New(email, opts...)
+			var parseErr *bemailparts.ParseError
+			if errors.As(err, &parseErr) && (parseErr.Offset > len(parseErr.Input) || parseErr.Msg == "") {
+				t.Errorf("New(%q) error = %+v, want an offset within the input and a message", email, parseErr)
+			}
+			if valid := bemailparts.Inspect(email, opts...).Valid(); valid != (err == nil) {
+				t.Errorf("Inspect(%q).Valid() = %v, want %v", email, valid, err == nil)
+			}
+			if err != nil {
+				continue
+			}
+			if err = bemailparts.CheckInvariants(e, opts...); err != nil {
+				t.Errorf("CheckInvariants(%q) error = %v", email, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package bemailparts
+
+import "unicode/utf8"
+
+// LengthInfo reports the lengths of an address and its parts. The limits of RFC 5321 are in octets, and an
+// internationalized domain is sent in ACE form, which is longer than its Unicode form, so an address that
+// looks short can exceed them once converted: "bücher.de" has 9 runes, 10 octets in UTF-8 and 16 octets as
+// "xn--bcher-kva.de".
+type LengthInfo struct {
+	// LocalOctets and LocalRunes are the length of the username in UTF-8 octets and in runes. The username
+	// is sent as is, so LocalOctets is the length RFC 5321 and RFC 6531 limit to 64 octets.
+	LocalOctets int
+	LocalRunes  int
+
+	// DomainOctets and DomainRunes are the length of the domain, as given, in UTF-8 octets and in runes.
+	DomainOctets int
+	DomainRunes  int
+
+	// DomainASCIIOctets is the length of the domain in ACE form, after IDNA conversion, which RFC 5321
+	// limits to 255 octets. It is DomainOctets for ASCII domains and domain literals.
+	DomainASCIIOctets int
+
+	// TotalOctets and TotalRunes are the length of the address, as returned by Email, in UTF-8 octets and
+	// in runes.
+	TotalOctets int
+	TotalRunes  int
+
+	// TotalASCIIOctets is the length of the address with its domain in ACE form, which is limited to 254
+	// octets.
+	TotalASCIIOctets int
+}
+
+// WithinLimits reports whether the username, the domain in ACE form and the address in ACE form are within
+// the octet limits of RFC 5321.
+func (l LengthInfo) WithinLimits() bool {
+	return l.LocalOctets <= maxUsernameLength && l.DomainASCIIOctets <= maxDomainLength &&
+		l.TotalASCIIOctets <= maxEmailLength
+}
+
+// LengthInfoOf returns the lengths of the username, the domain and the address of e in octets and in runes,
+// including the lengths after converting the domain to ACE form, which the limits of RFC 5321 apply to.
+//
+// Example:
+//
+//	e, err := New("john@bücher.de", WithIDN())
+//	if err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	info := LengthInfoOf(e)
+//	fmt.Println(info.DomainRunes, info.DomainOctets, info.DomainASCIIOctets) // Output: 9 10 16
+func LengthInfoOf(e BEmailParts) LengthInfo {
+	return lengthInfo(e.Username(), e.Domain())
+}
+
+// lengthInfo measures the address username@domain. A domain that cannot be converted to ACE form is measured
+// as given.
+func lengthInfo(username, domain string) LengthInfo {
+	asciiDomain := domain
+	if !isASCII(domain) && !isAddressLiteral(domain) {
+		if ascii, err := ToASCII(domain); err == nil {
+			asciiDomain = ascii
+		}
+	}
+
+	separator := len(emailSeparator)
+	l := LengthInfo{
+		LocalOctets:       len(username),
+		LocalRunes:        utf8.RuneCountInString(username),
+		DomainOctets:      len(domain),
+		DomainRunes:       utf8.RuneCountInString(domain),
+		DomainASCIIOctets: len(asciiDomain),
+	}
+	l.TotalOctets = l.LocalOctets + separator + l.DomainOctets
+	l.TotalRunes = l.LocalRunes + separator + l.DomainRunes
+	l.TotalASCIIOctets = l.LocalOctets + separator + l.DomainASCIIOctets
+	return l
+}
@@ -0,0 +1,97 @@
+package bemailparts_test
+
+import (
+	"github.com/bearaujus/bemailparts"
+	"strings"
+	"testing"
+)
+
+func TestLengthInfo(t *testing.T) {
+	tests := []struct {
+		name       string
+		email      string
+		opts       []bemailparts.Option
+		want       bemailparts.LengthInfo
+		wantWithin bool
+	}{
+		{
+			name:  "ascii",
+			email: "john@example.com",
+			want: bemailparts.LengthInfo{
+				LocalOctets: 4, LocalRunes: 4,
+				DomainOctets: 11, DomainRunes: 11, DomainASCIIOctets: 11,
+				TotalOctets: 16, TotalRunes: 16, TotalASCIIOctets: 16,
+			},
+			wantWithin: true,
+		},
+		{
+			name:  "internationalized domain",
+			email: "john@bücher.de",
+			opts:  []bemailparts.Option{bemailparts.WithIDN()},
+			want: bemailparts.LengthInfo{
+				LocalOctets: 4, LocalRunes: 4,
+				DomainOctets: 10, DomainRunes: 9, DomainASCIIOctets: 16,
+				TotalOctets: 15, TotalRunes: 14, TotalASCIIOctets: 21,
+			},
+			wantWithin: true,
+		},
+		{
+			name:  "unicode local part",
+			email: "jürgen@example.com",
+			opts:  []bemailparts.Option{bemailparts.WithUnicodeLocalPart()},
+			want: bemailparts.LengthInfo{
+				LocalOctets: 7, LocalRunes: 6,
+				DomainOctets: 11, DomainRunes: 11, DomainASCIIOctets: 11,
+				TotalOctets: 19, TotalRunes: 18, TotalASCIIOctets: 19,
+			},
+			wantWithin: true,
+		},
+		{
+			name:  "domain literal",
+			email: "john@[192.0.2.1]",
+			opts:  []bemailparts.Option{bemailparts.WithRFC5322()},
+			want: bemailparts.LengthInfo{
+				LocalOctets: 4, LocalRunes: 4,
+				DomainOctets: 11, DomainRunes: 11, DomainASCIIOctets: 11,
+				TotalOctets: 16, TotalRunes: 16, TotalASCIIOctets: 16,
+			},
+			wantWithin: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := bemailparts.New(tt.email, tt.opts...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			got := bemailparts.LengthInfoOf(e)
+			if got != tt.want {
+				t.Errorf("LengthInfoOf() got = %+v, want %+v", got, tt.want)
+			}
+			if got.WithinLimits() != tt.wantWithin {
+				t.Errorf("WithinLimits() got = %v, want %v", got.WithinLimits(), tt.wantWithin)
+			}
+		})
+	}
+
+	t.Run("test exceeds limits once converted", func(t *testing.T) {
+		got := bemailparts.LengthInfo{LocalOctets: 60, DomainOctets: 190, DomainASCIIOctets: 200,
+			TotalOctets: 251, TotalASCIIOctets: 261}
+		if got.WithinLimits() {
+			t.Error("WithinLimits() got = true, want false")
+		}
+	})
+
+	t.Run("test setters", func(t *testing.T) {
+		e, err := bemailparts.New("john@example.com")
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		if err = e.SetUsername(strings.Repeat("j", 10)); err != nil {
+			t.Fatalf("SetUsername() error = %v", err)
+		}
+		if got := bemailparts.LengthInfoOf(e).TotalOctets; got != 22 {
+			t.Errorf("TotalOctets got = %v, want 22", got)
+		}
+	})
+}
@@ -0,0 +1,160 @@
+package bemailparts
+
+import "strings"
+
+// mailtoScheme is the URI scheme of links to email addresses (RFC 6068).
+const mailtoScheme = "mailto:"
+
+// trailingPunctuation are the characters removed from the end of an address by ParseLenient, as left by
+// sentences and lists the address was copied from.
+const trailingPunctuation = `.,;:!?`
+
+// FixKind identifies a repair applied by ParseLenient.
+type FixKind string
+
+const (
+	// FixWhitespace removed whitespace surrounding the address.
+	FixWhitespace FixKind = "whitespace"
+	// FixMailto removed a "mailto:" prefix and any query such as "?subject=Hello".
+	FixMailto FixKind = "mailto"
+	// FixAngleBrackets removed angle brackets enclosing the address.
+	FixAngleBrackets FixKind = "angle_brackets"
+	// FixTrailingPunctuation removed punctuation following the address, including trailing dots.
+	FixTrailingPunctuation FixKind = "trailing_punctuation"
+	// FixInternalWhitespace removed whitespace inside the address, e.g. in "user@gmail .com".
+	FixInternalWhitespace FixKind = "internal_whitespace"
+	// FixDoubledAt collapsed repeated at signs, e.g. in "user@@gmail.com".
+	FixDoubledAt FixKind = "doubled_at"
+	// FixCommaForDot replaced commas in the domain with dots, e.g. in "user@gmail,com".
+	FixCommaForDot FixKind = "comma_for_dot"
+)
+
+// Fix is a repair applied by ParseLenient, with the input before and after it.
+type Fix struct {
+	Kind   FixKind
+	Before string
+	After  string
+}
+
+// lenientCleanup is a repair: it returns s repaired and true, or s and false if it does not apply.
+type lenientCleanup struct {
+	kind  FixKind
+	apply func(s string) (string, bool)
+}
+
+var lenientCleanups = []lenientCleanup{
+	{kind: FixWhitespace, apply: trimSpace},
+	{kind: FixMailto, apply: trimMailto},
+	{kind: FixAngleBrackets, apply: trimAngleBrackets},
+	{kind: FixTrailingPunctuation, apply: trimTrailingPunctuation},
+	{kind: FixInternalWhitespace, apply: removeInternalWhitespace},
+	{kind: FixDoubledAt, apply: collapseDoubledAt},
+	{kind: FixCommaForDot, apply: replaceCommaForDot},
+}
+
+// ParseLenient creates a new instance of BEmailParts from an address pasted from a mail client or a
+// spreadsheet, or typed in a hurry, repairing it first. Surrounding whitespace, a "mailto:" prefix, enclosing
+// angle brackets and trailing punctuation are removed, as often as they apply and in any combination, e.g.
+// " <mailto:john.doe@example.com>, ". Common damage is repaired as well: whitespace inside the address
+// ("user@gmail .com"), doubled at signs ("user@@gmail.com") and commas typed for dots in the domain
+// ("user@gmail,com"). Addresses with quoted usernames are only cleaned, not repaired.
+//
+// Parameters:
+//
+//	input: The address to repair and parse.
+//	opts: Optional Options controlling validation of the repaired address, e.g. WithRFC5322().
+//
+// Returns:
+//   - A BEmailParts instance representing the repaired email.
+//   - The repairs applied, in order, so callers can ask the user to confirm them. It is empty if the input
+//     was clean.
+//   - An error of New if the repaired address is invalid. The repairs are returned regardless.
+//
+// Example:
+//
+//	emailParts, fixes, err := ParseLenient(" <mailto:john.doe@example.com>, ")
+//	if err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	fmt.Println(emailParts.Email()) // Output: john.doe@example.com
+//	fmt.Println(len(fixes))         // Output: 4
+func ParseLenient(input string, opts ...Option) (BEmailParts, []Fix, error) {
+	o := newOptions(opts)
+	var fixes []Fix
+	for changed := true; changed; {
+		changed = false
+		for _, cleanup := range lenientCleanups {
+			if after, ok := cleanup.apply(input); ok {
+				fixes = append(fixes, Fix{Kind: cleanup.kind, Before: input, After: after})
+				input, changed = after, true
+			}
+		}
+	}
+
+	e, err := newEmailParts(input, o)
+	if err != nil {
+		return nil, fixes, err
+	}
+	return e, fixes, nil
+}
+
+func trimSpace(s string) (string, bool) {
+	trimmed := strings.TrimSpace(s)
+	return trimmed, trimmed != s
+}
+
+func trimMailto(s string) (string, bool) {
+	if len(s) < len(mailtoScheme) || !strings.EqualFold(s[:len(mailtoScheme)], mailtoScheme) {
+		return s, false
+	}
+	s = s[len(mailtoScheme):]
+	if i := strings.IndexByte(s, '?'); i >= 0 {
+		s = s[:i]
+	}
+	return s, true
+}
+
+func trimAngleBrackets(s string) (string, bool) {
+	if len(s) < 2 || s[0] != '<' || s[len(s)-1] != '>' {
+		return s, false
+	}
+	return s[1 : len(s)-1], true
+}
+
+func trimTrailingPunctuation(s string) (string, bool) {
+	trimmed := strings.TrimRight(s, trailingPunctuation)
+	return trimmed, trimmed != s
+}
+
+// isQuoted reports whether s contains a quoted string, whose whitespace, at signs and commas are
+// significant.
+func isQuoted(s string) bool {
+	return strings.Contains(s, `"`)
+}
+
+func removeInternalWhitespace(s string) (string, bool) {
+	if isQuoted(s) {
+		return s, false
+	}
+	repaired := strings.Join(strings.Fields(s), "")
+	return repaired, repaired != s
+}
+
+func collapseDoubledAt(s string) (string, bool) {
+	if isQuoted(s) || !strings.Contains(s, emailSeparator+emailSeparator) {
+		return s, false
+	}
+	for strings.Contains(s, emailSeparator+emailSeparator) {
+		s = strings.ReplaceAll(s, emailSeparator+emailSeparator, emailSeparator)
+	}
+	return s, true
+}
+
+func replaceCommaForDot(s string) (string, bool) {
+	i := strings.LastIndex(s, emailSeparator)
+	if isQuoted(s) || i < 0 || !strings.Contains(s[i:], ",") {
+		return s, false
+	}
+	return s[:i] + strings.ReplaceAll(s[i:], ",", domainSeparator), true
+}
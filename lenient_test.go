@@ -0,0 +1,138 @@
+package bemailparts_test
+
+import (
+	"errors"
+	"github.com/bearaujus/bemailparts"
+	"reflect"
+	"testing"
+)
+
+func TestParseLenient(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		opts      []bemailparts.Option
+		want      string
+		wantFixes []bemailparts.FixKind
+		wantErr   error
+	}{
+		{
+			name:  "clean input",
+			input: "john.doe@example.com",
+			want:  "john.doe@example.com",
+		},
+		{
+			name:      "surrounding whitespace",
+			input:     " \tjohn.doe@example.com\n",
+			want:      "john.doe@example.com",
+			wantFixes: []bemailparts.FixKind{bemailparts.FixWhitespace},
+		},
+		{
+			name:      "mailto link with query",
+			input:     "MAILTO:john.doe@example.com?subject=Hello",
+			want:      "john.doe@example.com",
+			wantFixes: []bemailparts.FixKind{bemailparts.FixMailto},
+		},
+		{
+			name:      "angle brackets",
+			input:     "<john.doe@example.com>",
+			want:      "john.doe@example.com",
+			wantFixes: []bemailparts.FixKind{bemailparts.FixAngleBrackets},
+		},
+		{
+			name:      "trailing punctuation",
+			input:     "john.doe@example.com.;",
+			want:      "john.doe@example.com",
+			wantFixes: []bemailparts.FixKind{bemailparts.FixTrailingPunctuation},
+		},
+		{
+			name:  "combined",
+			input: " <mailto:john.doe@example.com>, ",
+			want:  "john.doe@example.com",
+			wantFixes: []bemailparts.FixKind{bemailparts.FixWhitespace, bemailparts.FixTrailingPunctuation,
+				bemailparts.FixAngleBrackets, bemailparts.FixMailto},
+		},
+		{
+			name:      "options apply to the repaired address",
+			input:     "<John.Doe@Example.com>",
+			opts:      []bemailparts.Option{bemailparts.WithLowercase()},
+			want:      "john.doe@example.com",
+			wantFixes: []bemailparts.FixKind{bemailparts.FixAngleBrackets},
+		},
+		{
+			name:      "internal whitespace",
+			input:     "user@gmail .com",
+			want:      "user@gmail.com",
+			wantFixes: []bemailparts.FixKind{bemailparts.FixInternalWhitespace},
+		},
+		{
+			name:      "doubled at",
+			input:     "user@@@gmail.com",
+			want:      "user@gmail.com",
+			wantFixes: []bemailparts.FixKind{bemailparts.FixDoubledAt},
+		},
+		{
+			name:      "comma for dot",
+			input:     "first.last@mail,example,com",
+			want:      "first.last@mail.example.com",
+			wantFixes: []bemailparts.FixKind{bemailparts.FixCommaForDot},
+		},
+		{
+			name:      "trailing comma is punctuation",
+			input:     "user@gmail.com,",
+			want:      "user@gmail.com",
+			wantFixes: []bemailparts.FixKind{bemailparts.FixTrailingPunctuation},
+		},
+		{
+			name:  "several repairs",
+			input: "user @@gmail,com.",
+			want:  "user@gmail.com",
+			wantFixes: []bemailparts.FixKind{bemailparts.FixTrailingPunctuation, bemailparts.FixInternalWhitespace,
+				bemailparts.FixDoubledAt, bemailparts.FixCommaForDot},
+		},
+		{
+			name:    "quoted username is not repaired",
+			input:   `"john doe"@@example.com`,
+			opts:    []bemailparts.Option{bemailparts.WithRFC5322()},
+			wantErr: bemailparts.ErrInvalidEmailFormat,
+		},
+		{
+			name:      "error invalid after repair",
+			input:     "<john.doe@>",
+			wantFixes: []bemailparts.FixKind{bemailparts.FixAngleBrackets},
+			wantErr:   bemailparts.ErrInvalidEmailFormat,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, fixes, err := bemailparts.ParseLenient(tt.input, tt.opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseLenient() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && got.Email() != tt.want {
+				t.Errorf("ParseLenient() got = %v, want %v", got, tt.want)
+			}
+			var kinds []bemailparts.FixKind
+			for _, fix := range fixes {
+				kinds = append(kinds, fix.Kind)
+			}
+			if !reflect.DeepEqual(kinds, tt.wantFixes) {
+				t.Errorf("ParseLenient() fixes = %v, want %v", kinds, tt.wantFixes)
+			}
+		})
+	}
+
+	t.Run("test fixes record each step", func(t *testing.T) {
+		_, fixes, err := bemailparts.ParseLenient("<john@example.com>.")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []bemailparts.Fix{
+			{Kind: bemailparts.FixTrailingPunctuation, Before: "<john@example.com>.", After: "<john@example.com>"},
+			{Kind: bemailparts.FixAngleBrackets, Before: "<john@example.com>", After: "john@example.com"},
+		}
+		if !reflect.DeepEqual(fixes, want) {
+			t.Errorf("ParseLenient() fixes = %+v, want %+v", fixes, want)
+		}
+	})
+}
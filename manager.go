@@ -0,0 +1,79 @@
+package bemailparts
+
+import (
+	"sort"
+	"sync"
+)
+
+// Manager routes parsing to per-tenant Parsers, so a service embedding this package can apply different
+// validation rules to each of its customers. Tenants without their own configuration use the default
+// Parser of the Manager.
+//
+// Every type of this package keeps its state in its own instances, so tenant isolation of suppression
+// lists, quarantines and bounce policies only needs one instance of each per tenant.
+//
+// A Manager is safe for concurrent use.
+type Manager struct {
+	mu       sync.RWMutex
+	fallback *Parser
+	tenants  map[string]*Parser
+}
+
+// NewManager creates a Manager whose tenants use opts until they are configured with SetTenant.
+//
+// Example:
+//
+//	manager := NewManager()
+//	manager.SetTenant("acme", WithRFC5321(), WithStrict())
+//
+//	_, err := manager.Parse("acme", "john.doe@example.c")
+//	fmt.Println(err) // Output: top-level domain shorter than 2 characters at position 17 in domain
+func NewManager(opts ...Option) *Manager {
+	return &Manager{fallback: NewParser(opts...), tenants: map[string]*Parser{}}
+}
+
+// SetTenant configures tenant to use opts, replacing its previous configuration. The options of the
+// Manager do not apply to the tenant anymore.
+func (m *Manager) SetTenant(tenant string, opts ...Option) {
+	parser := NewParser(opts...)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tenants[tenant] = parser
+}
+
+// RemoveTenant removes the configuration of tenant, which then uses the options of the Manager again.
+func (m *Manager) RemoveTenant(tenant string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.tenants, tenant)
+}
+
+// Tenants returns the configured tenants, sorted.
+func (m *Manager) Tenants() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tenants := make([]string, 0, len(m.tenants))
+	for tenant := range m.tenants {
+		tenants = append(tenants, tenant)
+	}
+	sort.Strings(tenants)
+	return tenants
+}
+
+// Parser returns the Parser of tenant, or the default Parser of the Manager if tenant is not configured.
+func (m *Manager) Parser(tenant string) *Parser {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if parser, ok := m.tenants[tenant]; ok {
+		return parser
+	}
+	return m.fallback
+}
+
+// Parse is like New, using the options of tenant.
+func (m *Manager) Parse(tenant, email string) (BEmailParts, error) {
+	return m.Parser(tenant).Parse(email)
+}
@@ -0,0 +1,53 @@
+package bemailparts_test
+
+import (
+	"errors"
+	"github.com/bearaujus/bemailparts"
+	"reflect"
+	"testing"
+)
+
+func TestManager(t *testing.T) {
+	manager := bemailparts.NewManager(bemailparts.WithLowercase())
+	manager.SetTenant("acme", bemailparts.WithStrict())
+	manager.SetTenant("globex", bemailparts.WithRFC5322())
+
+	tests := []struct {
+		name    string
+		tenant  string
+		email   string
+		want    string
+		wantErr error
+	}{
+		{name: "default tenant", tenant: "initech", email: "John@Example.com", want: "john@example.com"},
+		{name: "default tenant accepts short tld", tenant: "initech", email: "john@example.c", want: "john@example.c"},
+		{name: "strict tenant keeps case", tenant: "acme", email: "John@Example.com", want: "John@Example.com"},
+		{name: "strict tenant rejects short tld", tenant: "acme", email: "john@example.c", wantErr: bemailparts.ErrEmailDomainTLDTooShort},
+		{name: "rfc5322 tenant accepts quoted username", tenant: "globex", email: `"john doe"@example.com`, want: `"john doe"@example.com`},
+		{name: "default tenant rejects quoted username", tenant: "initech", email: `"john doe"@example.com`, wantErr: bemailparts.ErrInvalidEmailFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := manager.Parse(tt.tenant, tt.email)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr == nil && got.Email() != tt.want {
+				t.Errorf("Parse() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got, want := manager.Tenants(), []string{"acme", "globex"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Tenants() got = %v, want %v", got, want)
+	}
+
+	manager.RemoveTenant("acme")
+	if _, err := manager.Parse("acme", "john@example.c"); err != nil {
+		t.Errorf("Parse() error = %v after RemoveTenant(), want nil", err)
+	}
+	if manager.Parser("acme") != manager.Parser("initech") {
+		t.Error("Parser() of a removed tenant is not the default Parser")
+	}
+}
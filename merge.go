@@ -0,0 +1,189 @@
+package bemailparts
+
+import (
+	"fmt"
+	"strings"
+)
+
+// mergeSimilarityThreshold is the Similarity above which MergeCandidates treats two addresses as
+// near-duplicates.
+const mergeSimilarityThreshold = 0.9
+
+// AccountEmail is the address registered for an account.
+type AccountEmail struct {
+	AccountID string
+	Email     string
+}
+
+// MergeCluster is a group of accounts whose addresses likely belong to the same person. Reasons explains,
+// for every link that joined the cluster, which two addresses matched and why.
+type MergeCluster struct {
+	Accounts []AccountEmail
+	Reasons  []string
+}
+
+// MergeCandidates clusters accounts whose addresses likely belong to the same person, for account-merge
+// workflows. Two addresses are linked when they are equal ignoring case ("duplicate"), have the same
+// Canonical address because they differ by a tag such as "+shop" ("tag variant"), by dots ignored by the
+// provider ("dot variant") or by an alias domain such as googlemail.com for gmail.com ("alias domain"), or
+// have a Similarity above 0.9 ("near-duplicate"). Links are transitive.
+// Accounts with invalid addresses are ignored, and only clusters of two or more accounts are returned, in
+// the order their first account appears in accounts.
+//
+// Example:
+//
+//	clusters := MergeCandidates([]AccountEmail{
+//	    {AccountID: "1", Email: "john.doe@gmail.com"},
+//	    {AccountID: "2", Email: "john.doe+shop@googlemail.com"},
+//	    {AccountID: "3", Email: "jane@example.com"},
+//	})
+//
+//	fmt.Println(len(clusters), len(clusters[0].Accounts)) // Output: 1 2
+func MergeCandidates(accounts []AccountEmail) []MergeCluster {
+	type candidate struct {
+		index     int
+		email     string
+		username  string
+		domain    string
+		canonical string
+	}
+
+	var candidates []candidate
+	for i, account := range accounts {
+		e, err := New(account.Email, WithLowercase())
+		if err != nil {
+			continue
+		}
+		username, domain := canonicalParts(e.Username(), e.Domain())
+		candidates = append(candidates, candidate{
+			index:     i,
+			email:     e.Email(),
+			username:  e.Username(),
+			domain:    e.Domain(),
+			canonical: generateEmail(username, domain),
+		})
+	}
+
+	parent := make([]int, len(candidates))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	reasons := map[int][]string{}
+	for i := range candidates {
+		for j := i + 1; j < len(candidates); j++ {
+			a, b := candidates[i], candidates[j]
+			var reason string
+			switch {
+			case a.email == b.email:
+				reason = "duplicate"
+			case a.canonical == b.canonical:
+				reason = canonicalMatchReason(a.username, b.username, a.domain, b.domain)
+			default:
+				if score := Similarity(a.canonical, b.canonical); score > mergeSimilarityThreshold {
+					reason = fmt.Sprintf("near-duplicate (similarity %.2f)", score)
+				}
+			}
+			if reason == "" {
+				continue
+			}
+
+			ri, rj := find(i), find(j)
+			if ri > rj {
+				ri, rj = rj, ri
+			}
+			if ri != rj {
+				parent[rj] = ri
+				reasons[ri] = append(reasons[ri], reasons[rj]...)
+				delete(reasons, rj)
+			}
+			reasons[ri] = append(reasons[ri], fmt.Sprintf("%s ~ %s: %s", a.email, b.email, reason))
+		}
+	}
+
+	var clusters []MergeCluster
+	byRoot := map[int]int{}
+	for i, c := range candidates {
+		root := find(i)
+		if _, ok := reasons[root]; !ok {
+			continue
+		}
+		k, ok := byRoot[root]
+		if !ok {
+			k = len(clusters)
+			byRoot[root] = k
+			clusters = append(clusters, MergeCluster{Reasons: reasons[root]})
+		}
+		clusters[k].Accounts = append(clusters[k].Accounts, accounts[c.index])
+	}
+	return clusters
+}
+
+// Similarity returns how similar two email addresses are, from 0 for entirely different addresses to 1 for
+// addresses that are equal ignoring case. It is one minus the edit distance between the addresses divided by
+// the length of the longer one, counted in characters.
+//
+// Example:
+//
+//	fmt.Println(Similarity("john.doe@example.com", "john.dae@example.com")) // Output: 0.95
+func Similarity(a, b string) float64 {
+	ra, rb := []rune(strings.ToLower(a)), []rune(strings.ToLower(b))
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(editDistance(ra, rb))/float64(longest)
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = prev[j-1] + cost
+			if prev[j]+1 < curr[j] {
+				curr[j] = prev[j] + 1
+			}
+			if curr[j-1]+1 < curr[j] {
+				curr[j] = curr[j-1] + 1
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// canonicalMatchReason explains why two different addresses with the same canonical address match.
+func canonicalMatchReason(usernameA, usernameB, domainA, domainB string) string {
+	var reasons []string
+	if usernameA != usernameB {
+		if strings.ReplaceAll(usernameA, domainSeparator, "") == strings.ReplaceAll(usernameB, domainSeparator, "") {
+			reasons = append(reasons, "dot variant")
+		} else {
+			reasons = append(reasons, "tag variant")
+		}
+	}
+	if domainA != domainB {
+		reasons = append(reasons, "alias domain")
+	}
+	return strings.Join(reasons, ", ")
+}
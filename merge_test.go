@@ -0,0 +1,65 @@
+package bemailparts_test
+
+import (
+	"github.com/bearaujus/bemailparts"
+	"reflect"
+	"testing"
+)
+
+func TestMergeCandidates(t *testing.T) {
+	accounts := []bemailparts.AccountEmail{
+		{AccountID: "1", Email: "john.doe@gmail.com"},
+		{AccountID: "2", Email: "jane@example.com"},
+		{AccountID: "3", Email: "John.Doe+shop@gmail.com"},
+		{AccountID: "4", Email: "invalid"},
+		{AccountID: "5", Email: "john.doe@googlemail.com"},
+		{AccountID: "6", Email: "bob@example.org"},
+		{AccountID: "7", Email: "jane@example.con"},
+		{AccountID: "8", Email: "johndoe@gmail.com"},
+	}
+
+	got := bemailparts.MergeCandidates(accounts)
+	want := []bemailparts.MergeCluster{
+		{
+			Accounts: []bemailparts.AccountEmail{accounts[0], accounts[2], accounts[4], accounts[7]},
+			Reasons: []string{
+				"john.doe@gmail.com ~ john.doe+shop@gmail.com: tag variant",
+				"john.doe@gmail.com ~ john.doe@googlemail.com: alias domain",
+				"john.doe@gmail.com ~ johndoe@gmail.com: dot variant",
+				"john.doe+shop@gmail.com ~ john.doe@googlemail.com: tag variant, alias domain",
+				"john.doe+shop@gmail.com ~ johndoe@gmail.com: tag variant",
+				"john.doe@googlemail.com ~ johndoe@gmail.com: dot variant, alias domain",
+			},
+		},
+		{
+			Accounts: []bemailparts.AccountEmail{accounts[1], accounts[6]},
+			Reasons:  []string{"jane@example.com ~ jane@example.con: near-duplicate (similarity 0.94)"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeCandidates() got = %+v, want %+v", got, want)
+	}
+
+	if got := bemailparts.MergeCandidates([]bemailparts.AccountEmail{{AccountID: "1", Email: "a@example.com"}}); len(got) != 0 {
+		t.Errorf("MergeCandidates() got = %+v, want none", got)
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{a: "john.doe@example.com", b: "John.Doe@Example.com", want: 1},
+		{a: "john.doe@example.com", b: "john.dae@example.com", want: 0.95},
+		{a: "abcd", b: "wxyz", want: 0},
+		{a: "", b: "", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			if got := bemailparts.Similarity(tt.a, tt.b); got != tt.want {
+				t.Errorf("Similarity() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
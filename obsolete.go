@@ -0,0 +1,161 @@
+package bemailparts
+
+import "strings"
+
+// WithObsoleteSyntax accepts addresses using the obsolete RFC 5322 forms found in old archives and rewrites
+// them to modern form: words of the username joined by dots may be quoted individually (obs-local-part), as
+// in `john."q".doe@example.com`, and whitespace, line folds and comments may surround every dot and the '@'
+// (obs-domain and embedded CFWS), as in "john . doe @ example . com". The username becomes a dot-atom where
+// possible, e.g. "john.q.doe", and a single quoted string otherwise, e.g. `"john smith.doe"`.
+//
+// WithObsoleteSyntax implies WithRFC5322, whose grammar the rewritten address is validated against; a later
+// syntax option replaces the grammar. It applies to constructors parsing whole addresses; with WithComments,
+// the comments removed are available through Comments.
+//
+// Example:
+//
+//	emailParts, err := New("john . \"doe\" @ example . com", WithObsoleteSyntax())
+//	if err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	fmt.Println(emailParts.Email()) // Output: john.doe@example.com
+func WithObsoleteSyntax() Option {
+	return func(o *options) {
+		o.syntax = syntaxRFC5322
+		o.obsolete = true
+	}
+}
+
+// normalizeObsolete rewrites email, which may use obsolete forms, to modern form with WithObsoleteSyntax. It
+// returns the comments removed, if WithComments is set, and false if WithObsoleteSyntax is not set or email
+// does not parse.
+func (o *options) normalizeObsolete(email string) (string, []string, bool) {
+	if !o.obsolete {
+		return "", nil, false
+	}
+
+	var comments []string
+	words, i, ok := scanObsoleteWords(email, 0, &comments, scanQuotedString)
+	if !ok || i >= len(email) || email[i] != emailSeparator[0] {
+		return "", nil, false
+	}
+
+	i, err := skipCFWS(email, i+1, &comments)
+	if err != nil {
+		return "", nil, false
+	}
+	var domain string
+	if i < len(email) && email[i] == '[' {
+		end := scanDomainLiteral(email, i)
+		if end < 0 {
+			return "", nil, false
+		}
+		domain, i = email[i:end], end
+	} else {
+		labels, end, ok := scanObsoleteWords(email, i, &comments, nil)
+		if !ok {
+			return "", nil, false
+		}
+		domain, i = strings.Join(labels, domainSeparator), end
+	}
+	if end, err := skipCFWS(email, i, &comments); err != nil || end != len(email) {
+		return "", nil, false
+	}
+
+	if !o.comments {
+		comments = nil
+	}
+	return generateEmail(joinObsoleteWords(words), domain), comments, true
+}
+
+// scanObsoleteWords scans the dot-separated words starting at s[i], surrounded by any CFWS, and returns them
+// with the index after them. Words are atoms or, if scanQuoted is not nil, quoted strings.
+func scanObsoleteWords(s string, i int, comments *[]string, scanQuoted func(s string, i int) int) ([]string, int, bool) {
+	var words []string
+	for {
+		var err error
+		if i, err = skipCFWS(s, i, comments); err != nil {
+			return nil, 0, false
+		}
+		end := i
+		if scanQuoted != nil && i < len(s) && s[i] == '"' {
+			end = scanQuoted(s, i)
+		} else {
+			for end < len(s) && isAtext(s[end]) {
+				end++
+			}
+		}
+		if end <= i {
+			return nil, 0, false
+		}
+		words = append(words, s[i:end])
+
+		if i, err = skipCFWS(s, end, comments); err != nil {
+			return nil, 0, false
+		}
+		if i >= len(s) || s[i] != domainSeparator[0] {
+			return words, i, true
+		}
+		i++
+	}
+}
+
+// joinObsoleteWords joins the words of an obs-local-part into a dot-atom if every quoted word is a valid
+// atom once unquoted, and into a single quoted string otherwise.
+func joinObsoleteWords(words []string) string {
+	contents := make([]string, len(words))
+	dotAtom := true
+	for i, word := range words {
+		contents[i] = word
+		if strings.HasPrefix(word, `"`) {
+			contents[i] = unquote(word)
+			dotAtom = dotAtom && isAtom(contents[i])
+		}
+	}
+	if dotAtom {
+		return strings.Join(contents, domainSeparator)
+	}
+
+	var b strings.Builder
+	b.WriteByte('"')
+	for i, content := range contents {
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		for j := 0; j < len(content); j++ {
+			if content[j] == '"' || content[j] == '\\' {
+				b.WriteByte('\\')
+			}
+			b.WriteByte(content[j])
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// isAtom reports whether s is a non-empty run of atext characters.
+func isAtom(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isAtext(s[i]) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// unquote returns the content of the quoted-string s, without its quotes and with quoted-pairs resolved.
+func unquote(s string) string {
+	s = s[1 : len(s)-1]
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,91 @@
+package bemailparts_test
+
+import (
+	"errors"
+	"github.com/bearaujus/bemailparts"
+	"reflect"
+	"testing"
+)
+
+func TestObsoleteSyntax(t *testing.T) {
+	tests := []struct {
+		name         string
+		email        string
+		opts         []bemailparts.Option
+		want         string
+		wantComments []string
+		wantErr      error
+	}{
+		{
+			name:  "folding whitespace around dots and at",
+			email: "john . doe @ example . com",
+			want:  "john.doe@example.com",
+		},
+		{
+			name:  "line folds",
+			email: "john\r\n .doe@example.com",
+			want:  "john.doe@example.com",
+		},
+		{
+			name:  "quoted atoms",
+			email: `john."q".doe@example.com`,
+			want:  "john.q.doe@example.com",
+		},
+		{
+			name:  "quoted words",
+			email: `"john smith".doe@example.com`,
+			want:  `"john smith.doe"@example.com`,
+		},
+		{
+			name:  "quoted pairs",
+			email: `"a\"b".c@example.com`,
+			want:  `"a\"b.c"@example.com`,
+		},
+		{
+			name:  "domain literal",
+			email: "john @ [192.0.2.1]",
+			want:  "john@[192.0.2.1]",
+		},
+		{
+			name:         "comments",
+			email:        "john (home) . doe @ (work) example.com",
+			opts:         []bemailparts.Option{bemailparts.WithComments()},
+			want:         "john.doe@example.com",
+			wantComments: []string{"home", "work"},
+		},
+		{
+			name:  "modern address",
+			email: "john.doe@example.com",
+			want:  "john.doe@example.com",
+		},
+		{
+			name:    "empty word",
+			email:   "john..doe@example.com",
+			wantErr: bemailparts.ErrInvalidEmailFormat,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]bemailparts.Option{bemailparts.WithObsoleteSyntax()}, tt.opts...)
+			got, err := bemailparts.New(tt.email, opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Email() != tt.want {
+				t.Errorf("New() got = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(got.Comments(), tt.wantComments) {
+				t.Errorf("Comments() got = %q, want %q", got.Comments(), tt.wantComments)
+			}
+		})
+	}
+
+	t.Run("test rejected without option", func(t *testing.T) {
+		if _, err := bemailparts.New("john . doe @ example . com", bemailparts.WithRFC5322()); err == nil {
+			t.Error("New() error = nil, want an error")
+		}
+	})
+}
@@ -0,0 +1,344 @@
+package bemailparts
+
+import "strings"
+
+// Option configures how email addresses are parsed and validated. Options passed to a constructor also
+// apply to every setter called on the returned BEmailParts.
+type Option func(*options)
+
+type options struct {
+	syntax         syntax
+	allowIPDomain  bool
+	minTLDLength   int
+	maxTLDLength   int
+	lowercase      bool
+	strict         bool
+	comments       bool
+	auditSink      AuditSink
+	policyVersion  string
+	auditKey       []byte
+	sampleRate     float64
+	sampleHook     func(email string)
+	singleLabel    bool
+	unicode        bool
+	idn            bool
+	clock          Clock
+	rand           *lockedRand
+	history        bool
+	allowedTLDs    map[string]bool
+	obsolete       bool
+	asciiOnly      bool
+	rejectReserved bool
+	knownTLDsOnly  bool
+	extraTLDs      map[string]bool
+	freeProviders  map[string]bool
+
+	requireRegistrable  bool
+	rejectDisposable    bool
+	rejectFreeProviders bool
+
+	usernameValidators []PartValidator
+	domainValidators   []PartValidator
+}
+
+// syntax selects the grammar addresses are validated against. Syntax options are mutually exclusive; the
+// last one passed wins.
+type syntax int
+
+const (
+	syntaxDefault syntax = iota
+	syntaxRFC5322
+	syntaxRFC5321
+	syntaxHTML5
+)
+
+const (
+	// DefaultMinTLDLength is the minimum TLD length without WithMinTLDLength or WithStrict: any TLD the
+	// selected syntax accepts is allowed.
+	DefaultMinTLDLength = 0
+
+	// StrictMinTLDLength is the minimum TLD length enforced by WithStrict.
+	StrictMinTLDLength = 2
+
+	// DefaultMaxTLDLength is the maximum TLD length without WithMaxTLDLength: the maximum length of a DNS
+	// label, in octets.
+	DefaultMaxTLDLength = maxLabelLength
+)
+
+// defaultOptions is shared by every instance created without options. Options are never modified once
+// built, so sharing them is safe and saves an allocation on the common path.
+var defaultOptions = &options{}
+
+func newOptions(opts []Option) *options {
+	if len(opts) == 0 {
+		return defaultOptions
+	}
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// optionsOf returns the options e was created with, or the defaults if e is not an instance of this package.
+func optionsOf(e BEmailParts) *options {
+	if e, ok := e.(*bEmailParts); ok {
+		return e.opts
+	}
+	return defaultOptions
+}
+
+// WithRFC5322 validates addresses against the RFC 5322 addr-spec grammar instead of the default pattern.
+// Local parts may be dot-atoms using any RFC 5322 atext character (e.g., "o'brien" or "a/b") or quoted
+// strings (e.g., "\"john doe\""), and domains may be dot-atoms or domain literals (e.g., "[192.0.2.1]").
+// Consecutive, leading, or trailing dots are rejected outside quoted strings.
+//
+// Example:
+//
+//	emailParts, err := New(`"john doe"@example.com`, WithRFC5322())
+//	if err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	fmt.Println(emailParts.Username()) // Output: "john doe"
+func WithRFC5322() Option {
+	return func(o *options) {
+		o.syntax = syntaxRFC5322
+	}
+}
+
+// WithRFC5321 validates addresses against the RFC 5321 mailbox grammar used by SMTP, so accepted addresses
+// can be handed to an MTA. On top of the RFC 5322 local-part rules, domains must be dot-separated labels
+// of letters, digits, and hyphens (no leading or trailing hyphen) or an address literal.
+//
+// Example:
+//
+//	_, err := New("john@-example.com", WithRFC5321())
+//	fmt.Println(errors.Is(err, ErrInvalidEmailDomainFormat)) // Output: true
+func WithRFC5321() Option {
+	return func(o *options) {
+		o.syntax = syntaxRFC5321
+	}
+}
+
+// WithAllowIPDomain accepts domains written as RFC 5321 address literals, such as "[192.0.2.1]" or
+// "[IPv6:2001:db8::1]", and validates the literal strictly. Malformed literals are rejected with
+// ErrInvalidAddressLiteral, and literals of types other than IPv4 and IPv6 with ErrUnsupportedAddressLiteral.
+// DomainName and DomainTLD return empty strings for such domains.
+//
+// Example:
+//
+//	emailParts, err := New("john.doe@[IPv6:2001:db8::1]", WithAllowIPDomain())
+//	if err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	fmt.Println(emailParts.Domain()) // Output: [IPv6:2001:db8::1]
+func WithAllowIPDomain() Option {
+	return func(o *options) {
+		o.allowIPDomain = true
+	}
+}
+
+// WithAllowAddressLiteral is the same as WithAllowIPDomain, named after the RFC 5321 term for bracketed
+// domains such as "[192.168.10.25]".
+//
+// Example:
+//
+//	emailParts, err := New("user@[192.168.10.25]", WithAllowAddressLiteral())
+//	if err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	fmt.Println(emailParts.Domain())     // Output: [192.168.10.25]
+//	fmt.Println(emailParts.DomainName()) // Output:
+func WithAllowAddressLiteral() Option {
+	return WithAllowIPDomain()
+}
+
+// WithHTML5Validation validates addresses exactly like browsers validate input[type=email] fields, following
+// the WHATWG HTML Living Standard, so backend validation agrees with the frontend. This accepts single-label
+// domains such as "localhost" (with an empty DomainTLD) and dots anywhere in the username.
+//
+// Example:
+//
+//	emailParts, err := New("admin@localhost", WithHTML5Validation())
+//	if err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	fmt.Println(emailParts.DomainName()) // Output: localhost
+func WithHTML5Validation() Option {
+	return func(o *options) {
+		o.syntax = syntaxHTML5
+	}
+}
+
+// WithMinTLDLength rejects addresses whose TLD, the last label of the domain, is shorter than n characters,
+// with ErrEmailDomainTLDTooShort. Domains without a TLD are rejected as well. Address literals are exempt.
+//
+// Example:
+//
+//	_, err := New("john.doe@example.c", WithMinTLDLength(2))
+//	fmt.Println(errors.Is(err, ErrEmailDomainTLDTooShort)) // Output: true
+func WithMinTLDLength(n int) Option {
+	return func(o *options) {
+		o.minTLDLength = n
+	}
+}
+
+// WithMaxTLDLength rejects addresses whose TLD is longer than n characters, with ErrEmailDomainTLDTooLong,
+// e.g. to cap TLDs at 24 characters as some business rules do. Internationalized TLDs are measured in their
+// ASCII form. Values of n below 1 or above DefaultMaxTLDLength leave the DNS limit of DefaultMaxTLDLength in
+// place. Address literals and single-label domains are exempt.
+//
+// Example:
+//
+//	_, err := New("john.doe@example.technology", WithMaxTLDLength(6))
+//	fmt.Println(errors.Is(err, ErrEmailDomainTLDTooLong)) // Output: true
+func WithMaxTLDLength(n int) Option {
+	return func(o *options) {
+		o.maxTLDLength = n
+	}
+}
+
+// WithAllowedTLDs rejects addresses whose TLD, the last label of the domain, is not one of tlds, with
+// ErrEmailDomainTLDNotAllowed, e.g. to accept country TLDs only. TLDs are compared ignoring case and may be
+// given with a leading dot; internationalized ones are compared in their ASCII form. Passing the option
+// several times allows the TLDs of every call. Address literals and single-label domains are exempt.
+//
+// Example:
+//
+//	parser := NewParser(WithAllowedTLDs("com", "org", "id"))
+//	_, err := parser.Parse("john.doe@example.net")
+//	fmt.Println(errors.Is(err, ErrEmailDomainTLDNotAllowed)) // Output: true
+func WithAllowedTLDs(tlds ...string) Option {
+	return func(o *options) {
+		o.allowedTLDs = addTLDs(o.allowedTLDs, tlds)
+	}
+}
+
+// addTLDs returns a new set of TLDs holding set and tlds, lowercased, in ASCII form and without their
+// leading dot, so that options never modify sets they share with earlier options.
+func addTLDs(set map[string]bool, tlds []string) map[string]bool {
+	added := make(map[string]bool, len(set)+len(tlds))
+	for tld := range set {
+		added[tld] = true
+	}
+	for _, tld := range tlds {
+		tld = strings.ToLower(strings.TrimPrefix(tld, domainSeparator))
+		if ascii, err := ToASCII(tld); err == nil {
+			tld = ascii
+		}
+		added[tld] = true
+	}
+	return added
+}
+
+// WithAllowSingleLabelDomain accepts domains made of a single DNS label, such as "localhost" or "mailhost",
+// as used for intranet addresses. DomainName returns the whole domain and DomainTLD an empty string for
+// them, and they are exempt from WithMinTLDLength and WithStrict. The RFC 5322, RFC 5321 and HTML5 syntaxes
+// accept single-label domains without this option.
+//
+// Example:
+//
+//	emailParts, err := New("admin@localhost", WithAllowSingleLabelDomain())
+//	if err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	fmt.Println(emailParts.DomainName()) // Output: localhost
+//	fmt.Println(emailParts.DomainTLD())  // Output:
+func WithAllowSingleLabelDomain() Option {
+	return func(o *options) {
+		o.singleLabel = true
+	}
+}
+
+// WithUnicodeLocalPart accepts internationalized usernames such as "用户" (RFC 6531, SMTPUTF8), as issued by
+// providers supporting Email Address Internationalization. Any printable non-ASCII character is allowed
+// wherever the selected syntax allows a letter. The 64 octet username limit counts UTF-8 octets, not
+// characters.
+//
+// Example:
+//
+//	emailParts, err := New("用户@example.com", WithUnicodeLocalPart())
+//	if err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	fmt.Println(emailParts.Username()) // Output: 用户
+func WithUnicodeLocalPart() Option {
+	return func(o *options) {
+		o.unicode = true
+	}
+}
+
+// WithASCIIOnly rejects every address that cannot be sent without SMTPUTF8 or IDNA conversion, for
+// pipelines feeding legacy systems that do not support internationalized email (RFC 6530). Usernames with
+// non-ASCII characters are rejected with ErrEmailRequiresSMTPUTF8, as they can only be delivered with
+// SMTPUTF8, and domains with non-ASCII characters with ErrEmailRequiresIDNA. Domains already in ACE form,
+// such as "xn--bcher-kva.de", are accepted.
+//
+// The errors of constructors are *ParseErrors explaining the conversion needed, e.g. the ACE form of the
+// domain. WithASCIIOnly takes precedence over WithUnicodeLocalPart and WithIDN.
+//
+// Example:
+//
+//	_, err := New("john@bücher.de", WithASCIIOnly())
+//	fmt.Println(err)                                  // Output: domain requires IDNA conversion to "xn--bcher-kva.de" at position 5 in domain
+//	fmt.Println(errors.Is(err, ErrEmailRequiresIDNA)) // Output: true
+func WithASCIIOnly() Option {
+	return func(o *options) {
+		o.asciiOnly = true
+	}
+}
+
+// WithLowercase lowercases the whole address, including the username, when parsing and in every setter.
+// This suits signup forms and deduplication, where "John.Doe@Example.com" and "john.doe@example.com" should
+// be treated as the same address.
+//
+// Example:
+//
+//	emailParts, _ := New("John.Doe@Example.com", WithLowercase())
+//	fmt.Println(emailParts.Email()) // Output: john.doe@example.com
+func WithLowercase() Option {
+	return func(o *options) {
+		o.lowercase = true
+	}
+}
+
+// WithStrict rejects addresses that pass the selected syntax but are not deliverable in practice: TLDs
+// shorter than 2 characters (ErrEmailDomainTLDTooShort), unless WithMinTLDLength asks for more, all-numeric
+// TLDs such as "123" (ErrEmailDomainTLDNumeric), which DNS and ICANN rules forbid, and usernames with
+// leading, trailing or consecutive dots such as ".john", "john." or "jo..hn" (ErrInvalidEmailUsernameFormat),
+// which major providers and MTAs reject.
+//
+// Example:
+//
+//	_, err := New("john.doe@example.c", WithStrict())
+//	fmt.Println(errors.Is(err, ErrEmailDomainTLDTooShort)) // Output: true
+func WithStrict() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+// WithComments accepts RFC 5322 comments and folding whitespace before and after the username and the
+// domain, as found in addresses copied from mail headers. They are stripped from the address, and the
+// comments are available through Comments.
+//
+// Example:
+//
+//	emailParts, err := New("john.doe(work)@example.com", WithComments())
+//	if err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	fmt.Println(emailParts.Email())    // Output: john.doe@example.com
+//	fmt.Println(emailParts.Comments()) // Output: [work]
+func WithComments() Option {
+	return func(o *options) {
+		o.comments = true
+	}
+}
@@ -0,0 +1,337 @@
+package bemailparts_test
+
+import (
+	"errors"
+	"github.com/bearaujus/bemailparts"
+	"strings"
+	"testing"
+)
+
+func TestOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		opts    []bemailparts.Option
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "lowercase",
+			email: "John.Doe@Example.COM",
+			opts:  []bemailparts.Option{bemailparts.WithLowercase()},
+			want:  "john.doe@example.com",
+		},
+		{
+			name:  "min tld length satisfied",
+			email: "john@example.com",
+			opts:  []bemailparts.Option{bemailparts.WithMinTLDLength(3)},
+			want:  "john@example.com",
+		},
+		{
+			name:    "min tld length not satisfied",
+			email:   "john@example.co",
+			opts:    []bemailparts.Option{bemailparts.WithMinTLDLength(3)},
+			wantErr: bemailparts.ErrEmailDomainTLDTooShort,
+		},
+		{
+			name:    "min tld length without tld",
+			email:   "admin@localhost",
+			opts:    []bemailparts.Option{bemailparts.WithHTML5Validation(), bemailparts.WithMinTLDLength(2)},
+			wantErr: bemailparts.ErrEmailDomainTLDTooShort,
+		},
+		{
+			name:  "min tld length ignores address literals",
+			email: "john@[192.0.2.1]",
+			opts:  []bemailparts.Option{bemailparts.WithAllowIPDomain(), bemailparts.WithMinTLDLength(2)},
+			want:  "john@[192.0.2.1]",
+		},
+		{
+			name:    "strict one letter tld",
+			email:   "john@example.c",
+			opts:    []bemailparts.Option{bemailparts.WithStrict()},
+			wantErr: bemailparts.ErrEmailDomainTLDTooShort,
+		},
+		{
+			name:    "strict long username",
+			email:   strings.Repeat("a", 65) + "@example.com",
+			opts:    []bemailparts.Option{bemailparts.WithStrict()},
+			wantErr: bemailparts.ErrEmailUsernameTooLong,
+		},
+		{
+			name:    "strict keeps stricter min tld length",
+			email:   "john@example.com",
+			opts:    []bemailparts.Option{bemailparts.WithStrict(), bemailparts.WithMinTLDLength(4)},
+			wantErr: bemailparts.ErrEmailDomainTLDTooShort,
+		},
+		{
+			name:    "strict leading dot username",
+			email:   ".john@example.com",
+			opts:    []bemailparts.Option{bemailparts.WithStrict()},
+			wantErr: bemailparts.ErrInvalidEmailUsernameFormat,
+		},
+		{
+			name:    "strict trailing dot username",
+			email:   "john.@example.com",
+			opts:    []bemailparts.Option{bemailparts.WithStrict()},
+			wantErr: bemailparts.ErrInvalidEmailUsernameFormat,
+		},
+		{
+			name:    "strict consecutive dots username",
+			email:   "jo..hn@example.com",
+			opts:    []bemailparts.Option{bemailparts.WithStrict(), bemailparts.WithHTML5Validation()},
+			wantErr: bemailparts.ErrInvalidEmailUsernameFormat,
+		},
+		{
+			name:    "max tld length not satisfied",
+			email:   "john@example.technology",
+			opts:    []bemailparts.Option{bemailparts.WithMaxTLDLength(6)},
+			wantErr: bemailparts.ErrEmailDomainTLDTooLong,
+		},
+		{
+			name:  "max tld length satisfied",
+			email: "john@example.travel",
+			opts:  []bemailparts.Option{bemailparts.WithMaxTLDLength(6)},
+			want:  "john@example.travel",
+		},
+		{
+			name:  "max tld length ignores address literals",
+			email: "john@[192.0.2.1]",
+			opts:  []bemailparts.Option{bemailparts.WithAllowIPDomain(), bemailparts.WithMaxTLDLength(1)},
+			want:  "john@[192.0.2.1]",
+		},
+		{
+			name:  "max tld length out of range keeps dns limit",
+			email: "john@example.technology",
+			opts:  []bemailparts.Option{bemailparts.WithMaxTLDLength(0)},
+			want:  "john@example.technology",
+		},
+		{
+			name:  "allowed tld",
+			email: "john@example.CO.ID",
+			opts:  []bemailparts.Option{bemailparts.WithAllowedTLDs(".com", "ID")},
+			want:  "john@example.CO.ID",
+		},
+		{
+			name:    "tld not allowed",
+			email:   "john@example.net",
+			opts:    []bemailparts.Option{bemailparts.WithAllowedTLDs("com"), bemailparts.WithAllowedTLDs("org")},
+			wantErr: bemailparts.ErrEmailDomainTLDNotAllowed,
+		},
+		{
+			name:  "allowed tlds accumulate",
+			email: "john@example.org",
+			opts:  []bemailparts.Option{bemailparts.WithAllowedTLDs("com"), bemailparts.WithAllowedTLDs("org")},
+			want:  "john@example.org",
+		},
+		{
+			name:  "allowed internationalized tld",
+			email: "ivan@пример.рф",
+			opts:  []bemailparts.Option{bemailparts.WithIDN(), bemailparts.WithAllowedTLDs("рф")},
+			want:  "ivan@пример.рф",
+		},
+		{
+			name:  "allowed tlds ignore address literals",
+			email: "john@[192.0.2.1]",
+			opts:  []bemailparts.Option{bemailparts.WithAllowIPDomain(), bemailparts.WithAllowedTLDs("com")},
+			want:  "john@[192.0.2.1]",
+		},
+		{
+			name:    "strict numeric tld",
+			email:   "john@example.123",
+			opts:    []bemailparts.Option{bemailparts.WithStrict(), bemailparts.WithRFC5322()},
+			wantErr: bemailparts.ErrEmailDomainTLDNumeric,
+		},
+		{
+			name:  "numeric tld without strict",
+			email: "john@example.123",
+			opts:  []bemailparts.Option{bemailparts.WithRFC5322()},
+			want:  "john@example.123",
+		},
+		{
+			name:  "consecutive dots username without strict",
+			email: "jo..hn@example.com",
+			want:  "jo..hn@example.com",
+		},
+		{
+			name:    "ascii only rejects unicode username",
+			email:   "jürgen@example.com",
+			opts:    []bemailparts.Option{bemailparts.WithUnicodeLocalPart(), bemailparts.WithASCIIOnly()},
+			wantErr: bemailparts.ErrEmailRequiresSMTPUTF8,
+		},
+		{
+			name:    "ascii only rejects unicode domain",
+			email:   "john@bücher.de",
+			opts:    []bemailparts.Option{bemailparts.WithASCIIOnly(), bemailparts.WithIDN()},
+			wantErr: bemailparts.ErrEmailRequiresIDNA,
+		},
+		{
+			name:    "ascii only explains without unicode options",
+			email:   "john@bücher.de",
+			opts:    []bemailparts.Option{bemailparts.WithASCIIOnly()},
+			wantErr: bemailparts.ErrEmailRequiresIDNA,
+		},
+		{
+			name:  "ascii only accepts ace domain",
+			email: "john@xn--bcher-kva.de",
+			opts:  []bemailparts.Option{bemailparts.WithASCIIOnly(), bemailparts.WithIDN()},
+			want:  "john@xn--bcher-kva.de",
+		},
+		{
+			name:  "last syntax option wins",
+			email: `"john doe"@example.com`,
+			opts:  []bemailparts.Option{bemailparts.WithHTML5Validation(), bemailparts.WithRFC5322()},
+			want:  `"john doe"@example.com`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bemailparts.New(tt.email, tt.opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr == nil && got.Email() != tt.want {
+				t.Errorf("New() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("test setters apply options", func(t *testing.T) {
+		e, err := bemailparts.New("john@example.com", bemailparts.WithLowercase(), bemailparts.WithStrict())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = e.SetUsername("Jane.Doe"); err != nil {
+			t.Fatal(err)
+		}
+		if err = e.SetDomainName("Example-Corp"); err != nil {
+			t.Fatal(err)
+		}
+		if e.Email() != "jane.doe@example-corp.com" {
+			t.Errorf("Email() got = %v, want %v", e.Email(), "jane.doe@example-corp.com")
+		}
+		if err = e.SetDomainTLD("c"); !errors.Is(err, bemailparts.ErrEmailDomainTLDTooShort) {
+			t.Errorf("SetDomainTLD() error = %v, want %v", err, bemailparts.ErrEmailDomainTLDTooShort)
+		}
+		if err = e.SetUsername(".."); !errors.Is(err, bemailparts.ErrInvalidEmailUsernameFormat) {
+			t.Errorf("SetUsername() error = %v, want %v", err, bemailparts.ErrInvalidEmailUsernameFormat)
+		}
+	})
+
+	t.Run("test setters reject numeric tld with strict", func(t *testing.T) {
+		e, err := bemailparts.New("john@example.com", bemailparts.WithRFC5322(), bemailparts.WithStrict())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = e.SetDomainTLD("123"); !errors.Is(err, bemailparts.ErrEmailDomainTLDNumeric) {
+			t.Errorf("SetDomainTLD() error = %v, want %v", err, bemailparts.ErrEmailDomainTLDNumeric)
+		}
+		if err = e.SetDomain("example.123"); !errors.Is(err, bemailparts.ErrEmailDomainTLDNumeric) {
+			t.Errorf("SetDomain() error = %v, want %v", err, bemailparts.ErrEmailDomainTLDNumeric)
+		}
+		if e.Email() != "john@example.com" {
+			t.Errorf("Email() got = %v, want %v", e.Email(), "john@example.com")
+		}
+	})
+
+	t.Run("test ascii only explains the conversion", func(t *testing.T) {
+		_, err := bemailparts.New("john@bücher.de", bemailparts.WithASCIIOnly())
+		want := `domain requires IDNA conversion to "xn--bcher-kva.de" at position 5 in domain`
+		if err == nil || err.Error() != want {
+			t.Errorf("New() error = %v, want %v", err, want)
+		}
+		_, err = bemailparts.New("jürgen@example.com", bemailparts.WithASCIIOnly())
+		want = `non-ASCII character 'ü' requires SMTPUTF8 at position 1 in local part`
+		if err == nil || err.Error() != want {
+			t.Errorf("New() error = %v, want %v", err, want)
+		}
+	})
+
+	t.Run("test setters reject non-ascii with ascii only", func(t *testing.T) {
+		e, err := bemailparts.New("john@example.com", bemailparts.WithASCIIOnly(), bemailparts.WithIDN())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = e.SetUsername("jürgen"); !errors.Is(err, bemailparts.ErrEmailRequiresSMTPUTF8) {
+			t.Errorf("SetUsername() error = %v, want %v", err, bemailparts.ErrEmailRequiresSMTPUTF8)
+		}
+		if err = e.SetDomainName("bücher"); !errors.Is(err, bemailparts.ErrEmailRequiresIDNA) {
+			t.Errorf("SetDomainName() error = %v, want %v", err, bemailparts.ErrEmailRequiresIDNA)
+		}
+		if err = e.SetDomainTLD("рф"); !errors.Is(err, bemailparts.ErrEmailRequiresIDNA) {
+			t.Errorf("SetDomainTLD() error = %v, want %v", err, bemailparts.ErrEmailRequiresIDNA)
+		}
+		if e.Email() != "john@example.com" {
+			t.Errorf("Email() got = %v, want %v", e.Email(), "john@example.com")
+		}
+	})
+
+	t.Run("test new from username and domain with options", func(t *testing.T) {
+		got, err := bemailparts.NewFromUsernameAndDomain("John", "Example.com", bemailparts.WithLowercase())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Email() != "john@example.com" {
+			t.Errorf("NewFromUsernameAndDomain() got = %v, want %v", got, "john@example.com")
+		}
+	})
+}
+
+func TestWithAllowSingleLabelDomain(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		opts    []bemailparts.Option
+		wantErr bool
+	}{
+		{name: "success localhost", email: "admin@localhost"},
+		{name: "success hyphenated host", email: "root@mail-host"},
+		{name: "success with strict", email: "root@mailhost", opts: []bemailparts.Option{bemailparts.WithStrict()}},
+		{name: "success multi-label domain", email: "john@example.com"},
+		{name: "error leading hyphen", email: "root@-mailhost", wantErr: true},
+		{name: "error underscore", email: "root@mail_host", wantErr: true},
+		{name: "error empty domain", email: "root@", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]bemailparts.Option{bemailparts.WithAllowSingleLabelDomain()}, tt.opts...)
+			got, err := bemailparts.New(tt.email, opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got.Email() != tt.email {
+				t.Errorf("New() got = %v, want %v", got, tt.email)
+			}
+		})
+	}
+
+	t.Run("test single label domain parts", func(t *testing.T) {
+		if _, err := bemailparts.New("admin@localhost"); err == nil {
+			t.Error("expecting an error on New() without option but got nil")
+		}
+
+		e, err := bemailparts.New("admin@localhost", bemailparts.WithAllowSingleLabelDomain())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.DomainName() != "localhost" || e.DomainTLD() != "" || e.DomainTLDWithoutDot() != "" {
+			t.Errorf("DomainName(), DomainTLD() got = %v, %v, want localhost, empty", e.DomainName(), e.DomainTLD())
+		}
+		if err = e.SetDomainName("mailhost"); err != nil {
+			t.Fatal(err)
+		}
+		if e.Email() != "admin@mailhost" {
+			t.Errorf("Email() got = %v, want admin@mailhost", e.Email())
+		}
+		if err = e.SetDomainTLD("com"); err != nil {
+			t.Fatal(err)
+		}
+		if e.Email() != "admin@mailhost.com" {
+			t.Errorf("Email() got = %v, want admin@mailhost.com", e.Email())
+		}
+		if err = bemailparts.CheckInvariants(e, bemailparts.WithAllowSingleLabelDomain()); err != nil {
+			t.Error(err)
+		}
+	})
+}
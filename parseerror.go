@@ -0,0 +1,273 @@
+package bemailparts
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Parts of an address named by ParseError.
+const (
+	PartLocal  = "local part"
+	PartDomain = "domain"
+)
+
+// ParseError is returned by New and the other constructors parsing a whole address when it is invalid. It
+// tells where the problem is, e.g. "invalid character ' ' at position 4 in local part", so that forms can
+// point users at it. It unwraps to the error describing the problem, such as ErrInvalidEmailUsernameFormat,
+// so errors.Is and ErrorCodeOf work as before.
+type ParseError struct {
+	// Input is the address that was validated, after lowercasing with WithLowercase, removing comments with
+	// WithComments and rewriting obsolete forms with WithObsoleteSyntax. If the comments could not be
+	// removed, it is the address as given.
+	Input string
+
+	// Offset is the byte offset in Input where the problem is, or -1 if it is not known.
+	Offset int
+
+	// Part is PartLocal or PartDomain, or an empty string if the problem concerns the whole address.
+	Part string
+
+	// Msg describes the problem, e.g. "invalid character ' '".
+	Msg string
+
+	// Err is an *EmailError about the part of Input with the problem, wrapping the error of this package
+	// describing it.
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	msg := e.Msg
+	if e.Offset >= 0 {
+		msg += fmt.Sprintf(" at position %d", e.Offset)
+	}
+	if e.Part != "" {
+		msg += " in " + e.Part
+	}
+	return msg
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// emailError returns the *EmailError about the part of e.Input with the problem err.
+func (e *ParseError) emailError(err error) error {
+	at := strings.LastIndex(e.Input, emailSeparator)
+	switch {
+	case at < 0:
+	case e.Part == PartLocal:
+		return newEmailError(FieldUsername, e.Input[:at], err)
+	case e.Part == PartDomain && isTLDError(err):
+		domain := e.Input[at+1:]
+		return newEmailError(FieldTLD, domain[strings.LastIndex(domain, domainSeparator)+1:], err)
+	case e.Part == PartDomain:
+		return newEmailError(FieldDomain, e.Input[at+1:], err)
+	}
+	return newEmailError(FieldEmail, e.Input, err)
+}
+
+// isTLDError reports whether err is about the TLD of a domain that is otherwise valid.
+func isTLDError(err error) bool {
+	return errors.Is(err, ErrEmailDomainTLDTooShort) || errors.Is(err, ErrEmailDomainTLDTooLong) ||
+		errors.Is(err, ErrEmailDomainTLDNumeric) || errors.Is(err, ErrEmailDomainTLDNotAllowed) ||
+		errors.Is(err, ErrEmailDomainTLDUnknown)
+}
+
+// parseError locates the problem err, returned when validating input, in input.
+func (o *options) parseError(input string, err error) error {
+	e := o.locateProblem(input, err)
+	e.Err = e.emailError(err)
+	return e
+}
+
+// locateProblem returns a ParseError telling where in input the problem err is, without Err.
+func (o *options) locateProblem(input string, err error) *ParseError {
+	e := &ParseError{Input: input, Offset: -1, Msg: err.Error()}
+	var cfwsErr *cfwsError
+	if errors.As(err, &cfwsErr) {
+		e.Part, e.Offset, e.Msg = cfwsErr.part, cfwsErr.offset, cfwsErr.msg
+		return e
+	}
+	at := strings.LastIndex(input, emailSeparator)
+	if at < 0 {
+		e.Offset, e.Msg = len(input), "missing '@'"
+		return e
+	}
+	username, domain := input[:at], input[at+1:]
+
+	var validatorErr *validatorError
+	switch {
+	case errors.As(err, &validatorErr):
+		e.Part, e.Offset = validatorErr.part, 0
+		if e.Part == PartDomain {
+			e.Offset = at + 1
+		}
+	case errors.Is(err, ErrEmailRequiresSMTPUTF8):
+		e.Part = PartLocal
+		e.Offset, e.Msg = asciiOnlyProblem(PartLocal, username)
+	case errors.Is(err, ErrEmailRequiresIDNA):
+		offset, msg := asciiOnlyProblem(PartDomain, domain)
+		e.Part, e.Offset, e.Msg = PartDomain, at+1+offset, msg
+	case errors.Is(err, ErrEmailTooLong):
+		e.Offset, e.Msg = maxEmailLength, fmt.Sprintf("address longer than %d octets", maxEmailLength)
+	case errors.Is(err, ErrEmailUsernameTooLong):
+		e.Part, e.Offset = PartLocal, maxUsernameLength
+		e.Msg = fmt.Sprintf("local part longer than %d octets", maxUsernameLength)
+	case errors.Is(err, ErrEmailDomainTooLong):
+		e.Part, e.Offset = PartDomain, at+1+maxDomainLength
+		e.Msg = fmt.Sprintf("domain longer than %d octets", maxDomainLength)
+	case errors.Is(err, ErrEmailDomainTLDTooShort):
+		e.Part, e.Offset = PartDomain, at+1+strings.LastIndex(domain, domainSeparator)+1
+		e.Msg = fmt.Sprintf("top-level domain shorter than %d characters", o.effectiveMinTLDLength())
+	case errors.Is(err, ErrEmailDomainTLDTooLong):
+		e.Part, e.Offset = PartDomain, at+1+strings.LastIndex(domain, domainSeparator)+1+o.effectiveMaxTLDLength()
+		e.Msg = fmt.Sprintf("top-level domain longer than %d characters", o.effectiveMaxTLDLength())
+	case errors.Is(err, ErrEmailDomainTLDNumeric):
+		e.Part, e.Offset = PartDomain, at+1+strings.LastIndex(domain, domainSeparator)+1
+		e.Msg = "top-level domain is numeric"
+	case errors.Is(err, ErrEmailDomainTLDNotAllowed):
+		e.Part, e.Offset = PartDomain, at+1+strings.LastIndex(domain, domainSeparator)+1
+		e.Msg = "top-level domain is not allowed"
+	case errors.Is(err, ErrEmailDomainTLDUnknown):
+		e.Part, e.Offset = PartDomain, at+1+strings.LastIndex(domain, domainSeparator)+1
+		e.Msg = "unknown top-level domain"
+	case errors.Is(err, ErrEmailDomainReserved):
+		use, _ := reservedDomainUse(domain)
+		e.Part, e.Offset, e.Msg = PartDomain, at+1, "domain is reserved for "+use
+	case errors.Is(err, ErrNoRegistrableDomain):
+		e.Part, e.Offset, e.Msg = PartDomain, at+1, "domain is a public suffix"
+	case errors.Is(err, ErrEmailDomainDisposable):
+		e.Part, e.Offset, e.Msg = PartDomain, at+1, "domain is a disposable mailbox service"
+	case errors.Is(err, ErrEmailDomainFreeProvider):
+		e.Part, e.Offset, e.Msg = PartDomain, at+1, "domain is a free mailbox provider"
+	case errors.Is(err, ErrInvalidEmailUsernameFormat),
+		errors.Is(err, ErrInvalidEmailFormat) && o.validateUsernameSyntax(username) != nil:
+		e.Part = PartLocal
+		e.Offset, e.Msg = o.locateUsernameProblem(username)
+		if e.Msg == "" {
+			e.Msg = err.Error()
+		}
+	case errors.Is(err, ErrInvalidAddressLiteral), errors.Is(err, ErrUnsupportedAddressLiteral):
+		e.Part, e.Offset = PartDomain, at+1
+	default:
+		offset, msg := o.locateDomainProblem(domain)
+		e.Part, e.Offset = PartDomain, at+1+offset
+		if msg != "" {
+			e.Msg = msg
+		}
+	}
+	return e
+}
+
+// locateUsernameProblem returns the offset in username of its first problem and a description of it, or an
+// empty description if the problem is not one it recognizes.
+func (o *options) locateUsernameProblem(username string) (int, string) {
+	if username == "" {
+		return 0, "empty local part"
+	}
+	if strings.HasPrefix(username, `"`) {
+		return 0, "invalid quoted string"
+	}
+	if i, r := firstInvalidRune(username, o.isUsernameChar); i >= 0 {
+		return i, fmt.Sprintf("invalid character %q", r)
+	}
+	switch {
+	case strings.HasPrefix(username, domainSeparator):
+		return 0, "local part starts with '.'"
+	case strings.HasSuffix(username, domainSeparator):
+		return len(username) - 1, "local part ends with '.'"
+	case strings.Contains(username, domainSeparator+domainSeparator):
+		return strings.Index(username, domainSeparator+domainSeparator) + 1, "consecutive dots"
+	}
+	return 0, ""
+}
+
+// locateDomainProblem is like locateUsernameProblem for domains.
+func (o *options) locateDomainProblem(domain string) (int, string) {
+	if domain == "" {
+		return 0, "empty domain"
+	}
+	if i, r := firstInvalidRune(domain, o.isDomainChar); i >= 0 {
+		return i, fmt.Sprintf("invalid character %q", r)
+	}
+
+	start := 0
+	for _, label := range strings.Split(domain, domainSeparator) {
+		switch {
+		case label == "":
+			return start, "empty label"
+		case len(label) > maxLabelLength:
+			return start + maxLabelLength, fmt.Sprintf("label longer than %d octets", maxLabelLength)
+		case label[0] == '-':
+			return start, "label starts with '-'"
+		case label[len(label)-1] == '-':
+			return start + len(label) - 1, "label ends with '-'"
+		}
+		start += len(label) + len(domainSeparator)
+	}
+
+	if o.syntax == syntaxDefault {
+		last := strings.LastIndex(domain, domainSeparator)
+		if last < 0 {
+			return len(domain), "missing top-level domain"
+		}
+		if !o.idn || isASCII(domain[last+1:]) {
+			if tld := domain[last+1:]; !isTLDLabel(tld) {
+				return last + 1, "top-level domain must contain only letters"
+			}
+		}
+	}
+	return 0, ""
+}
+
+// asciiOnlyProblem returns the offset in s, a username or a domain rejected by WithASCIIOnly, of the
+// problem and a message explaining the conversion s needs: SMTPUTF8 for a username, and the ACE form for a
+// domain, which is converted as a whole.
+func asciiOnlyProblem(part, s string) (int, string) {
+	i, r := firstInvalidRune(s, func(r rune) bool { return r < utf8.RuneSelf })
+	if part == PartLocal {
+		return i, fmt.Sprintf("non-ASCII character %q requires SMTPUTF8", r)
+	}
+	if ascii, err := ToASCII(s); err == nil {
+		return 0, fmt.Sprintf("domain requires IDNA conversion to %q", ascii)
+	}
+	return i, fmt.Sprintf("non-ASCII character %q requires IDNA conversion", r)
+}
+
+// firstInvalidRune returns the offset and value of the first rune of s rejected by valid, or -1 if there is
+// none. Invalid UTF-8 is reported as utf8.RuneError.
+func firstInvalidRune(s string, valid func(r rune) bool) (int, rune) {
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if (r == utf8.RuneError && size == 1) || !valid(r) {
+			return i, r
+		}
+		i += size
+	}
+	return -1, 0
+}
+
+func (o *options) isUsernameChar(r rune) bool {
+	if r >= utf8.RuneSelf {
+		return o.unicode && !o.asciiOnly && unicode.IsGraphic(r) && !unicode.IsSpace(r)
+	}
+	c := byte(r)
+	if o.syntax == syntaxDefault {
+		return isAlphaNumeric(c) || strings.IndexByte("._%+-", c) >= 0
+	}
+	return isAtext(c) || c == '.'
+}
+
+func (o *options) isDomainChar(r rune) bool {
+	if r >= utf8.RuneSelf {
+		return o.idn && !o.asciiOnly && unicode.IsGraphic(r) && !unicode.IsSpace(r)
+	}
+	c := byte(r)
+	if o.syntax == syntaxRFC5322 {
+		return isAtext(c) || c == '.'
+	}
+	return isAlphaNumeric(c) || c == '-' || c == '.'
+}
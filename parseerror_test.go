@@ -0,0 +1,190 @@
+package bemailparts_test
+
+import (
+	"errors"
+	"github.com/bearaujus/bemailparts"
+	"strings"
+	"testing"
+)
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		name       string
+		email      string
+		opts       []bemailparts.Option
+		wantOffset int
+		wantPart   string
+		wantMsg    string
+		wantErr    error
+	}{
+		{
+			name:       "space in local part",
+			email:      "john doe@example.com",
+			wantOffset: 4,
+			wantPart:   bemailparts.PartLocal,
+			wantMsg:    "invalid character ' ' at position 4 in local part",
+			wantErr:    bemailparts.ErrInvalidEmailFormat,
+		},
+		{
+			name:       "missing at",
+			email:      "john.doe",
+			wantOffset: 8,
+			wantMsg:    "missing '@' at position 8",
+			wantErr:    bemailparts.ErrInvalidEmailFormat,
+		},
+		{
+			name:       "empty local part",
+			email:      "@example.com",
+			wantOffset: 0,
+			wantPart:   bemailparts.PartLocal,
+			wantMsg:    "empty local part at position 0 in local part",
+			wantErr:    bemailparts.ErrInvalidEmailFormat,
+		},
+		{
+			name:       "invalid character in domain",
+			email:      "john@exa_mple.com",
+			wantOffset: 8,
+			wantPart:   bemailparts.PartDomain,
+			wantMsg:    "invalid character '_' at position 8 in domain",
+			wantErr:    bemailparts.ErrInvalidEmailFormat,
+		},
+		{
+			name:       "empty label",
+			email:      "john@example..com",
+			wantOffset: 13,
+			wantPart:   bemailparts.PartDomain,
+			wantMsg:    "empty label at position 13 in domain",
+			wantErr:    bemailparts.ErrInvalidEmailDomainFormat,
+		},
+		{
+			name:       "label ends with hyphen",
+			email:      "john@example-.com",
+			wantOffset: 12,
+			wantPart:   bemailparts.PartDomain,
+			wantMsg:    "label ends with '-' at position 12 in domain",
+			wantErr:    bemailparts.ErrInvalidEmailDomainFormat,
+		},
+		{
+			name:       "missing tld",
+			email:      "john@example",
+			wantOffset: 12,
+			wantPart:   bemailparts.PartDomain,
+			wantMsg:    "missing top-level domain at position 12 in domain",
+			wantErr:    bemailparts.ErrInvalidEmailFormat,
+		},
+		{
+			name:       "tld too long",
+			email:      "john@example.technology",
+			opts:       []bemailparts.Option{bemailparts.WithMaxTLDLength(6)},
+			wantOffset: 19,
+			wantPart:   bemailparts.PartDomain,
+			wantMsg:    "top-level domain longer than 6 characters at position 19 in domain",
+			wantErr:    bemailparts.ErrEmailDomainTLDTooLong,
+		},
+		{
+			name:       "numeric tld",
+			email:      "john@example.c0m",
+			wantOffset: 13,
+			wantPart:   bemailparts.PartDomain,
+			wantMsg:    "top-level domain must contain only letters at position 13 in domain",
+			wantErr:    bemailparts.ErrInvalidEmailFormat,
+		},
+		{
+			name:       "consecutive dots with strict",
+			email:      "jo..hn@example.com",
+			opts:       []bemailparts.Option{bemailparts.WithStrict()},
+			wantOffset: 3,
+			wantPart:   bemailparts.PartLocal,
+			wantMsg:    "consecutive dots at position 3 in local part",
+			wantErr:    bemailparts.ErrInvalidEmailUsernameFormat,
+		},
+		{
+			name:       "tld too short",
+			email:      "john@example.c",
+			opts:       []bemailparts.Option{bemailparts.WithStrict()},
+			wantOffset: 13,
+			wantPart:   bemailparts.PartDomain,
+			wantMsg:    "top-level domain shorter than 2 characters at position 13 in domain",
+			wantErr:    bemailparts.ErrEmailDomainTLDTooShort,
+		},
+		{
+			name:       "numeric tld",
+			email:      "john@example.123",
+			opts:       []bemailparts.Option{bemailparts.WithRFC5322(), bemailparts.WithStrict()},
+			wantOffset: 13,
+			wantPart:   bemailparts.PartDomain,
+			wantMsg:    "top-level domain is numeric at position 13 in domain",
+			wantErr:    bemailparts.ErrEmailDomainTLDNumeric,
+		},
+		{
+			name:       "local part too long",
+			email:      strings.Repeat("a", 65) + "@example.com",
+			wantOffset: 64,
+			wantPart:   bemailparts.PartLocal,
+			wantMsg:    "local part longer than 64 octets at position 64 in local part",
+			wantErr:    bemailparts.ErrEmailUsernameTooLong,
+		},
+		{
+			name:       "rfc5322 local part",
+			email:      "john(doe@example.com",
+			opts:       []bemailparts.Option{bemailparts.WithRFC5322()},
+			wantOffset: 4,
+			wantPart:   bemailparts.PartLocal,
+			wantMsg:    "invalid character '(' at position 4 in local part",
+			wantErr:    bemailparts.ErrInvalidEmailFormat,
+		},
+		{
+			name:       "invalid address literal",
+			email:      "john@[300.0.0.1]",
+			opts:       []bemailparts.Option{bemailparts.WithAllowIPDomain()},
+			wantOffset: 5,
+			wantPart:   bemailparts.PartDomain,
+			wantMsg:    "invalid email address literal at position 5 in domain",
+			wantErr:    bemailparts.ErrInvalidAddressLiteral,
+		},
+		{
+			name:       "unterminated comment in local part",
+			email:      "john(work@example.com",
+			opts:       []bemailparts.Option{bemailparts.WithComments()},
+			wantOffset: 4,
+			wantPart:   bemailparts.PartLocal,
+			wantMsg:    "unterminated comment at position 4 in local part",
+			wantErr:    bemailparts.ErrInvalidEmailFormat,
+		},
+		{
+			name:       "unterminated comment in domain",
+			email:      "john@example.com (work",
+			opts:       []bemailparts.Option{bemailparts.WithComments()},
+			wantOffset: 17,
+			wantPart:   bemailparts.PartDomain,
+			wantMsg:    "unterminated comment at position 17 in domain",
+			wantErr:    bemailparts.ErrInvalidEmailFormat,
+		},
+		{
+			name:       "missing at with comments",
+			email:      "john.doe (work)",
+			opts:       []bemailparts.Option{bemailparts.WithComments()},
+			wantOffset: 15,
+			wantMsg:    "missing '@' at position 15",
+			wantErr:    bemailparts.ErrInvalidEmailFormat,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := bemailparts.New(tt.email, tt.opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var parseErr *bemailparts.ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("New() error = %T, want *ParseError", err)
+			}
+			if parseErr.Input != tt.email || parseErr.Offset != tt.wantOffset || parseErr.Part != tt.wantPart {
+				t.Errorf("ParseError got = %+v, want Offset %v, Part %q", parseErr, tt.wantOffset, tt.wantPart)
+			}
+			if err.Error() != tt.wantMsg {
+				t.Errorf("Error() got = %q, want %q", err.Error(), tt.wantMsg)
+			}
+		})
+	}
+}
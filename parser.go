@@ -0,0 +1,91 @@
+package bemailparts
+
+// Parser creates BEmailParts instances with a fixed set of Options, so callers configure validation once
+// instead of passing the same options to every constructor call.
+//
+// A Parser is safe for concurrent use.
+type Parser struct {
+	opts *options
+}
+
+// NewParser creates a Parser applying opts to every address it parses.
+//
+// Example:
+//
+//	parser := NewParser(WithRFC5321(), WithLowercase(), WithStrict())
+//	emailParts, err := parser.Parse("John.Doe@Example.com")
+//	if err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	fmt.Println(emailParts.Email()) // Output: john.doe@example.com
+func NewParser(opts ...Option) *Parser {
+	return &Parser{opts: newOptions(opts)}
+}
+
+// Parse is like New, using the options of the Parser.
+func (p *Parser) Parse(email string) (BEmailParts, error) {
+	return newEmailParts(email, p.opts)
+}
+
+// Validate is like the package-level Validate, using the options of the Parser.
+func (p *Parser) Validate(email string) error {
+	return validate(email, p.opts)
+}
+
+// IsValid reports whether email is valid with the options of the Parser. Unlike the package-level IsValid,
+// it accepts every address the options do, including internationalized ones.
+func (p *Parser) IsValid(email string) bool {
+	return isValid(email, p.opts)
+}
+
+// ParseList parses every address of emails, such as the rows of an import, like Parse.
+//
+// Returns:
+//   - A BEmailParts instance per address, in order, with nil for the addresses that could not be parsed.
+//   - An *AddressListError describing every failed address, or nil if all addresses were parsed.
+func (p *Parser) ParseList(emails []string) ([]BEmailParts, error) {
+	addresses := make([]BEmailParts, len(emails))
+	var listErr *AddressListError
+	for i, email := range emails {
+		e, err := newEmailParts(email, p.opts)
+		if err != nil {
+			if listErr == nil {
+				listErr = &AddressListError{}
+			}
+			listErr.Errors = append(listErr.Errors, &AddressError{Index: i, Address: email, Err: err})
+			continue
+		}
+		addresses[i] = e
+	}
+
+	if listErr != nil {
+		return addresses, listErr
+	}
+	return addresses, nil
+}
+
+// ParseFromUsernameAndDomain is like NewFromUsernameAndDomain, using the options of the Parser.
+func (p *Parser) ParseFromUsernameAndDomain(username, domain string) (BEmailParts, error) {
+	return newFromUsernameAndDomain(username, domain, p.opts)
+}
+
+// ParseFromFullParts is like NewFromFullParts, using the options of the Parser.
+func (p *Parser) ParseFromFullParts(username, domainName, domainTLD string) (BEmailParts, error) {
+	return newFromFullParts(username, domainName, domainTLD, p.opts)
+}
+
+// ParseAddress is like the package-level ParseAddress, using the options of the Parser.
+func (p *Parser) ParseAddress(address string) (BEmailParts, error) {
+	return parseAddress(address, p.opts)
+}
+
+// ParseAddressList is like the package-level ParseAddressList, using the options of the Parser.
+func (p *Parser) ParseAddressList(list string) ([]BEmailParts, error) {
+	return parseAddressList(list, p.opts)
+}
+
+// ParseAddressGroups is like the package-level ParseAddressGroups, using the options of the Parser.
+func (p *Parser) ParseAddressGroups(list string) ([]AddressGroup, error) {
+	return parseAddressGroups(list, p.opts)
+}
@@ -0,0 +1,85 @@
+package bemailparts_test
+
+import (
+	"errors"
+	"github.com/bearaujus/bemailparts"
+	"sync"
+	"testing"
+)
+
+func TestParser(t *testing.T) {
+	parser := bemailparts.NewParser(bemailparts.WithLowercase(), bemailparts.WithStrict())
+
+	t.Run("test parse", func(t *testing.T) {
+		got, err := parser.Parse("John.Doe@Example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Email() != "john.doe@example.com" {
+			t.Errorf("Parse() got = %v, want %v", got, "john.doe@example.com")
+		}
+		if _, err = parser.Parse("john.doe@example.c"); !errors.Is(err, bemailparts.ErrEmailDomainTLDTooShort) {
+			t.Errorf("Parse() error = %v, want %v", err, bemailparts.ErrEmailDomainTLDTooShort)
+		}
+	})
+
+	t.Run("test parse from parts", func(t *testing.T) {
+		got, err := parser.ParseFromUsernameAndDomain("John", "Example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Email() != "john@example.com" {
+			t.Errorf("ParseFromUsernameAndDomain() got = %v, want %v", got, "john@example.com")
+		}
+
+		got, err = parser.ParseFromFullParts("John", "Example", "COM")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Email() != "john@example.com" {
+			t.Errorf("ParseFromFullParts() got = %v, want %v", got, "john@example.com")
+		}
+	})
+
+	t.Run("test is valid", func(t *testing.T) {
+		if !parser.IsValid("John.Doe@Example.com") {
+			t.Error("IsValid() got false for a valid address")
+		}
+		if parser.IsValid("john.doe@example.c") {
+			t.Error("IsValid() got true for a TLD too short with WithStrict")
+		}
+	})
+
+	t.Run("test parse list", func(t *testing.T) {
+		got, err := parser.ParseList([]string{"John@Example.com", "invalid", "jane@example.org"})
+		var listErr *bemailparts.AddressListError
+		if !errors.As(err, &listErr) || len(listErr.Errors) != 1 || listErr.Errors[0].Index != 1 {
+			t.Fatalf("ParseList() error = %v, want one error at index 1", err)
+		}
+		if len(got) != 3 || got[0].Email() != "john@example.com" || got[1] != nil || got[2].Email() != "jane@example.org" {
+			t.Errorf("ParseList() got = %v", got)
+		}
+		if _, err = parser.ParseList([]string{"john@example.com"}); err != nil {
+			t.Errorf("ParseList() error = %v, want nil", err)
+		}
+	})
+
+	t.Run("test concurrent use", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				e, err := parser.Parse("John@Example.com")
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if err = e.SetUsername("Jane"); err != nil {
+					t.Error(err)
+				}
+			}()
+		}
+		wg.Wait()
+	})
+}
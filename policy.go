@@ -0,0 +1,31 @@
+package bemailparts
+
+// PolicyCorporateSignup returns the options of a signup form requiring a work address, so that teams start
+// from a sane default instead of assembling options from scratch:
+//
+//   - WithStrict, for addresses that are deliverable in practice;
+//   - WithKnownTLDsOnly and WithRequireRegistrableDomain, for domains under a delegated TLD and below a
+//     public suffix of the embedded Public Suffix List;
+//   - WithRejectReservedDomains, WithRejectDisposableDomains and WithRejectFreeProviders, for domains that
+//     are reserved, disposable or belong to a free mailbox provider.
+//
+// It does not reject role accounts such as "admin@" or "sales@", nor addresses of forwarding relays, as the
+// package has no data to recognize them; add a WithUsernameValidator or WithDomainValidator for these. It
+// cannot check that the domain receives mail either, since parsing does no network lookups: run Preflight
+// with WithMXCheck before sending. Options passed after the policy add to it, e.g. WithFreeProviders.
+//
+// Example:
+//
+//	parser := NewParser(append(PolicyCorporateSignup(), WithFreeProviders(nil, []string{"fastmail.com"}))...)
+//	_, err := parser.Parse("john@gmail.com")
+//	fmt.Println(errors.Is(err, ErrEmailDomainFreeProvider)) // Output: true
+func PolicyCorporateSignup() []Option {
+	return []Option{
+		WithStrict(),
+		WithKnownTLDsOnly(),
+		WithRequireRegistrableDomain(),
+		WithRejectReservedDomains(),
+		WithRejectDisposableDomains(),
+		WithRejectFreeProviders(),
+	}
+}
@@ -0,0 +1,38 @@
+package bemailparts_test
+
+import (
+	"errors"
+	"github.com/bearaujus/bemailparts"
+	"testing"
+)
+
+func TestPolicyCorporateSignup(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		wantErr error
+	}{
+		{name: "work address", email: "john.doe@acme.co.uk"},
+		{name: "free provider", email: "john@gmail.com", wantErr: bemailparts.ErrEmailDomainFreeProvider},
+		{name: "disposable domain", email: "john@mailinator.com", wantErr: bemailparts.ErrEmailDomainDisposable},
+		{name: "reserved domain", email: "john@example.com", wantErr: bemailparts.ErrEmailDomainReserved},
+		{name: "public suffix", email: "john@co.uk", wantErr: bemailparts.ErrNoRegistrableDomain},
+		{name: "unknown tld", email: "john@acme.notatld", wantErr: bemailparts.ErrEmailDomainTLDUnknown},
+		{name: "consecutive dots", email: "john..doe@acme.com", wantErr: bemailparts.ErrInvalidEmailUsernameFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := bemailparts.New(tt.email, bemailparts.PolicyCorporateSignup()...)
+			if !errors.Is(err, tt.wantErr) || (err != nil) != (tt.wantErr != nil) {
+				t.Errorf("New() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("test options passed after the policy add to it", func(t *testing.T) {
+		opts := append(bemailparts.PolicyCorporateSignup(), bemailparts.WithFreeProviders(nil, []string{"gmail.com"}))
+		if _, err := bemailparts.New("john@gmail.com", opts...); err != nil {
+			t.Errorf("New() error = %v, want nil", err)
+		}
+	})
+}
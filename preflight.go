@@ -0,0 +1,424 @@
+package bemailparts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MXResolver looks up the MX records of a domain. *net.Resolver satisfies this interface.
+type MXResolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+}
+
+// HostResolver looks up the addresses of a host. An MXResolver also implementing HostResolver, such as
+// *net.Resolver, lets the MX check of Preflight fall back to the A and AAAA records of domains without MX
+// records.
+type HostResolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// PreflightOption configures the checks run by Preflight.
+type PreflightOption func(*preflightOptions)
+
+type preflightOptions struct {
+	suppressions  *SuppressionList
+	mxResolver    MXResolver
+	mxCache       *MXCache
+	mxUnavailable MXUnavailable
+	domainOrder   bool
+	dryRun        bool
+}
+
+// WithSuppressionList makes Preflight reject recipients found in list.
+func WithSuppressionList(list *SuppressionList) PreflightOption {
+	return func(o *preflightOptions) {
+		o.suppressions = list
+	}
+}
+
+// WithMXCheck makes Preflight reject recipients whose domain cannot receive mail, as reported by resolver:
+// the domain does not exist, or it publishes a null MX record (RFC 7505). A domain without MX records
+// receives mail at its A or AAAA records (RFC 5321), so if resolver also implements HostResolver, a domain
+// without MX records is rejected only if LookupHost reports that it does not exist either. Lookups failing
+// for another reason, e.g. a timeout, are handled as set by WithMXUnavailable. Passing nil uses
+// net.DefaultResolver. Each domain is looked up once per Preflight call.
+func WithMXCheck(resolver MXResolver) PreflightOption {
+	return func(o *preflightOptions) {
+		if resolver == nil {
+			resolver = net.DefaultResolver
+		}
+		o.mxResolver = resolver
+	}
+}
+
+// MXUnavailable is what Preflight does with a recipient when the MX lookup of its domain fails without
+// telling whether the domain exists, e.g. because the resolver timed out or returned a server failure.
+type MXUnavailable int
+
+const (
+	// MXUnavailableReject rejects the recipient with ErrDomainWithoutMX, as if the domain could not receive
+	// mail.
+	MXUnavailableReject MXUnavailable = iota
+
+	// MXUnavailableAccept accepts the recipient and records it in PreflightResult.Unverified, so that an
+	// outage of the resolver does not reject a whole send.
+	MXUnavailableAccept
+
+	// MXUnavailableUseStale decides on the recipient with the last result of the domain kept by the MXCache
+	// of WithMXCache, however old, and rejects it as MXUnavailableReject does if there is none. Recipients
+	// accepted this way are recorded in PreflightResult.Unverified.
+	MXUnavailableUseStale
+)
+
+// WithMXUnavailable sets what Preflight does with a recipient when the lookups of WithMXCheck fail with any
+// error but a *net.DNSError reporting that the domain was not found, which always rejects the recipient.
+// The default is MXUnavailableReject.
+func WithMXUnavailable(action MXUnavailable) PreflightOption {
+	return func(o *preflightOptions) {
+		o.mxUnavailable = action
+	}
+}
+
+// WithMXCache makes Preflight keep the results of WithMXCheck in cache, so that domains checked by an
+// earlier call are not looked up again while their result is fresh, and so that MXUnavailableUseStale has
+// a result to fall back to. Only lookups telling whether the domain can receive mail are cached.
+func WithMXCache(cache *MXCache) PreflightOption {
+	return func(o *preflightOptions) {
+		o.mxCache = cache
+	}
+}
+
+// MXCache keeps the results of the MX checks of Preflight across calls. A result is fresh for the ttl of
+// the cache, and kept once stale for MXUnavailableUseStale.
+//
+// An MXCache is safe for concurrent use.
+type MXCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	clock   Clock
+	results map[string]mxCacheEntry
+}
+
+type mxCacheEntry struct {
+	result mxResult
+	at     time.Time
+}
+
+// NewMXCache creates an empty MXCache whose results are fresh for ttl.
+//
+// Example:
+//
+//	cache := NewMXCache(time.Hour)
+//	result := Preflight(recipients,
+//	    WithMXCheck(nil),
+//	    WithMXCache(cache),
+//	    WithMXUnavailable(MXUnavailableUseStale),
+//	)
+func NewMXCache(ttl time.Duration) *MXCache {
+	return &MXCache{
+		ttl:     ttl,
+		clock:   systemClock,
+		results: map[string]mxCacheEntry{},
+	}
+}
+
+// SetClock makes the MXCache read the current time from clock, e.g. to simulate the expiry of its results
+// in tests. Passing nil restores the system clock.
+func (c *MXCache) SetClock(clock Clock) {
+	if clock == nil {
+		clock = systemClock
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clock = clock
+}
+
+// Len returns the number of domains in the cache, fresh or stale.
+func (c *MXCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.results)
+}
+
+// get returns the result cached for domain and whether it is still fresh.
+func (c *MXCache) get(domain string) (r mxResult, fresh, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.results[domain]
+	if !ok {
+		return mxResult{}, false, false
+	}
+	return entry.result, c.clock.Now().Sub(entry.at) < c.ttl, true
+}
+
+func (c *MXCache) put(domain string, r mxResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.results[domain] = mxCacheEntry{result: r, at: c.clock.Now()}
+}
+
+// WithDomainOrder makes Preflight group Accepted and Rejected by domain, in ascending (case-insensitive)
+// order of domain and then input order. The domain of a recipient is the text after its last '@', even if
+// the recipient is invalid, such as "foo@bar"; recipients without any '@' come first. Sending per domain in
+// this order helps to respect per-provider rate limits.
+func WithDomainOrder() PreflightOption {
+	return func(o *preflightOptions) {
+		o.domainOrder = true
+	}
+}
+
+// WithDryRun makes the suppression and MX checks of Preflight run in shadow: recipients they would reject
+// are accepted and recorded in PreflightResult.Shadowed instead. Operators can evaluate a new suppression
+// list or resolver against live traffic this way before enforcing it. Invalid and duplicate recipients are
+// still rejected.
+func WithDryRun() PreflightOption {
+	return func(o *preflightOptions) {
+		o.dryRun = true
+	}
+}
+
+// PreflightResult splits recipients into a send-ready list and a rejected list.
+type PreflightResult struct {
+	// Accepted holds the send-ready recipients in input order, or in domain order with WithDomainOrder,
+	// without duplicates.
+	Accepted []string
+
+	// Rejected holds the rejected recipients in input order, or in domain order with WithDomainOrder, each
+	// with the reason it was rejected.
+	Rejected []PreflightRejection
+
+	// Shadowed holds the recipients accepted only because of WithDryRun, in the order of Accepted, each
+	// once, with the reason it would have been rejected for first.
+	Shadowed []PreflightRejection
+
+	// Unverified holds the recipients accepted without an MX check, or on a stale result of it, because of
+	// WithMXUnavailable, in the order of Accepted, each with the error of the failed lookup.
+	Unverified []PreflightRejection
+}
+
+// PreflightRejection is a recipient rejected by Preflight.
+type PreflightRejection struct {
+	Email string
+	Err   error
+}
+
+// Preflight checks recipients before a bulk send, like PreflightContext with context.Background().
+func Preflight(recipients []string, opts ...PreflightOption) *PreflightResult {
+	return PreflightContext(context.Background(), recipients, opts...)
+}
+
+// PreflightContext checks recipients before a bulk send. Every recipient is validated, and duplicates
+// (case-insensitive) of an earlier recipient are rejected with ErrDuplicateEmail. Depending on opts,
+// suppressed recipients are rejected with ErrEmailSuppressed and recipients on domains that cannot receive
+// mail with ErrDomainWithoutMX. The lookups of WithMXCheck use ctx: once ctx is done, the recipients left to
+// check are rejected with ctx.Err(), even with WithDryRun or MXUnavailableAccept. The result is
+// deterministic: the same recipients and checks always produce the same result in the same order.
+//
+// Example:
+//
+//	result := PreflightContext(ctx,
+//	    []string{"john.doe@example.com", "JOHN.DOE@example.com", "invalid"},
+//	    WithSuppressionList(list),
+//	    WithMXCheck(nil),
+//	)
+//
+//	fmt.Println(result.Accepted) // Output: [john.doe@example.com]
+//	for _, r := range result.Rejected {
+//	    fmt.Println(r.Email, r.Err)
+//	}
+func PreflightContext(ctx context.Context, recipients []string, opts ...PreflightOption) *PreflightResult {
+	o := &preflightOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	result := &PreflightResult{}
+	reject := func(email string, err error) {
+		result.Rejected = append(result.Rejected, PreflightRejection{Email: email, Err: err})
+	}
+	// check rejects email for a failed suppression or MX check, or accepts and shadows it with WithDryRun.
+	// Either way, the remaining checks are skipped, so each recipient is shadowed at most once.
+	check := func(email string, err error) {
+		if o.dryRun {
+			result.Accepted = append(result.Accepted, email)
+			result.Shadowed = append(result.Shadowed, PreflightRejection{Email: email, Err: err})
+			return
+		}
+		reject(email, err)
+	}
+
+	seen := map[string]bool{}
+	mx := map[string]mxResult{}
+	for _, recipient := range recipients {
+		e, err := New(recipient)
+		if err != nil {
+			reject(recipient, err)
+			continue
+		}
+
+		key := strings.ToLower(e.Email())
+		if seen[key] {
+			reject(recipient, ErrDuplicateEmail)
+			continue
+		}
+		seen[key] = true
+
+		if o.suppressions != nil {
+			if reason, ok := o.suppressions.Reason(recipient); ok {
+				check(recipient, fmt.Errorf("%w: %s", ErrEmailSuppressed, reason))
+				continue
+			}
+		}
+
+		if o.mxResolver != nil {
+			// A lookup failing because ctx is done tells nothing about the domain, so it is neither handled
+			// as unavailable nor cached.
+			if err := ctx.Err(); err != nil {
+				reject(recipient, err)
+				continue
+			}
+			domain := strings.ToLower(e.Domain())
+			r, ok := mx[domain]
+			if !ok {
+				r, err = o.lookupMX(ctx, domain)
+				if err != nil {
+					reject(recipient, err)
+					continue
+				}
+				mx[domain] = r
+			}
+			if r.unavailable && (o.mxUnavailable == MXUnavailableAccept || r.stale && r.err == nil) {
+				result.Unverified = append(result.Unverified, PreflightRejection{Email: recipient, Err: r.lookupErr})
+			} else if r.err != nil {
+				check(recipient, r.err)
+				continue
+			}
+		}
+
+		result.Accepted = append(result.Accepted, recipient)
+	}
+
+	if o.domainOrder {
+		sort.SliceStable(result.Accepted, func(i, j int) bool {
+			return recipientDomain(result.Accepted[i]) < recipientDomain(result.Accepted[j])
+		})
+		sort.SliceStable(result.Rejected, func(i, j int) bool {
+			return recipientDomain(result.Rejected[i].Email) < recipientDomain(result.Rejected[j].Email)
+		})
+		sort.SliceStable(result.Shadowed, func(i, j int) bool {
+			return recipientDomain(result.Shadowed[i].Email) < recipientDomain(result.Shadowed[j].Email)
+		})
+		sort.SliceStable(result.Unverified, func(i, j int) bool {
+			return recipientDomain(result.Unverified[i].Email) < recipientDomain(result.Unverified[j].Email)
+		})
+	}
+	return result
+}
+
+// lookupMX checks domain with the resolver, or with the cache of WithMXCache while its result is fresh.
+// Returns ctx.Err() if ctx is done once the lookup returns.
+func (o *preflightOptions) lookupMX(ctx context.Context, domain string) (mxResult, error) {
+	var stale mxResult
+	var cached bool
+	if o.mxCache != nil {
+		r, fresh, ok := o.mxCache.get(domain)
+		if fresh {
+			return r, nil
+		}
+		stale, cached = r, ok
+	}
+
+	r := checkMX(ctx, o.mxResolver, domain)
+	if err := ctx.Err(); err != nil {
+		return mxResult{}, err
+	}
+	if !r.unavailable {
+		if o.mxCache != nil {
+			o.mxCache.put(domain, r)
+		}
+		return r, nil
+	}
+	if o.mxUnavailable == MXUnavailableUseStale && cached {
+		stale.lookupErr, stale.unavailable, stale.stale = r.lookupErr, true, true
+		return stale, nil
+	}
+	return r, nil
+}
+
+// recipientDomain returns the lowercased domain of recipient, or an empty string if it has none.
+func recipientDomain(recipient string) string {
+	i := strings.LastIndex(recipient, emailSeparator)
+	if i < 0 {
+		return ""
+	}
+	return strings.ToLower(recipient[i+1:])
+}
+
+// mxResult is the result of the MX check of a domain.
+type mxResult struct {
+	// err is the reason to reject recipients on the domain, or nil if it can receive mail.
+	err error
+
+	// lookupErr is the error of the lookup, and unavailable whether it failed without telling whether the
+	// domain exists.
+	lookupErr   error
+	unavailable bool
+
+	// stale is whether err is the cached result of an earlier lookup, used because this one was unavailable.
+	stale bool
+}
+
+// checkMX checks whether domain can receive mail. Only a domain that does not exist or publishes a null MX
+// record cannot.
+func checkMX(ctx context.Context, resolver MXResolver, domain string) mxResult {
+	records, err := resolver.LookupMX(ctx, domain)
+	if err != nil && !isDomainNotFound(err) {
+		return mxUnavailableResult(err)
+	}
+	for _, record := range records {
+		// A single "." host is a null MX (RFC 7505): the domain accepts no mail.
+		if record.Host != "." {
+			return mxResult{}
+		}
+	}
+	if len(records) > 0 {
+		return mxResult{err: ErrDomainWithoutMX}
+	}
+
+	// Without MX records, mail is delivered to the A or AAAA records of the domain (RFC 5321, section 5.1).
+	// net.Resolver reports a domain without MX records as not found, so only the host lookup tells whether
+	// the domain exists.
+	hosts, ok := resolver.(HostResolver)
+	if !ok {
+		if err != nil {
+			return mxResult{err: fmt.Errorf("%w: %v", ErrDomainWithoutMX, err), lookupErr: err}
+		}
+		return mxResult{}
+	}
+	if _, err = hosts.LookupHost(ctx, domain); err != nil {
+		if isDomainNotFound(err) {
+			return mxResult{err: fmt.Errorf("%w: %v", ErrDomainWithoutMX, err), lookupErr: err}
+		}
+		return mxUnavailableResult(err)
+	}
+	return mxResult{}
+}
+
+// mxUnavailableResult returns the result of an MX check whose lookup failed with err, which does not tell
+// whether the domain exists.
+func mxUnavailableResult(err error) mxResult {
+	return mxResult{err: fmt.Errorf("%w: %v", ErrDomainWithoutMX, err), lookupErr: err, unavailable: true}
+}
+
+// isDomainNotFound reports whether err is a *net.DNSError reporting that the domain was not found.
+func isDomainNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
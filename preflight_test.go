@@ -0,0 +1,374 @@
+package bemailparts_test
+
+import (
+	"context"
+	"errors"
+	"github.com/bearaujus/bemailparts"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type fakeMXResolver map[string][]*net.MX
+
+func (f fakeMXResolver) LookupMX(_ context.Context, name string) ([]*net.MX, error) {
+	records, ok := f[name]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return records, nil
+}
+
+func TestPreflight(t *testing.T) {
+	list := bemailparts.NewSuppressionList()
+	if err := list.Add("bounced@example.com", bemailparts.SuppressionBounce); err != nil {
+		t.Fatal(err)
+	}
+	resolver := fakeMXResolver{
+		"example.com": {{Host: "mx.example.com", Pref: 10}},
+		"null-mx.com": {{Host: ".", Pref: 0}},
+	}
+
+	recipients := []string{
+		"john.doe@example.com",
+		"invalid",
+		"JOHN.DOE@example.com",
+		"bounced@example.com",
+		"jane@null-mx.com",
+		"jane@missing.com",
+		"jane.doe@example.com",
+	}
+	got := bemailparts.Preflight(recipients, bemailparts.WithSuppressionList(list), bemailparts.WithMXCheck(resolver))
+
+	if want := []string{"john.doe@example.com", "jane.doe@example.com"}; !reflect.DeepEqual(got.Accepted, want) {
+		t.Errorf("Preflight() got Accepted = %v, want %v", got.Accepted, want)
+	}
+
+	wantRejected := []struct {
+		email string
+		err   error
+	}{
+		{email: "invalid", err: bemailparts.ErrInvalidEmailFormat},
+		{email: "JOHN.DOE@example.com", err: bemailparts.ErrDuplicateEmail},
+		{email: "bounced@example.com", err: bemailparts.ErrEmailSuppressed},
+		{email: "jane@null-mx.com", err: bemailparts.ErrDomainWithoutMX},
+		{email: "jane@missing.com", err: bemailparts.ErrDomainWithoutMX},
+	}
+	if len(got.Rejected) != len(wantRejected) {
+		t.Fatalf("Preflight() got Rejected = %v, want %v", got.Rejected, wantRejected)
+	}
+	for i, want := range wantRejected {
+		if got.Rejected[i].Email != want.email || !errors.Is(got.Rejected[i].Err, want.err) {
+			t.Errorf("Preflight() got Rejected[%d] = %v, want %v", i, got.Rejected[i], want)
+		}
+	}
+
+	plain := bemailparts.Preflight([]string{"bounced@example.com", "jane@missing.com"})
+	if len(plain.Accepted) != 2 {
+		t.Errorf("Preflight() without options got Accepted = %v", plain.Accepted)
+	}
+}
+
+func TestPreflightWithDomainOrder(t *testing.T) {
+	recipients := []string{
+		"zoe@b.org",
+		"adam@c.net",
+		"invalid",
+		"bob@A.com",
+		"zoe@b.org",
+		"amy@b.org",
+		"carl@a.com",
+	}
+	got := bemailparts.Preflight(recipients, bemailparts.WithDomainOrder())
+
+	want := []string{"bob@A.com", "carl@a.com", "zoe@b.org", "amy@b.org", "adam@c.net"}
+	if !reflect.DeepEqual(got.Accepted, want) {
+		t.Errorf("Preflight() got Accepted = %v, want %v", got.Accepted, want)
+	}
+	if len(got.Rejected) != 2 || got.Rejected[0].Email != "invalid" || got.Rejected[1].Email != "zoe@b.org" {
+		t.Errorf("Preflight() got Rejected = %v, want [invalid zoe@b.org]", got.Rejected)
+	}
+
+	if again := bemailparts.Preflight(recipients, bemailparts.WithDomainOrder()); !reflect.DeepEqual(again, got) {
+		t.Errorf("Preflight() got = %v on second run, want %v", again, got)
+	}
+}
+
+func TestPreflightWithDryRun(t *testing.T) {
+	list := bemailparts.NewSuppressionList()
+	if err := list.Add("bounced@example.com", bemailparts.SuppressionBounce); err != nil {
+		t.Fatal(err)
+	}
+	resolver := fakeMXResolver{"example.com": {{Host: "mx.example.com", Pref: 10}}}
+
+	recipients := []string{"bounced@example.com", "invalid", "jane@missing.com", "john@example.com", "JOHN@example.com"}
+	got := bemailparts.Preflight(recipients,
+		bemailparts.WithSuppressionList(list),
+		bemailparts.WithMXCheck(resolver),
+		bemailparts.WithDryRun(),
+	)
+
+	if want := []string{"bounced@example.com", "jane@missing.com", "john@example.com"}; !reflect.DeepEqual(got.Accepted, want) {
+		t.Errorf("Preflight() got Accepted = %v, want %v", got.Accepted, want)
+	}
+	if len(got.Rejected) != 2 || got.Rejected[0].Email != "invalid" || got.Rejected[1].Email != "JOHN@example.com" {
+		t.Errorf("Preflight() got Rejected = %v, want [invalid JOHN@example.com]", got.Rejected)
+	}
+	if len(got.Shadowed) != 2 ||
+		got.Shadowed[0].Email != "bounced@example.com" || !errors.Is(got.Shadowed[0].Err, bemailparts.ErrEmailSuppressed) ||
+		got.Shadowed[1].Email != "jane@missing.com" || !errors.Is(got.Shadowed[1].Err, bemailparts.ErrDomainWithoutMX) {
+		t.Errorf("Preflight() got Shadowed = %v, want bounced@example.com and jane@missing.com", got.Shadowed)
+	}
+}
+
+// timeoutMXResolver times out looking up the domains it holds and delegates the others.
+type timeoutMXResolver struct {
+	fakeMXResolver
+	timeouts map[string]bool
+}
+
+func (f timeoutMXResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	if f.timeouts[name] {
+		return nil, &net.DNSError{Err: "i/o timeout", Name: name, IsTimeout: true}
+	}
+	return f.fakeMXResolver.LookupMX(ctx, name)
+}
+
+func TestPreflightWithMXUnavailable(t *testing.T) {
+	resolver := timeoutMXResolver{
+		fakeMXResolver: fakeMXResolver{"example.com": {{Host: "mx.example.com", Pref: 10}}},
+		timeouts:       map[string]bool{"slow.com": true},
+	}
+	recipients := []string{"jane@slow.com", "jane@missing.com", "john@example.com", "john@slow.com"}
+
+	tests := []struct {
+		name           string
+		opts           []bemailparts.PreflightOption
+		wantAccepted   []string
+		wantRejected   []string
+		wantUnverified []string
+	}{
+		{
+			name:         "reject by default",
+			wantAccepted: []string{"john@example.com"},
+			wantRejected: []string{"jane@slow.com", "jane@missing.com", "john@slow.com"},
+		},
+		{
+			name:         "reject",
+			opts:         []bemailparts.PreflightOption{bemailparts.WithMXUnavailable(bemailparts.MXUnavailableReject)},
+			wantAccepted: []string{"john@example.com"},
+			wantRejected: []string{"jane@slow.com", "jane@missing.com", "john@slow.com"},
+		},
+		{
+			name:           "accept",
+			opts:           []bemailparts.PreflightOption{bemailparts.WithMXUnavailable(bemailparts.MXUnavailableAccept)},
+			wantAccepted:   []string{"jane@slow.com", "john@example.com", "john@slow.com"},
+			wantRejected:   []string{"jane@missing.com"},
+			wantUnverified: []string{"jane@slow.com", "john@slow.com"},
+		},
+		{
+			name: "accept in dry run",
+			opts: []bemailparts.PreflightOption{
+				bemailparts.WithMXUnavailable(bemailparts.MXUnavailableAccept),
+				bemailparts.WithDryRun(),
+			},
+			wantAccepted:   []string{"jane@slow.com", "jane@missing.com", "john@example.com", "john@slow.com"},
+			wantUnverified: []string{"jane@slow.com", "john@slow.com"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]bemailparts.PreflightOption{bemailparts.WithMXCheck(resolver)}, tt.opts...)
+			got := bemailparts.Preflight(recipients, opts...)
+
+			if !reflect.DeepEqual(got.Accepted, tt.wantAccepted) {
+				t.Errorf("Preflight() got Accepted = %v, want %v", got.Accepted, tt.wantAccepted)
+			}
+			var rejected, unverified []string
+			for _, r := range got.Rejected {
+				rejected = append(rejected, r.Email)
+				if !errors.Is(r.Err, bemailparts.ErrDomainWithoutMX) {
+					t.Errorf("Preflight() got Rejected error %v for %v, want %v", r.Err, r.Email, bemailparts.ErrDomainWithoutMX)
+				}
+			}
+			for _, r := range got.Unverified {
+				unverified = append(unverified, r.Email)
+				var dnsErr *net.DNSError
+				if !errors.As(r.Err, &dnsErr) || !dnsErr.IsTimeout {
+					t.Errorf("Preflight() got Unverified error %v for %v, want the lookup timeout", r.Err, r.Email)
+				}
+			}
+			if !reflect.DeepEqual(rejected, tt.wantRejected) {
+				t.Errorf("Preflight() got Rejected = %v, want %v", rejected, tt.wantRejected)
+			}
+			if !reflect.DeepEqual(unverified, tt.wantUnverified) {
+				t.Errorf("Preflight() got Unverified = %v, want %v", unverified, tt.wantUnverified)
+			}
+		})
+	}
+}
+
+// hostMXResolver also looks up the addresses of the hosts it holds, and fails on the others.
+type hostMXResolver struct {
+	fakeMXResolver
+	hosts map[string][]string
+}
+
+func (f hostMXResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	addrs, ok := f.hosts[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return addrs, nil
+}
+
+func TestPreflightWithHostFallback(t *testing.T) {
+	resolver := hostMXResolver{
+		fakeMXResolver: fakeMXResolver{"example.com": {{Host: "mx.example.com", Pref: 10}}, "empty.com": {}},
+		hosts:          map[string][]string{"a-only.com": {"192.0.2.1"}, "empty.com": {"2001:db8::1"}},
+	}
+	recipients := []string{"jane@a-only.com", "jane@empty.com", "jane@missing.com", "john@example.com"}
+	got := bemailparts.Preflight(recipients, bemailparts.WithMXCheck(resolver))
+
+	if want := []string{"jane@a-only.com", "jane@empty.com", "john@example.com"}; !reflect.DeepEqual(got.Accepted, want) {
+		t.Errorf("Preflight() got Accepted = %v, want %v", got.Accepted, want)
+	}
+	if len(got.Rejected) != 1 || got.Rejected[0].Email != "jane@missing.com" ||
+		!errors.Is(got.Rejected[0].Err, bemailparts.ErrDomainWithoutMX) {
+		t.Errorf("Preflight() got Rejected = %v, want jane@missing.com", got.Rejected)
+	}
+}
+
+func TestPreflightContext(t *testing.T) {
+	resolver := hostMXResolver{hosts: map[string][]string{"a-only.com": {"192.0.2.1"}}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		opts []bemailparts.PreflightOption
+	}{
+		{name: "default"},
+		{name: "accept", opts: []bemailparts.PreflightOption{bemailparts.WithMXUnavailable(bemailparts.MXUnavailableAccept)}},
+		{name: "dry run", opts: []bemailparts.PreflightOption{bemailparts.WithDryRun()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]bemailparts.PreflightOption{bemailparts.WithMXCheck(resolver)}, tt.opts...)
+			got := bemailparts.PreflightContext(ctx, []string{"jane@a-only.com", "john@a-only.com"}, opts...)
+
+			if len(got.Accepted) != 0 || len(got.Unverified) != 0 || len(got.Shadowed) != 0 {
+				t.Errorf("PreflightContext() got Accepted = %v, Unverified = %v, Shadowed = %v, want none",
+					got.Accepted, got.Unverified, got.Shadowed)
+			}
+			if len(got.Rejected) != 2 {
+				t.Fatalf("PreflightContext() got Rejected = %v, want both recipients", got.Rejected)
+			}
+			for _, r := range got.Rejected {
+				if !errors.Is(r.Err, context.Canceled) || errors.Is(r.Err, bemailparts.ErrDomainWithoutMX) {
+					t.Errorf("PreflightContext() got Rejected error %v for %v, want %v", r.Err, r.Email, context.Canceled)
+				}
+			}
+		})
+	}
+}
+
+func TestPreflightWithDryRunShadowsOnce(t *testing.T) {
+	list := bemailparts.NewSuppressionList()
+	if err := list.Add("bounced@missing.com", bemailparts.SuppressionBounce); err != nil {
+		t.Fatal(err)
+	}
+	got := bemailparts.Preflight([]string{"bounced@missing.com"},
+		bemailparts.WithSuppressionList(list),
+		bemailparts.WithMXCheck(fakeMXResolver{}),
+		bemailparts.WithDryRun(),
+	)
+
+	if want := []string{"bounced@missing.com"}; !reflect.DeepEqual(got.Accepted, want) {
+		t.Errorf("Preflight() got Accepted = %v, want %v", got.Accepted, want)
+	}
+	if len(got.Shadowed) != 1 || !errors.Is(got.Shadowed[0].Err, bemailparts.ErrEmailSuppressed) {
+		t.Errorf("Preflight() got Shadowed = %v, want bounced@missing.com once, suppressed", got.Shadowed)
+	}
+}
+
+func TestPreflightWithMXCache(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := bemailparts.NewMXCache(time.Hour)
+	cache.SetClock(bemailparts.ClockFunc(func() time.Time { return now }))
+
+	recipients := []string{"jane@null.com", "jane@other.com", "john@example.com"}
+	resolver := fakeMXResolver{
+		"example.com": {{Host: "mx.example.com", Pref: 10}},
+		"null.com":    {{Host: ".", Pref: 0}},
+	}
+	bemailparts.Preflight(recipients[:1], bemailparts.WithMXCheck(resolver), bemailparts.WithMXCache(cache))
+	bemailparts.Preflight(recipients[2:], bemailparts.WithMXCheck(resolver), bemailparts.WithMXCache(cache))
+	if cache.Len() != 2 {
+		t.Fatalf("MXCache.Len() got %v, want 2", cache.Len())
+	}
+
+	down := timeoutMXResolver{timeouts: map[string]bool{"example.com": true, "null.com": true, "other.com": true}}
+	tests := []struct {
+		name           string
+		elapsed        time.Duration
+		opts           []bemailparts.PreflightOption
+		wantAccepted   []string
+		wantRejected   []string
+		wantUnverified []string
+	}{
+		{
+			name:         "fresh",
+			elapsed:      time.Minute,
+			wantAccepted: []string{"john@example.com"},
+			wantRejected: []string{"jane@null.com", "jane@other.com"},
+		},
+		{
+			name:         "stale rejects by default",
+			elapsed:      2 * time.Hour,
+			wantRejected: []string{"jane@null.com", "jane@other.com", "john@example.com"},
+		},
+		{
+			name:           "stale used",
+			elapsed:        2 * time.Hour,
+			opts:           []bemailparts.PreflightOption{bemailparts.WithMXUnavailable(bemailparts.MXUnavailableUseStale)},
+			wantAccepted:   []string{"john@example.com"},
+			wantRejected:   []string{"jane@null.com", "jane@other.com"},
+			wantUnverified: []string{"john@example.com"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache.SetClock(bemailparts.ClockFunc(func() time.Time { return now.Add(tt.elapsed) }))
+			opts := append([]bemailparts.PreflightOption{bemailparts.WithMXCheck(down), bemailparts.WithMXCache(cache)}, tt.opts...)
+			got := bemailparts.Preflight(recipients, opts...)
+
+			if !reflect.DeepEqual(got.Accepted, tt.wantAccepted) {
+				t.Errorf("Preflight() got Accepted = %v, want %v", got.Accepted, tt.wantAccepted)
+			}
+			var rejected, unverified []string
+			for _, r := range got.Rejected {
+				rejected = append(rejected, r.Email)
+				if !errors.Is(r.Err, bemailparts.ErrDomainWithoutMX) {
+					t.Errorf("Preflight() got Rejected error %v for %v, want %v", r.Err, r.Email, bemailparts.ErrDomainWithoutMX)
+				}
+			}
+			for _, r := range got.Unverified {
+				unverified = append(unverified, r.Email)
+			}
+			if !reflect.DeepEqual(rejected, tt.wantRejected) {
+				t.Errorf("Preflight() got Rejected = %v, want %v", rejected, tt.wantRejected)
+			}
+			if !reflect.DeepEqual(unverified, tt.wantUnverified) {
+				t.Errorf("Preflight() got Unverified = %v, want %v", unverified, tt.wantUnverified)
+			}
+		})
+	}
+	if cache.Len() != 2 {
+		t.Errorf("MXCache.Len() got %v after failed lookups, want 2", cache.Len())
+	}
+}
@@ -0,0 +1,360 @@
+package bemailparts
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+)
+
+// ColumnProfile is a data-quality report for a column of email addresses, as returned by ProfileColumn.
+type ColumnProfile struct {
+	// Column is the zero-based index of the profiled column.
+	Column int
+
+	// Total is the number of data rows profiled.
+	Total int
+
+	// Valid is the number of rows holding a valid email address.
+	Valid int
+
+	// Duplicates is the number of valid rows repeating an address already seen (case-insensitive). It is a
+	// lower bound if Untracked is positive.
+	Duplicates int
+
+	// Untracked is the number of valid rows whose address was not remembered because the limit of
+	// WithMaxTracked was reached, so that later repeats of it are not counted in Duplicates.
+	Untracked int
+
+	// Disposable is the number of valid rows holding an address of a disposable mailbox service, as reported
+	// by IsDisposable.
+	Disposable int
+
+	// FreeProviders is the number of valid rows holding an address of a free mailbox provider, as reported by
+	// IsFreeProvider.
+	FreeProviders int
+
+	// Providers counts valid addresses per mailbox provider: the Provider of the rule of Rules covering the
+	// domain, e.g. "Gmail" for "gmail.com" and "googlemail.com", or else the lowercased registrable domain,
+	// e.g. "example.co.uk" for "mail.example.co.uk". Once it holds as many providers as the limit of
+	// WithMaxTracked, addresses of other providers are counted under the empty key, as in Domains.
+	Providers map[string]int
+
+	// Domains counts valid addresses per lowercased domain, e.g. "example.com". Once it holds as many
+	// domains as the limit of WithMaxTracked, addresses on other domains are counted under the empty key, as
+	// in TLDs.
+	Domains map[string]int
+
+	// TLDs counts valid addresses per lowercased TLD without a leading dot, e.g. "com" or "co.id".
+	TLDs map[string]int
+
+	// Errors counts invalid rows per error code, e.g. CodeInvalidEmailFormat.
+	Errors map[ErrorCode]int
+}
+
+// ValidityRate returns the fraction of rows holding a valid email address, between 0 and 1.
+func (p *ColumnProfile) ValidityRate() float64 {
+	return rate(p.Valid, p.Total)
+}
+
+// DuplicateRate returns the fraction of valid rows repeating an earlier address, between 0 and 1.
+func (p *ColumnProfile) DuplicateRate() float64 {
+	return rate(p.Duplicates, p.Valid)
+}
+
+// DisposableRate returns the fraction of valid rows holding a disposable address, between 0 and 1.
+func (p *ColumnProfile) DisposableRate() float64 {
+	return rate(p.Disposable, p.Valid)
+}
+
+// FreeProviderRate returns the fraction of valid rows holding an address of a free mailbox provider, between
+// 0 and 1.
+func (p *ColumnProfile) FreeProviderRate() float64 {
+	return rate(p.FreeProviders, p.Valid)
+}
+
+// ProfileOption configures ProfileColumn, ProfileEmailColumn and DetectEmailColumn.
+type ProfileOption func(*profileOptions)
+
+type profileOptions struct {
+	maxTracked int
+	parse      *options
+}
+
+// DefaultMaxTracked is the limit of WithMaxTracked used by default. Profiling takes up to a few hundred
+// megabytes at this limit, depending on the length of the addresses.
+const DefaultMaxTracked = 1 << 20
+
+// WithMaxTracked bounds the memory taken by ProfileColumn and ProfileEmailColumn, which read the CSV one
+// record at a time: at most n distinct addresses are remembered to count duplicates, and Providers, Domains
+// and TLDs each hold at most n keys besides the empty key. Past the limit, rows are counted in Untracked and
+// under the empty keys instead. Values of n below 1 are ignored.
+func WithMaxTracked(n int) ProfileOption {
+	return func(o *profileOptions) {
+		if n > 0 {
+			o.maxTracked = n
+		}
+	}
+}
+
+// WithParseOptions makes ProfileColumn, ProfileEmailColumn and DetectEmailColumn parse addresses with opts
+// instead of the default rules, e.g. to count internationalized addresses as valid with WithIDN and
+// WithUnicodeLocalPart, or to profile the addresses a signup form would accept with PolicyCorporateSignup.
+// Passing the option several times applies the options of the last call.
+//
+// Example:
+//
+//	f, _ := os.Open("users.csv")
+//	profile, err := ProfileEmailColumn(f, WithParseOptions(WithIDN(), WithUnicodeLocalPart()))
+//	if err != nil {
+//	    log.Fatalf("Failed to profile: %v", err)
+//	}
+//
+//	fmt.Println(profile.Valid) // Output: number of valid addresses, including internationalized ones
+func WithParseOptions(opts ...Option) ProfileOption {
+	parse := newOptions(opts)
+	return func(o *profileOptions) {
+		o.parse = parse
+	}
+}
+
+func newProfileOptions(opts []ProfileOption) *profileOptions {
+	o := &profileOptions{maxTracked: DefaultMaxTracked, parse: defaultOptions}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// ProfileColumn reads CSV from r and reports the quality of the email addresses in column col (zero-based).
+// The first record is treated as a header and skipped. Rows too short to have column col are counted as
+// invalid. Memory use is bounded as set by WithMaxTracked. Use ProfileEmailColumn to detect the column
+// instead.
+//
+// Example:
+//
+//	f, _ := os.Open("users.csv")
+//	profile, err := ProfileColumn(f, 1)
+//	if err != nil {
+//	    log.Fatalf("Failed to profile: %v", err)
+//	}
+//
+//	fmt.Printf("valid: %.2f, duplicates: %.2f\n", profile.ValidityRate(), profile.DuplicateRate())
+//	fmt.Printf("disposable: %.2f, free: %.2f\n", profile.DisposableRate(), profile.FreeProviderRate())
+//	fmt.Println(profile.Providers["Gmail"]) // Output: number of valid Gmail addresses
+//	fmt.Println(profile.TLDs["com"])        // Output: number of valid .com addresses
+func ProfileColumn(r io.Reader, col int, opts ...ProfileOption) (*ColumnProfile, error) {
+	if col < 0 {
+		return nil, ErrInvalidColumn
+	}
+
+	cr := newCSVReader(r)
+	if _, err := cr.Read(); err != nil {
+		if errors.Is(err, io.EOF) {
+			return newColumnProfile(col), nil
+		}
+		return nil, err
+	}
+	p := newColumnProfiler(col, newProfileOptions(opts))
+	return p.profile, p.addAll(cr)
+}
+
+// ProfileEmailColumn is like ProfileColumn, but detects the column holding the email addresses with
+// DetectEmailColumn on the first rows, so batch jobs need not be configured with a column index. The
+// detected column is reported in ColumnProfile.Column. Jobs that know the column can override detection by
+// calling ProfileColumn with its index.
+//
+// Returns ErrEmailColumnNotFound if no column holds mostly valid addresses, including when r holds no data
+// rows.
+//
+// Example:
+//
+//	f, _ := os.Open("export.csv")
+//	profile, err := ProfileEmailColumn(f)
+//	if err != nil {
+//	    log.Fatalf("Failed to profile: %v", err)
+//	}
+//
+//	fmt.Println(profile.Column) // Output: index of the email column, e.g. 2
+func ProfileEmailColumn(r io.Reader, opts ...ProfileOption) (*ColumnProfile, error) {
+	cr := newCSVReader(r)
+	if _, err := cr.Read(); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, ErrEmailColumnNotFound
+		}
+		return nil, err
+	}
+
+	var sample [][]string
+	for len(sample) < emailColumnSampleSize {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		sample = append(sample, record)
+	}
+	o := newProfileOptions(opts)
+	col, err := detectEmailColumn(sample, o)
+	if err != nil {
+		return nil, err
+	}
+
+	p := newColumnProfiler(col, o)
+	for _, record := range sample {
+		p.add(record)
+	}
+	return p.profile, p.addAll(cr)
+}
+
+// emailColumnSampleSize is the number of data rows ProfileEmailColumn detects the email column on.
+const emailColumnSampleSize = 100
+
+// DetectEmailColumn returns the index of the column of records, data rows without their header, holding the
+// most valid email addresses under the default rules, or those set by WithParseOptions. The column must hold a valid address in at least half
+// of the rows; on a tie, the first column wins.
+//
+// Returns ErrEmailColumnNotFound if no column qualifies or records is empty.
+//
+// Example:
+//
+//	col, _ := DetectEmailColumn([][]string{{"1", "John", "john@example.com"}, {"2", "Jane", "jane@example.com"}})
+//	fmt.Println(col) // Output: 2
+func DetectEmailColumn(records [][]string, opts ...ProfileOption) (int, error) {
+	return detectEmailColumn(records, newProfileOptions(opts))
+}
+
+func detectEmailColumn(records [][]string, o *profileOptions) (int, error) {
+	var valid []int
+	for _, record := range records {
+		for col, value := range record {
+			if col >= len(valid) {
+				valid = append(valid, make([]int, col+1-len(valid))...)
+			}
+			// Detection tries every column, so it records no audit.
+			if o.parse.check(strings.TrimSpace(value)) == nil {
+				valid[col]++
+			}
+		}
+	}
+
+	best := -1
+	for col, n := range valid {
+		if 2*n >= len(records) && n > 0 && (best < 0 || n > valid[best]) {
+			best = col
+		}
+	}
+	if best < 0 {
+		return 0, ErrEmailColumnNotFound
+	}
+	return best, nil
+}
+
+// columnProfiler builds the ColumnProfile of a column record by record.
+type columnProfiler struct {
+	profile    *ColumnProfile
+	seen       map[string]bool
+	maxTracked int
+	parse      *options
+}
+
+func newColumnProfiler(col int, o *profileOptions) *columnProfiler {
+	return &columnProfiler{profile: newColumnProfile(col), seen: map[string]bool{}, maxTracked: o.maxTracked,
+		parse: o.parse}
+}
+
+// addAll adds every remaining record of cr.
+func (p *columnProfiler) addAll(cr *csv.Reader) error {
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		p.add(record)
+	}
+}
+
+func (p *columnProfiler) add(record []string) {
+	profile := p.profile
+	profile.Total++
+	var value string
+	if profile.Column < len(record) {
+		value = strings.TrimSpace(record[profile.Column])
+	}
+
+	e, err := newEmailParts(value, p.parse)
+	if err != nil {
+		code, _ := ErrorCodeOf(err)
+		profile.Errors[code]++
+		return
+	}
+
+	profile.Valid++
+	key := strings.ToLower(e.Email())
+	switch {
+	case p.seen[key]:
+		profile.Duplicates++
+	case len(p.seen) < p.maxTracked:
+		p.seen[key] = true
+	default:
+		profile.Untracked++
+	}
+	if IsDisposable(e) {
+		profile.Disposable++
+	}
+	if IsFreeProvider(e) {
+		profile.FreeProviders++
+	}
+	p.count(profile.Providers, mailboxProvider(e))
+	p.count(profile.Domains, strings.ToLower(e.Domain()))
+	p.count(profile.TLDs, strings.ToLower(e.DomainTLDWithoutDot()))
+}
+
+// count increments counts[key], or counts[""] if counts already holds maxTracked other keys.
+func (p *columnProfiler) count(counts map[string]int, key string) {
+	if _, ok := counts[key]; !ok && len(counts) >= p.maxTracked {
+		key = ""
+	}
+	counts[key]++
+}
+
+// mailboxProvider returns the key of the provider of e in ColumnProfile.Providers.
+func mailboxProvider(e BEmailParts) string {
+	domain := strings.ToLower(e.Domain())
+	if rule, ok := providerRulesByDomain[domain]; ok {
+		return rule.Provider
+	}
+	if registrable := registrableDomain(domain); registrable != "" {
+		return registrable
+	}
+	return domain
+}
+
+// newCSVReader returns a csv.Reader on r accepting records of any length.
+func newCSVReader(r io.Reader) *csv.Reader {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	return cr
+}
+
+func newColumnProfile(col int) *ColumnProfile {
+	return &ColumnProfile{
+		Column:    col,
+		Providers: map[string]int{},
+		Domains:   map[string]int{},
+		TLDs:      map[string]int{},
+		Errors:    map[ErrorCode]int{},
+	}
+}
+
+func rate(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
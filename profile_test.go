@@ -0,0 +1,216 @@
+package bemailparts_test
+
+import (
+	"errors"
+	"github.com/bearaujus/bemailparts"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProfileColumn(t *testing.T) {
+	input := "id,email\n" +
+		"1,john.doe@example.com\n" +
+		"2,John.Doe@Example.com\n" +
+		"3,jane@example.co.id\n" +
+		"4,not-an-email\n" +
+		"5\n" +
+		"6,jane@googlemail.com\n" +
+		"7,bob@mailinator.com\n" +
+		"8,eve@mail.example.co.id\n"
+
+	got, err := bemailparts.ProfileColumn(strings.NewReader(input), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Total != 8 || got.Valid != 6 || got.Duplicates != 1 {
+		t.Errorf("ProfileColumn() got Total = %v, Valid = %v, Duplicates = %v, want 8, 6, 1",
+			got.Total, got.Valid, got.Duplicates)
+	}
+	if got.ValidityRate() != 0.75 {
+		t.Errorf("ValidityRate() got = %v, want %v", got.ValidityRate(), 0.75)
+	}
+	if got.Disposable != 1 || got.DisposableRate() != 1.0/6 {
+		t.Errorf("ProfileColumn() got Disposable = %v, DisposableRate() = %v, want 1, %v",
+			got.Disposable, got.DisposableRate(), 1.0/6)
+	}
+	if got.FreeProviders != 1 || got.FreeProviderRate() != 1.0/6 {
+		t.Errorf("ProfileColumn() got FreeProviders = %v, FreeProviderRate() = %v, want 1, %v",
+			got.FreeProviders, got.FreeProviderRate(), 1.0/6)
+	}
+	wantProviders := map[string]int{"example.com": 2, "example.co.id": 2, "Gmail": 1, "mailinator.com": 1}
+	if !reflect.DeepEqual(got.Providers, wantProviders) {
+		t.Errorf("ProfileColumn() got Providers = %v, want %v", got.Providers, wantProviders)
+	}
+	if got.Domains["example.com"] != 2 || got.Domains["example.co.id"] != 1 {
+		t.Errorf("ProfileColumn() got Domains = %v", got.Domains)
+	}
+	if got.TLDs["com"] != 4 || got.TLDs["co.id"] != 2 {
+		t.Errorf("ProfileColumn() got TLDs = %v", got.TLDs)
+	}
+	if got.Errors[bemailparts.CodeInvalidEmailFormat] != 2 {
+		t.Errorf("ProfileColumn() got Errors = %v", got.Errors)
+	}
+
+	if _, err = bemailparts.ProfileColumn(strings.NewReader(input), -1); err == nil {
+		t.Error("expecting an error on ProfileColumn() but got nil")
+	}
+
+	empty, err := bemailparts.ProfileColumn(strings.NewReader(""), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if empty.Total != 0 || empty.ValidityRate() != 0 || empty.DuplicateRate() != 0 {
+		t.Errorf("ProfileColumn() on empty input got = %+v", empty)
+	}
+}
+
+func TestProfileColumnWithMaxTracked(t *testing.T) {
+	input := "id,email\n" +
+		"1,john@a.com\n" +
+		"2,jane@b.com\n" +
+		"3,bob@c.com\n" +
+		"4,john@a.com\n" +
+		"5,bob@c.com\n"
+
+	got, err := bemailparts.ProfileColumn(strings.NewReader(input), 1, bemailparts.WithMaxTracked(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Valid != 5 || got.Duplicates != 1 || got.Untracked != 2 {
+		t.Errorf("ProfileColumn() got Valid = %v, Duplicates = %v, Untracked = %v, want 5, 1, 2",
+			got.Valid, got.Duplicates, got.Untracked)
+	}
+	if want := map[string]int{"a.com": 2, "b.com": 1, "": 2}; !reflect.DeepEqual(got.Domains, want) {
+		t.Errorf("ProfileColumn() got Domains = %v, want %v", got.Domains, want)
+	}
+	if want := map[string]int{"com": 5}; !reflect.DeepEqual(got.TLDs, want) {
+		t.Errorf("ProfileColumn() got TLDs = %v, want %v", got.TLDs, want)
+	}
+}
+
+func TestProfileColumnWithParseOptions(t *testing.T) {
+	input := "id,email\n" +
+		"1,用户@例子.广告\n" +
+		"2,jürgen@bücher.de\n" +
+		"3,john@gmail.com\n" +
+		"4,jane@freemail.co.id\n"
+	opts := bemailparts.WithParseOptions(bemailparts.WithIDN(), bemailparts.WithUnicodeLocalPart(),
+		bemailparts.WithFreeProviders([]string{"freemail.*"}, nil))
+
+	got, err := bemailparts.ProfileColumn(strings.NewReader(input), 1, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Valid != 4 || got.FreeProviders != 2 {
+		t.Errorf("ProfileColumn() got Valid = %v, FreeProviders = %v, want 4, 2", got.Valid, got.FreeProviders)
+	}
+
+	got, err = bemailparts.ProfileColumn(strings.NewReader(input), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Valid != 2 || got.FreeProviders != 1 {
+		t.Errorf("ProfileColumn() without options got Valid = %v, FreeProviders = %v, want 2, 1", got.Valid,
+			got.FreeProviders)
+	}
+
+	input = "id,name,email\n" +
+		"1,John,用户@例子.广告\n" +
+		"2,Jane,jürgen@bücher.de\n"
+	got, err = bemailparts.ProfileEmailColumn(strings.NewReader(input), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Column != 2 || got.Valid != 2 {
+		t.Errorf("ProfileEmailColumn() got Column = %v, Valid = %v, want 2, 2", got.Column, got.Valid)
+	}
+}
+
+func TestProfileEmailColumn(t *testing.T) {
+	input := "id,name,contact,notes\n" +
+		"1,John,john.doe@example.com,see alice@example.com\n" +
+		"2,Jane,jane@example.co.id,\n" +
+		"3,Bob,not-an-email,\n" +
+		"4,Eve,eve@example.org\n"
+
+	got, err := bemailparts.ProfileEmailColumn(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Column != 2 || got.Total != 4 || got.Valid != 3 {
+		t.Errorf("ProfileEmailColumn() got Column = %v, Total = %v, Valid = %v, want 2, 4, 3",
+			got.Column, got.Total, got.Valid)
+	}
+
+	if _, err = bemailparts.ProfileEmailColumn(strings.NewReader("id,name\n1,John\n2,Jane\n")); !errors.Is(err, bemailparts.ErrEmailColumnNotFound) {
+		t.Errorf("ProfileEmailColumn() error = %v, want %v", err, bemailparts.ErrEmailColumnNotFound)
+	}
+	if _, err = bemailparts.ProfileEmailColumn(strings.NewReader("id,email\n")); !errors.Is(err, bemailparts.ErrEmailColumnNotFound) {
+		t.Errorf("ProfileEmailColumn() error = %v, want %v", err, bemailparts.ErrEmailColumnNotFound)
+	}
+}
+
+func TestDetectEmailColumn(t *testing.T) {
+	tests := []struct {
+		name    string
+		records [][]string
+		opts    []bemailparts.ProfileOption
+		want    int
+		wantErr error
+	}{
+		{
+			name:    "email column",
+			records: [][]string{{"1", "John", "john@example.com"}, {"2", "Jane", " jane@example.com "}},
+			want:    2,
+		},
+		{
+			name:    "most valid column wins",
+			records: [][]string{{"a@example.com", "b@example.com"}, {"x", "c@example.com"}},
+			want:    1,
+		},
+		{
+			name:    "first column wins a tie",
+			records: [][]string{{"a@example.com", "b@example.com"}},
+			want:    0,
+		},
+		{
+			name:    "half valid",
+			records: [][]string{{"1", "john@example.com"}, {"2", "unknown"}},
+			want:    1,
+		},
+		{
+			name:    "mostly invalid",
+			records: [][]string{{"john@example.com"}, {"unknown"}, {"n/a"}},
+			wantErr: bemailparts.ErrEmailColumnNotFound,
+		},
+		{
+			name:    "internationalized addresses",
+			records: [][]string{{"1", "用户@例子.广告"}, {"2", "jürgen@bücher.de"}},
+			wantErr: bemailparts.ErrEmailColumnNotFound,
+		},
+		{
+			name:    "internationalized addresses with parse options",
+			records: [][]string{{"1", "用户@例子.广告"}, {"2", "jürgen@bücher.de"}},
+			opts: []bemailparts.ProfileOption{
+				bemailparts.WithParseOptions(bemailparts.WithIDN(), bemailparts.WithUnicodeLocalPart()),
+			},
+			want: 1,
+		},
+		{
+			name:    "no records",
+			wantErr: bemailparts.ErrEmailColumnNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bemailparts.DetectEmailColumn(tt.records, tt.opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DetectEmailColumn() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("DetectEmailColumn() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package bemailparts
+
+import "strings"
+
+// ProviderRule describes how a mailbox provider maps addresses to mailboxes, so that addresses delivered
+// to the same mailbox can be recognized.
+type ProviderRule struct {
+	// Provider is the name of the provider, e.g. "Gmail".
+	Provider string
+
+	// Domain is the canonical domain of the provider's addresses.
+	Domain string
+
+	// AliasDomains deliver to the same mailboxes as Domain.
+	AliasDomains []string
+
+	// Separators are the characters that start a tag, which the provider ignores together with the rest of
+	// the username, e.g. "+" for "john+news".
+	Separators string
+
+	// DotInsensitive reports whether the provider ignores dots in usernames.
+	DotInsensitive bool
+}
+
+// defaultProviderRule applies to domains without a ProviderRule.
+var defaultProviderRule = ProviderRule{Separators: "+"}
+
+var providerRules = []ProviderRule{
+	{Provider: "Gmail", Domain: "gmail.com", AliasDomains: []string{"googlemail.com"}, Separators: "+", DotInsensitive: true},
+	{Provider: "Outlook", Domain: "outlook.com", Separators: "+"},
+	{Provider: "Hotmail", Domain: "hotmail.com", Separators: "+"},
+	{Provider: "iCloud", Domain: "icloud.com", AliasDomains: []string{"me.com", "mac.com"}, Separators: "+"},
+	{Provider: "Yahoo", Domain: "yahoo.com", Separators: "-"},
+	{Provider: "Fastmail", Domain: "fastmail.com", Separators: "+"},
+}
+
+// providerRulesByDomain indexes providerRules by canonical and alias domains.
+var providerRulesByDomain = func() map[string]*ProviderRule {
+	byDomain := map[string]*ProviderRule{}
+	for i := range providerRules {
+		rule := &providerRules[i]
+		byDomain[rule.Domain] = rule
+		for _, alias := range rule.AliasDomains {
+			byDomain[alias] = rule
+		}
+	}
+	return byDomain
+}()
+
+// Rules returns the provider rules Canonical and MergeCandidates apply, so that systems written in other
+// languages can export and mirror them. Domains without a rule have their "+" tags removed and nothing else.
+// The result is a copy; modifying it does not affect canonicalization. CanonicalVersion is incremented
+// whenever the rules change the result of Canonical.
+//
+// Example:
+//
+//	for _, rule := range Rules() {
+//	    fmt.Println(rule.Provider, rule.Domain, rule.AliasDomains, rule.Separators, rule.DotInsensitive)
+//	}
+//	// Output: Gmail gmail.com [googlemail.com] + true
+//	// ...
+func Rules() []ProviderRule {
+	rules := make([]ProviderRule, len(providerRules))
+	for i, rule := range providerRules {
+		rule.AliasDomains = append([]string(nil), rule.AliasDomains...)
+		rules[i] = rule
+	}
+	return rules
+}
+
+// Canonical returns the address of the mailbox email is delivered to, according to the rule of its
+// provider: lowercased, with alias domains replaced by the canonical domain, tags removed, and dots removed
+// for dot-insensitive providers.
+// Returns an error if email is invalid.
+//
+// Example:
+//
+//	canonical, err := Canonical("John.Doe+news@googlemail.com")
+//	if err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	fmt.Println(canonical) // Output: johndoe@gmail.com
+func Canonical(email string) (string, error) {
+	e, err := parseEmailParts(email, canonicalOptions)
+	if err != nil {
+		return "", err
+	}
+	username, domain := canonicalParts(e.username, e.domain)
+	return generateEmail(username, domain), nil
+}
+
+// canonicalOptions parse the addresses passed to Canonical, shared to save an allocation per call.
+var canonicalOptions = newOptions([]Option{WithLowercase()})
+
+// canonicalParts applies the provider rule of domain to the lowercased username and domain.
+func canonicalParts(username, domain string) (string, string) {
+	rule := &defaultProviderRule
+	if r, ok := providerRulesByDomain[domain]; ok {
+		rule = r
+		domain = r.Domain
+	}
+	if i := strings.IndexAny(username, rule.Separators); i > 0 {
+		username = username[:i]
+	}
+	if rule.DotInsensitive {
+		username = strings.ReplaceAll(username, domainSeparator, "")
+	}
+	return username, domain
+}
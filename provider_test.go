@@ -0,0 +1,55 @@
+package bemailparts_test
+
+import (
+	"github.com/bearaujus/bemailparts"
+	"testing"
+)
+
+func TestCanonical(t *testing.T) {
+	tests := []struct {
+		email   string
+		want    string
+		wantErr bool
+	}{
+		{email: "John.Doe+news@googlemail.com", want: "johndoe@gmail.com"},
+		{email: "j.o.h.n@gmail.com", want: "john@gmail.com"},
+		{email: "john.doe+news@outlook.com", want: "john.doe@outlook.com"},
+		{email: "john-news@yahoo.com", want: "john@yahoo.com"},
+		{email: "john+news@yahoo.com", want: "john+news@yahoo.com"},
+		{email: "john+news@me.com", want: "john@icloud.com"},
+		{email: "john.doe+news@example.com", want: "john.doe@example.com"},
+		{email: "+news@example.com", want: "+news@example.com"},
+		{email: "invalid", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			got, err := bemailparts.Canonical(tt.email)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Canonical() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Canonical() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRules(t *testing.T) {
+	rules := bemailparts.Rules()
+	if len(rules) == 0 || rules[0].Provider != "Gmail" || !rules[0].DotInsensitive || rules[0].AliasDomains[0] != "googlemail.com" {
+		t.Fatalf("Rules() got = %+v", rules)
+	}
+
+	rules[0].AliasDomains[0] = "example.com"
+	if got, _ := bemailparts.Canonical("john@googlemail.com"); got != "john@gmail.com" {
+		t.Errorf("Canonical() got = %v after modifying Rules(), want john@gmail.com", got)
+	}
+
+	for _, rule := range rules {
+		for _, domain := range append([]string{rule.Domain}, rule.AliasDomains...) {
+			if _, err := bemailparts.Canonical("john@" + domain); err != nil {
+				t.Errorf("Canonical() error = %v for domain %v", err, domain)
+			}
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package bemailparts
+
+import (
+	_ "embed"
+	"strings"
+	"sync"
+)
+
+// publicSuffixList is the ICANN section of the Public Suffix List (https://publicsuffix.org), which names
+// the suffixes under which domains are registered, such as "com", "co.uk" or "*.kawasaki.jp".
+//
+//go:embed public_suffix_list.dat
+var publicSuffixList string
+
+// publicSuffixRules holds the rules of publicSuffixList by kind, keyed by suffix without "*." or "!".
+// Internationalized rules are held in both their Unicode and ACE forms.
+type publicSuffixRules struct {
+	exact     map[string]bool
+	wildcard  map[string]bool
+	exception map[string]bool
+}
+
+var (
+	publicSuffixOnce  sync.Once
+	publicSuffixTable publicSuffixRules
+)
+
+// loadPublicSuffixRules parses publicSuffixList on first use, so programs that never split domains do not
+// pay for it.
+func loadPublicSuffixRules() *publicSuffixRules {
+	publicSuffixOnce.Do(func() {
+		r := publicSuffixRules{exact: map[string]bool{}, wildcard: map[string]bool{}, exception: map[string]bool{}}
+		for _, line := range strings.Split(publicSuffixList, "\n") {
+			rule := strings.TrimSpace(line)
+			if rule == "" || strings.HasPrefix(rule, "//") {
+				continue
+			}
+			rules := r.exact
+			switch {
+			case strings.HasPrefix(rule, "*."):
+				rule, rules = rule[len("*."):], r.wildcard
+			case strings.HasPrefix(rule, "!"):
+				rule, rules = rule[len("!"):], r.exception
+			}
+			rules[rule] = true
+			if ascii, err := ToASCII(rule); !isASCII(rule) && err == nil {
+				rules[ascii] = true
+			}
+		}
+		publicSuffixTable = r
+	})
+	return &publicSuffixTable
+}
+
+// publicSuffixLabels returns the number of labels of the public suffix of domain, which is not an address
+// literal, following the algorithm of the Public Suffix List: the longest matching rule wins, exceptions
+// win over wildcards, and a domain no rule matches has its last label as public suffix.
+func publicSuffixLabels(domain string) int {
+	rules := loadPublicSuffixRules()
+	suffix := strings.ToLower(domain)
+	labels := strings.Count(suffix, domainSeparator) + 1
+	for ; labels > 1; labels-- {
+		parent := suffix[strings.IndexByte(suffix, domainSeparator[0])+1:]
+		switch {
+		case rules.exception[suffix]:
+			return labels - 1
+		case rules.exact[suffix], rules.wildcard[parent]:
+			return labels
+		}
+		suffix = parent
+	}
+	return 1
+}
+
+// splitPublicSuffix splits domain, which has a dot and is not an address literal, before its public suffix
+// into the domain name and the TLD (with its leading dot). A domain that is itself a public suffix, such as
+// "co.uk", is split at its first dot.
+func splitPublicSuffix(domain string) (string, string) {
+	i := len(domain)
+	for labels := publicSuffixLabels(domain); labels > 0; labels-- {
+		i = strings.LastIndex(domain[:i], domainSeparator)
+		if i < 0 {
+			i = strings.Index(domain, domainSeparator)
+			break
+		}
+	}
+	return domain[:i], domain[i:]
+}
+
+// RegistrableDomain returns the registrable domain of e, the public suffix with the label before it
+// (eTLD+1), which identifies the organization owning the domain, e.g. for deduplication, analytics and DMARC
+// alignment. Returns an empty string for a domain that is itself a public suffix, such as "co.uk", a
+// single-label domain or a domain literal such as "[192.0.2.1]".
+//
+// Example:
+//
+//	e, err := New("john.doe@mail.example.co.uk")
+//	if err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	fmt.Println(RegistrableDomain(e)) // Output: example.co.uk
+func RegistrableDomain(e BEmailParts) string {
+	return registrableDomain(e.Domain())
+}
+
+// WithRequireRegistrableDomain rejects addresses whose domain is itself a public suffix according to the
+// embedded Public Suffix List, with ErrNoRegistrableDomain, e.g. "john@co.uk": no organization owns such a
+// domain. Address literals and single-label domains are exempt. It also applies to the setters.
+//
+// Example:
+//
+//	_, err := New("john@co.uk", WithRequireRegistrableDomain())
+//	fmt.Println(err)                                    // Output: domain is a public suffix at position 5 in domain
+//	fmt.Println(errors.Is(err, ErrNoRegistrableDomain)) // Output: true
+func WithRequireRegistrableDomain() Option {
+	return func(o *options) {
+		o.requireRegistrable = true
+	}
+}
+
+// registrableDomain returns the public suffix of domain with the label before it, or an empty string if
+// domain is an address literal or has no label before its public suffix.
+func registrableDomain(domain string) string {
+	if isAddressLiteral(domain) {
+		return ""
+	}
+	i := len(domain)
+	for labels := publicSuffixLabels(domain) + 1; labels > 0; labels-- {
+		if i = strings.LastIndex(domain[:i], domainSeparator); i < 0 {
+			if labels == 1 {
+				return domain
+			}
+			return ""
+		}
+	}
+	return domain[i+1:]
+}
+
+func (e *bEmailParts) Subdomain() string {
+	registrable := registrableDomain(e.domain)
+	if registrable == "" || len(registrable) == len(e.domain) {
+		return ""
+	}
+	return e.domain[:len(e.domain)-len(registrable)-len(domainSeparator)]
+}
+
+func (e *bEmailParts) SetSubdomain(subdomain string) error {
+	registrable := registrableDomain(e.domain)
+	if registrable == "" {
+		return newEmailError(FieldSubdomain, subdomain, ErrNoRegistrableDomain)
+	}
+	if subdomain == "" {
+		return e.setDomain(FieldSubdomain, registrable)
+	}
+	if err := e.opts.validateDomainName(subdomain); err != nil {
+		e.opts.auditParts(e.username, generateDomain(subdomain, registrable), err)
+		return newEmailError(FieldSubdomain, subdomain, err)
+	}
+	return e.setDomain(FieldSubdomain, generateDomain(subdomain, registrable))
+}
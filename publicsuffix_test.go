@@ -0,0 +1,243 @@
+package bemailparts_test
+
+import (
+	"errors"
+	"github.com/bearaujus/bemailparts"
+	"testing"
+)
+
+func TestPublicSuffixSplit(t *testing.T) {
+	tests := []struct {
+		name           string
+		email          string
+		opts           []bemailparts.Option
+		wantDomainName string
+		wantDomainTLD  string
+	}{
+		{name: "single label suffix", email: "john@example.com", wantDomainName: "example", wantDomainTLD: ".com"},
+		{name: "subdomain", email: "john@mail.example.com", wantDomainName: "mail.example", wantDomainTLD: ".com"},
+		{name: "multi-label suffix", email: "john@example.co.id", wantDomainName: "example", wantDomainTLD: ".co.id"},
+		{name: "subdomain of multi-label suffix", email: "john@mail.example.co.uk", wantDomainName: "mail.example", wantDomainTLD: ".co.uk"},
+		{name: "case is kept", email: "john@Mail.Example.CO.UK", wantDomainName: "Mail.Example", wantDomainTLD: ".CO.UK"},
+		{name: "wildcard rule", email: "john@www.example.foo.bd", wantDomainName: "www.example", wantDomainTLD: ".foo.bd"},
+		{name: "exception rule", email: "john@mail.www.ck", wantDomainName: "mail.www", wantDomainTLD: ".ck"},
+		{name: "unknown tld", email: "john@mail.example.zzz", wantDomainName: "mail.example", wantDomainTLD: ".zzz"},
+		{name: "domain is a public suffix", email: "john@co.uk", wantDomainName: "co", wantDomainTLD: ".uk"},
+		{name: "domain matches a wildcard", email: "john@foo.bd", wantDomainName: "foo", wantDomainTLD: ".bd"},
+		{
+			name:           "internationalized suffix",
+			email:          "ivan@пример.рф",
+			opts:           []bemailparts.Option{bemailparts.WithIDN()},
+			wantDomainName: "пример",
+			wantDomainTLD:  ".рф",
+		},
+		{
+			name:           "ace form of internationalized suffix",
+			email:          "li@example.xn--55qx5d.cn",
+			opts:           []bemailparts.Option{bemailparts.WithIDN()},
+			wantDomainName: "example",
+			wantDomainTLD:  ".xn--55qx5d.cn",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := bemailparts.New(tt.email, tt.opts...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			if e.DomainName() != tt.wantDomainName || e.DomainTLD() != tt.wantDomainTLD {
+				t.Errorf("DomainName(), DomainTLD() got = %v, %v, want %v, %v",
+					e.DomainName(), e.DomainTLD(), tt.wantDomainName, tt.wantDomainTLD)
+			}
+			if err = bemailparts.CheckInvariants(e, tt.opts...); err != nil {
+				t.Errorf("CheckInvariants() error = %v", err)
+			}
+		})
+	}
+
+	t.Run("test setters use public suffix boundaries", func(t *testing.T) {
+		e, err := bemailparts.New("john@mail.example.co.uk")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = e.SetDomainTLD("com"); err != nil {
+			t.Fatal(err)
+		}
+		if e.Email() != "john@mail.example.com" {
+			t.Errorf("SetDomainTLD() got = %v, want %v", e.Email(), "john@mail.example.com")
+		}
+		if err = e.SetDomainName("example"); err != nil {
+			t.Fatal(err)
+		}
+		if e.Email() != "john@example.com" {
+			t.Errorf("SetDomainName() got = %v, want %v", e.Email(), "john@example.com")
+		}
+	})
+}
+
+func TestRegistrableDomain(t *testing.T) {
+	tests := []struct {
+		name  string
+		email string
+		opts  []bemailparts.Option
+		want  string
+	}{
+		{name: "registrable domain", email: "john@example.com", want: "example.com"},
+		{name: "subdomain", email: "john@mail.eu.example.com", want: "example.com"},
+		{name: "multi-label suffix", email: "john@mail.example.co.uk", want: "example.co.uk"},
+		{name: "case is kept", email: "john@Mail.Example.CO.UK", want: "Example.CO.UK"},
+		{name: "wildcard rule", email: "john@www.example.foo.bd", want: "example.foo.bd"},
+		{name: "exception rule", email: "john@mail.www.ck", want: "www.ck"},
+		{name: "domain is a public suffix", email: "john@co.uk", want: ""},
+		{
+			name:  "single label domain",
+			email: "john@localhost",
+			opts:  []bemailparts.Option{bemailparts.WithAllowSingleLabelDomain()},
+			want:  "",
+		},
+		{
+			name:  "address literal",
+			email: "john@[192.0.2.1]",
+			opts:  []bemailparts.Option{bemailparts.WithAllowIPDomain()},
+			want:  "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := bemailparts.New(tt.email, tt.opts...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			if got := bemailparts.RegistrableDomain(e); got != tt.want {
+				t.Errorf("RegistrableDomain() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithRequireRegistrableDomain(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		opts    []bemailparts.Option
+		wantErr error
+	}{
+		{name: "registrable domain", email: "john@example.co.uk"},
+		{name: "domain is a public suffix", email: "john@co.uk", wantErr: bemailparts.ErrNoRegistrableDomain},
+		{name: "wildcard rule", email: "john@foo.bd", wantErr: bemailparts.ErrNoRegistrableDomain},
+		{
+			name:  "single label domain",
+			email: "john@localhost",
+			opts:  []bemailparts.Option{bemailparts.WithAllowSingleLabelDomain()},
+		},
+		{
+			name:  "address literal",
+			email: "john@[192.0.2.1]",
+			opts:  []bemailparts.Option{bemailparts.WithAllowIPDomain()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]bemailparts.Option{bemailparts.WithRequireRegistrableDomain()}, tt.opts...)
+			_, err := bemailparts.New(tt.email, opts...)
+			if !errors.Is(err, tt.wantErr) || (err != nil) != (tt.wantErr != nil) {
+				t.Errorf("New() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("test error explains the rejection", func(t *testing.T) {
+		_, err := bemailparts.New("john@co.uk", bemailparts.WithRequireRegistrableDomain())
+		want := "domain is a public suffix at position 5 in domain"
+		if err == nil || err.Error() != want {
+			t.Errorf("New() error = %v, want %v", err, want)
+		}
+	})
+
+	t.Run("test setters require a registrable domain", func(t *testing.T) {
+		e, err := bemailparts.New("john@example.co.uk", bemailparts.WithRequireRegistrableDomain())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = e.SetDomain("co.uk"); !errors.Is(err, bemailparts.ErrNoRegistrableDomain) {
+			t.Errorf("SetDomain() error = %v, want %v", err, bemailparts.ErrNoRegistrableDomain)
+		}
+	})
+}
+
+func TestSubdomain(t *testing.T) {
+	tests := []struct {
+		name  string
+		email string
+		opts  []bemailparts.Option
+		want  string
+	}{
+		{name: "subdomain", email: "john@mail.example.com", want: "mail"},
+		{name: "nested subdomain", email: "john@eu.mail.example.co.uk", want: "eu.mail"},
+		{name: "no subdomain", email: "john@example.co.uk", want: ""},
+		{name: "domain is a public suffix", email: "john@co.uk", want: ""},
+		{
+			name:  "address literal",
+			email: "john@[192.0.2.1]",
+			opts:  []bemailparts.Option{bemailparts.WithAllowIPDomain()},
+			want:  "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := bemailparts.New(tt.email, tt.opts...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			if got := e.Subdomain(); got != tt.want {
+				t.Errorf("Subdomain() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetSubdomain(t *testing.T) {
+	tests := []struct {
+		name      string
+		email     string
+		subdomain string
+		want      string
+		wantErr   error
+	}{
+		{name: "replace", email: "john@mail.example.com", subdomain: "eu", want: "john@eu.example.com"},
+		{name: "add", email: "john@example.co.uk", subdomain: "eu.mail", want: "john@eu.mail.example.co.uk"},
+		{name: "remove", email: "john@eu.mail.example.com", subdomain: "", want: "john@example.com"},
+		{
+			name:      "invalid subdomain",
+			email:     "john@mail.example.com",
+			subdomain: "e u",
+			want:      "john@mail.example.com",
+			wantErr:   bemailparts.ErrInvalidEmailDomainNameFormat,
+		},
+		{
+			name:      "no registrable domain",
+			email:     "john@co.uk",
+			subdomain: "mail",
+			want:      "john@co.uk",
+			wantErr:   bemailparts.ErrNoRegistrableDomain,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := bemailparts.New(tt.email, bemailparts.WithHistory())
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			err = e.SetSubdomain(tt.subdomain)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("SetSubdomain() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if e.Email() != tt.want {
+				t.Errorf("SetSubdomain() got = %v, want %v", e.Email(), tt.want)
+			}
+			if history := bemailparts.History(e); tt.wantErr == nil && history[len(history)-1].Field != bemailparts.FieldSubdomain {
+				t.Errorf("History() got = %+v, want a FieldSubdomain mutation", history)
+			}
+		})
+	}
+}
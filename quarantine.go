@@ -0,0 +1,139 @@
+package bemailparts
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// quarantineKeyPrefix prefixes the Store keys of a Quarantine.
+const quarantineKeyPrefix = "quarantine/"
+
+// Quarantine holds addresses that failed verification with a transient error (e.g., a greylisting or
+// timeout response) until they become eligible to be checked again. It is meant to be kept separate from
+// any permanent suppression list: quarantined addresses are expected to come back.
+//
+// A Quarantine is safe for concurrent use.
+type Quarantine struct {
+	mu     sync.Mutex
+	period time.Duration
+	clock  Clock
+	store  Store
+}
+
+// NewQuarantine creates an empty Quarantine kept in memory, whose addresses become eligible for re-checking
+// once period has elapsed since they were added.
+//
+// Example:
+//
+//	q := NewQuarantine(24 * time.Hour)
+//	if err := q.Add("john.doe@example.com"); err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	fmt.Println(q.IsQuarantined("john.doe@example.com")) // Output: true
+func NewQuarantine(period time.Duration) *Quarantine {
+	return NewQuarantineWithStore(period, NewMemoryStore())
+}
+
+// NewQuarantineWithStore creates a Quarantine kept in store, holding the addresses already quarantined
+// there. If store fails, IsQuarantined reports addresses as quarantined and Eligible returns the addresses
+// read so far, so that addresses are not re-checked early.
+//
+// Example:
+//
+//	q := NewQuarantineWithStore(24*time.Hour, store)
+//	fmt.Println(q.Eligible()) // Output: the addresses in store due for a re-check
+func NewQuarantineWithStore(period time.Duration, store Store) *Quarantine {
+	return &Quarantine{
+		period: period,
+		clock:  systemClock,
+		store:  store,
+	}
+}
+
+// SetClock makes the Quarantine read the current time from clock, e.g. to simulate the expiry of its period
+// in tests. Passing nil restores the system clock.
+func (q *Quarantine) SetClock(clock Clock) {
+	if clock == nil {
+		clock = systemClock
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.clock = clock
+}
+
+// Add quarantines email for the configured period. Adding an address that is already quarantined restarts
+// its period. Returns an error if email is invalid or the Store fails.
+func (q *Quarantine) Add(email string) error {
+	e, err := New(email)
+	if err != nil {
+		return err
+	}
+	until := q.now().Add(q.period).UnixNano()
+	return q.store.Put(quarantineKey(e.Email()), []byte(strconv.FormatInt(until, 10)+"\n"+e.Email()), 0)
+}
+
+// Remove releases email from the quarantine, typically after it was re-checked successfully.
+// Returns an error if the Store fails.
+func (q *Quarantine) Remove(email string) error {
+	return q.store.Delete(quarantineKey(email))
+}
+
+// IsQuarantined reports whether email is quarantined and its period has not elapsed yet.
+func (q *Quarantine) IsQuarantined(email string) bool {
+	value, ok, err := q.store.Get(quarantineKey(email))
+	if err != nil {
+		return true
+	}
+	if !ok {
+		return false
+	}
+	until, _ := parseQuarantineValue(value)
+	return q.now().Before(until)
+}
+
+// Eligible returns the quarantined addresses whose period has elapsed and which are due for a re-check,
+// sorted alphabetically. They stay in the quarantine until removed or added again.
+func (q *Quarantine) Eligible() []string {
+	now := q.now()
+	var eligible []string
+	_ = q.store.Scan(quarantineKeyPrefix, func(_ string, value []byte) bool {
+		if until, email := parseQuarantineValue(value); !now.Before(until) {
+			eligible = append(eligible, email)
+		}
+		return true
+	})
+	sort.Strings(eligible)
+	return eligible
+}
+
+// Len returns the number of addresses in the quarantine, eligible or not.
+func (q *Quarantine) Len() int {
+	var n int
+	_ = q.store.Scan(quarantineKeyPrefix, func(string, []byte) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+func (q *Quarantine) now() time.Time {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.clock.Now()
+}
+
+func quarantineKey(email string) string {
+	return quarantineKeyPrefix + strings.ToLower(email)
+}
+
+// parseQuarantineValue returns the end of the period and the address stored by Add.
+func parseQuarantineValue(value []byte) (time.Time, string) {
+	until, email := splitStoreValue(value)
+	nanos, _ := strconv.ParseInt(until, 10, 64)
+	return time.Unix(0, nanos), email
+}
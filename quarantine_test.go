@@ -0,0 +1,53 @@
+package bemailparts_test
+
+import (
+	"github.com/bearaujus/bemailparts"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestQuarantine(t *testing.T) {
+	t.Run("test quarantine period not elapsed", func(t *testing.T) {
+		q := bemailparts.NewQuarantine(time.Hour)
+		if err := q.Add("john.doe@example.com"); err != nil {
+			t.Fatal(err)
+		}
+		if !q.IsQuarantined("John.Doe@Example.com") {
+			t.Error("IsQuarantined() got = false, want true")
+		}
+		if got := q.Eligible(); len(got) != 0 {
+			t.Errorf("Eligible() got = %v, want none", got)
+		}
+
+		q.Remove("john.doe@example.com")
+		if q.IsQuarantined("john.doe@example.com") || q.Len() != 0 {
+			t.Error("Remove() did not release the address")
+		}
+	})
+
+	t.Run("test quarantine period elapsed", func(t *testing.T) {
+		q := bemailparts.NewQuarantine(0)
+		for _, email := range []string{"b@example.com", "a@example.com"} {
+			if err := q.Add(email); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if q.IsQuarantined("a@example.com") {
+			t.Error("IsQuarantined() got = true, want false")
+		}
+		if got, want := q.Eligible(), []string{"a@example.com", "b@example.com"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("Eligible() got = %v, want %v", got, want)
+		}
+		if q.Len() != 2 {
+			t.Errorf("Len() got = %v, want %v", q.Len(), 2)
+		}
+	})
+
+	t.Run("test quarantine invalid email", func(t *testing.T) {
+		q := bemailparts.NewQuarantine(time.Hour)
+		if err := q.Add("!@#!@#"); err == nil {
+			t.Error("expecting an error on Add() but got nil")
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package bemailparts
+
+// CanonicalVersion identifies the rules Canonical applies. It is incremented whenever a change to Rules makes
+// Canonical return a different address for some input, so that systems storing canonical addresses as
+// deduplication keys know when to upgrade them with RecanonicalizeKey or MigrateCanonicalKeys.
+const CanonicalVersion = 1
+
+// RecanonicalizeKey returns the key Canonical computes for address under the current rules, to replace
+// oldKey, a key computed from address by an earlier version, and whether it differs from oldKey. Keys are
+// recomputed from the address rather than converted, since earlier rules may have dropped parts of it that
+// the current rules need. Hashes of keys must be recomputed from the returned key.
+// Returns an error if address is invalid.
+//
+// Example:
+//
+//	key, changed, err := RecanonicalizeKey("john.doe@googlemail.com", "John.Doe+news@googlemail.com")
+//	if err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	fmt.Println(key, changed) // Output: johndoe@gmail.com true
+func RecanonicalizeKey(oldKey, address string) (string, bool, error) {
+	key, err := Canonical(address)
+	if err != nil {
+		return "", false, err
+	}
+	return key, key != oldKey, nil
+}
+
+// CanonicalKey is a deduplication key stored with the address it was computed from by Canonical.
+type CanonicalKey struct {
+	Key     string
+	Address string
+}
+
+// MigrateCanonicalKeys recomputes every key with RecanonicalizeKey and returns those that changed, with
+// their new key, in the order of keys, so that only changed records are written back. Distinct old keys may
+// map to the same new key, e.g. when the rules gain an alias domain; merging such records is up to the
+// caller. Keys with invalid addresses are left out and reported in an *AddressListError, whose Index is
+// their position in keys.
+//
+// Example:
+//
+//	changed, err := MigrateCanonicalKeys([]CanonicalKey{
+//	    {Key: "john.doe@googlemail.com", Address: "John.Doe@googlemail.com"},
+//	    {Key: "jane@example.com", Address: "jane+news@example.com"},
+//	})
+//	if err != nil {
+//	    log.Printf("Some keys were not migrated: %v", err)
+//	}
+//
+//	fmt.Println(changed) // Output: [{johndoe@gmail.com John.Doe@googlemail.com}]
+func MigrateCanonicalKeys(keys []CanonicalKey) ([]CanonicalKey, error) {
+	var changed []CanonicalKey
+	var errs []*AddressError
+	for i, k := range keys {
+		key, ok, err := RecanonicalizeKey(k.Key, k.Address)
+		if err != nil {
+			errs = append(errs, &AddressError{Index: i, Address: k.Address, Err: err})
+			continue
+		}
+		if ok {
+			changed = append(changed, CanonicalKey{Key: key, Address: k.Address})
+		}
+	}
+	if errs != nil {
+		return changed, &AddressListError{Errors: errs}
+	}
+	return changed, nil
+}
@@ -0,0 +1,91 @@
+package bemailparts_test
+
+import (
+	"errors"
+	"github.com/bearaujus/bemailparts"
+	"reflect"
+	"testing"
+)
+
+func TestRecanonicalizeKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		oldKey      string
+		address     string
+		want        string
+		wantChanged bool
+		wantErr     error
+	}{
+		{
+			name:    "current key",
+			oldKey:  "johndoe@gmail.com",
+			address: "John.Doe+news@googlemail.com",
+			want:    "johndoe@gmail.com",
+		},
+		{
+			name:        "key without the alias domain rule",
+			oldKey:      "john.doe@googlemail.com",
+			address:     "John.Doe+news@googlemail.com",
+			want:        "johndoe@gmail.com",
+			wantChanged: true,
+		},
+		{
+			name:        "key keeping the tag",
+			oldKey:      "jane+news@example.com",
+			address:     "jane+news@example.com",
+			want:        "jane@example.com",
+			wantChanged: true,
+		},
+		{
+			name:    "invalid address",
+			oldKey:  "invalid",
+			address: "invalid",
+			wantErr: bemailparts.ErrInvalidEmailFormat,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed, err := bemailparts.RecanonicalizeKey(tt.oldKey, tt.address)
+			if !errors.Is(err, tt.wantErr) || (err != nil) != (tt.wantErr != nil) {
+				t.Fatalf("RecanonicalizeKey() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want || changed != tt.wantChanged {
+				t.Errorf("RecanonicalizeKey() got = %v, %v, want %v, %v", got, changed, tt.want, tt.wantChanged)
+			}
+		})
+	}
+}
+
+func TestMigrateCanonicalKeys(t *testing.T) {
+	keys := []bemailparts.CanonicalKey{
+		{Key: "john.doe@googlemail.com", Address: "John.Doe@googlemail.com"},
+		{Key: "jane@example.com", Address: "jane+news@example.com"},
+		{Key: "invalid", Address: "invalid"},
+		{Key: "bob+shop@example.com", Address: "bob+shop@example.com"},
+	}
+	got, err := bemailparts.MigrateCanonicalKeys(keys)
+	want := []bemailparts.CanonicalKey{
+		{Key: "johndoe@gmail.com", Address: "John.Doe@googlemail.com"},
+		{Key: "bob@example.com", Address: "bob+shop@example.com"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MigrateCanonicalKeys() got = %+v, want %+v", got, want)
+	}
+
+	var listErr *bemailparts.AddressListError
+	if !errors.As(err, &listErr) || len(listErr.Errors) != 1 || listErr.Errors[0].Index != 2 {
+		t.Fatalf("MigrateCanonicalKeys() error = %v, want an *AddressListError for index 2", err)
+	}
+	if !errors.Is(err, bemailparts.ErrInvalidEmailFormat) {
+		t.Errorf("MigrateCanonicalKeys() error = %v, want %v", err, bemailparts.ErrInvalidEmailFormat)
+	}
+
+	t.Run("test nothing to migrate", func(t *testing.T) {
+		got, err := bemailparts.MigrateCanonicalKeys([]bemailparts.CanonicalKey{
+			{Key: "jane@example.com", Address: "Jane@Example.com"},
+		})
+		if got != nil || err != nil {
+			t.Errorf("MigrateCanonicalKeys() got = %+v, %v, want nil, nil", got, err)
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package bemailparts
+
+import "strings"
+
+// reservedDomains lists the special-use domains that never receive mail, with what they are reserved for.
+// A domain is reserved if it is one of them or a subdomain of one.
+var reservedDomains = []struct {
+	domain string
+	use    string
+}{
+	{domain: "example", use: "documentation (RFC 2606)"},
+	{domain: "example.com", use: "documentation (RFC 2606)"},
+	{domain: "example.net", use: "documentation (RFC 2606)"},
+	{domain: "example.org", use: "documentation (RFC 2606)"},
+	{domain: "test", use: "testing (RFC 2606)"},
+	{domain: "invalid", use: "invalid names (RFC 2606)"},
+	{domain: "localhost", use: "the local host (RFC 6761)"},
+	{domain: "onion", use: "Tor onion services (RFC 7686)"},
+}
+
+// WithRejectReservedDomains rejects addresses whose domain is reserved, as reported by IsReservedDomain,
+// with ErrEmailDomainReserved. Such addresses can never receive mail, so signup forms can reject them as
+// obviously fake, e.g. "john@example.com" or "test@site.test". It also applies to the setters.
+//
+// Example:
+//
+//	_, err := New("john@example.com", WithRejectReservedDomains())
+//	fmt.Println(err)                                    // Output: domain is reserved for documentation (RFC 2606) at position 5 in domain
+//	fmt.Println(errors.Is(err, ErrEmailDomainReserved)) // Output: true
+func WithRejectReservedDomains() Option {
+	return func(o *options) {
+		o.rejectReserved = true
+	}
+}
+
+// IsReservedDomain reports whether the domain of e is reserved and can never receive mail: the special-use
+// names of RFC 2606 and RFC 6761 ("example.com", "example.net", "example.org" and the "example", "test",
+// "invalid" and "localhost" TLDs), the "onion" TLD of RFC 7686, and their subdomains.
+//
+// Example:
+//
+//	e, err := New("john@site.test")
+//	if err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	fmt.Println(IsReservedDomain(e)) // Output: true
+func IsReservedDomain(e BEmailParts) bool {
+	_, ok := reservedDomainUse(e.Domain())
+	return ok
+}
+
+// reservedDomainUse returns what domain is reserved for, or false if it is not reserved.
+func reservedDomainUse(domain string) (string, bool) {
+	domain = strings.ToLower(domain)
+	for _, reserved := range reservedDomains {
+		if domain == reserved.domain || strings.HasSuffix(domain, domainSeparator+reserved.domain) {
+			return reserved.use, true
+		}
+	}
+	return "", false
+}
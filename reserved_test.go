@@ -0,0 +1,80 @@
+package bemailparts_test
+
+import (
+	"errors"
+	"github.com/bearaujus/bemailparts"
+	"testing"
+)
+
+func TestIsReservedDomain(t *testing.T) {
+	tests := []struct {
+		name  string
+		email string
+		opts  []bemailparts.Option
+		want  bool
+	}{
+		{name: "documentation domain", email: "john@example.com", want: true},
+		{name: "documentation subdomain", email: "john@mail.Example.ORG", want: true},
+		{name: "documentation tld", email: "john@site.example", want: true},
+		{name: "test tld", email: "john@site.test", want: true},
+		{name: "invalid tld", email: "john@nowhere.invalid", want: true},
+		{name: "localhost tld", email: "john@app.localhost", want: true},
+		{
+			name:  "single label localhost",
+			email: "john@localhost",
+			opts:  []bemailparts.Option{bemailparts.WithAllowSingleLabelDomain()},
+			want:  true,
+		},
+		{name: "onion tld", email: "john@expyuzz4wqqyqhjn.onion", want: true},
+		{name: "similar domain", email: "john@example.co", want: false},
+		{name: "suffix without dot", email: "john@myexample.com", want: false},
+		{name: "testing tld", email: "john@site.testing", want: false},
+		{
+			name:  "address literal",
+			email: "john@[192.0.2.1]",
+			opts:  []bemailparts.Option{bemailparts.WithAllowIPDomain()},
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := bemailparts.New(tt.email, tt.opts...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			if got := bemailparts.IsReservedDomain(e); got != tt.want {
+				t.Errorf("IsReservedDomain() got = %v, want %v", got, tt.want)
+			}
+
+			opts := append([]bemailparts.Option{bemailparts.WithRejectReservedDomains()}, tt.opts...)
+			_, err = bemailparts.New(tt.email, opts...)
+			if got := errors.Is(err, bemailparts.ErrEmailDomainReserved); got != tt.want {
+				t.Errorf("New() error = %v, want ErrEmailDomainReserved %v", err, tt.want)
+			}
+		})
+	}
+
+	t.Run("test error explains the reservation", func(t *testing.T) {
+		_, err := bemailparts.New("john@example.com", bemailparts.WithRejectReservedDomains())
+		want := "domain is reserved for documentation (RFC 2606) at position 5 in domain"
+		if err == nil || err.Error() != want {
+			t.Errorf("New() error = %v, want %v", err, want)
+		}
+	})
+
+	t.Run("test setters reject reserved domains", func(t *testing.T) {
+		e, err := bemailparts.New("john@example.co", bemailparts.WithRejectReservedDomains())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = e.SetDomainTLD("test"); !errors.Is(err, bemailparts.ErrEmailDomainReserved) {
+			t.Errorf("SetDomainTLD() error = %v, want %v", err, bemailparts.ErrEmailDomainReserved)
+		}
+		if err = e.SetDomain("mail.example.net"); !errors.Is(err, bemailparts.ErrEmailDomainReserved) {
+			t.Errorf("SetDomain() error = %v, want %v", err, bemailparts.ErrEmailDomainReserved)
+		}
+		if e.Email() != "john@example.co" {
+			t.Errorf("Email() got = %v, want %v", e.Email(), "john@example.co")
+		}
+	})
+}
@@ -0,0 +1,135 @@
+package bemailparts
+
+import (
+	"io"
+	"regexp"
+	"strings"
+)
+
+// RewriteRule moves the addresses of a domain to another one. Exactly one of Domain, Suffix and Pattern
+// selects the domains it applies to.
+type RewriteRule struct {
+	// Domain matches this domain only, ignoring case, e.g. "old-brand.com".
+	Domain string
+
+	// Suffix matches this domain and its subdomains, ignoring case, e.g. "old-brand.com" matches
+	// "old-brand.com" and "eu.old-brand.com".
+	Suffix string
+
+	// Pattern matches the domains it matches once lowercased. Anchor it to match whole domains.
+	Pattern *regexp.Regexp
+
+	// Replacement is the new domain. With Pattern, it is a template expanded as by
+	// regexp.Regexp.ReplaceAllString, e.g. "$1.new-brand.com".
+	Replacement string
+}
+
+// RewriteRules applies rewrite rules to addresses, e.g. to migrate the users of a tenant to its new domain.
+// Only the domain changes, so usernames, including their "+" tags, are preserved.
+//
+// RewriteRules is immutable and safe for concurrent use.
+type RewriteRules struct {
+	rules []RewriteRule
+}
+
+// NewRewriteRules creates RewriteRules from rules, which are tried in order; the first rule matching an
+// address applies.
+//
+// Parameters:
+//
+//	rules: The rules, each with exactly one of Domain, Suffix and Pattern, and a Replacement.
+//
+// Returns:
+//   - The RewriteRules.
+//   - ErrInvalidRewriteRule if a rule has no or several matchers, or no Replacement.
+//
+// Example:
+//
+//	rules, err := NewRewriteRules(RewriteRule{Suffix: "old-brand.com", Replacement: "new-brand.com"})
+//	if err != nil {
+//	    log.Fatalf("Invalid rules: %v", err)
+//	}
+//
+//	email, _, _ := rules.Rewrite("john+news@eu.old-brand.com")
+//	fmt.Println(email) // Output: john+news@new-brand.com
+func NewRewriteRules(rules ...RewriteRule) (*RewriteRules, error) {
+	for _, rule := range rules {
+		matchers := 0
+		for _, set := range []bool{rule.Domain != "", rule.Suffix != "", rule.Pattern != nil} {
+			if set {
+				matchers++
+			}
+		}
+		if matchers != 1 || rule.Replacement == "" {
+			return nil, ErrInvalidRewriteRule
+		}
+	}
+	return &RewriteRules{rules: append([]RewriteRule(nil), rules...)}, nil
+}
+
+// Rewrite returns email with its domain replaced by the first matching rule.
+// Returns false if no rule matches, with email unchanged, and an error if email is invalid or the rule
+// produces an invalid address.
+func (r *RewriteRules) Rewrite(email string) (string, bool, error) {
+	e, err := New(email)
+	if err != nil {
+		return "", false, err
+	}
+	domain, ok := r.rewriteDomain(strings.ToLower(e.Domain()))
+	if !ok {
+		return email, false, nil
+	}
+	if err = e.SetDomain(domain); err != nil {
+		return "", false, err
+	}
+	return e.Email(), true, nil
+}
+
+// RewriteAll rewrites every address of emails like Rewrite, in a new slice in the same order. Addresses no
+// rule matches, invalid addresses and addresses a rule would make invalid are kept unchanged.
+//
+// Example:
+//
+//	migrated := rules.RewriteAll([]string{"jane@old-brand.com", "john@example.com"})
+//	fmt.Println(migrated) // Output: [jane@new-brand.com john@example.com]
+func (r *RewriteRules) RewriteAll(emails []string) []string {
+	rewritten := make([]string, len(emails))
+	for i, email := range emails {
+		rewritten[i] = email
+		if replaced, ok, err := r.Rewrite(email); ok && err == nil {
+			rewritten[i] = replaced
+		}
+	}
+	return rewritten
+}
+
+// RewriteDataset copies src to dst, rewriting every email address found in it like RewriteAll. Like
+// AnonymizeDataset, it works line by line on CSV, JSONL and other text formats.
+func (r *RewriteRules) RewriteDataset(src io.Reader, dst io.Writer) error {
+	return rewriteDataset(src, dst, func(email string) (string, error) {
+		replaced, _, err := r.Rewrite(email)
+		return replaced, err
+	})
+}
+
+// rewriteDomain returns the domain the first rule matching domain, which is lowercase, rewrites it to.
+func (r *RewriteRules) rewriteDomain(domain string) (string, bool) {
+	for _, rule := range r.rules {
+		switch {
+		case rule.Domain != "":
+			if domain == strings.ToLower(rule.Domain) {
+				return rule.Replacement, true
+			}
+		case rule.Suffix != "":
+			suffix := strings.ToLower(rule.Suffix)
+			if domain == suffix || strings.HasSuffix(domain, domainSeparator+suffix) {
+				return rule.Replacement, true
+			}
+		default:
+			if rule.Pattern.MatchString(domain) {
+				return rule.Pattern.ReplaceAllString(domain, rule.Replacement), true
+			}
+		}
+	}
+	return "", false
+}
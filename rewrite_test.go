@@ -0,0 +1,81 @@
+package bemailparts_test
+
+import (
+	"bytes"
+	"errors"
+	"github.com/bearaujus/bemailparts"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRewriteRules(t *testing.T) {
+	rules, err := bemailparts.NewRewriteRules(
+		bemailparts.RewriteRule{Domain: "legacy.example.com", Replacement: "example.com"},
+		bemailparts.RewriteRule{Suffix: "old-brand.com", Replacement: "new-brand.com"},
+		bemailparts.RewriteRule{Pattern: regexp.MustCompile(`^(\w+)\.acme\.test$`), Replacement: "$1.acme.example"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		email   string
+		want    string
+		wantOk  bool
+		wantErr error
+	}{
+		{name: "domain", email: "john@Legacy.Example.com", want: "john@example.com", wantOk: true},
+		{name: "domain does not match subdomains", email: "john@eu.legacy.example.com", want: "john@eu.legacy.example.com"},
+		{name: "suffix", email: "john+news@old-brand.com", want: "john+news@new-brand.com", wantOk: true},
+		{name: "suffix subdomain", email: "john@eu.old-brand.com", want: "john@new-brand.com", wantOk: true},
+		{name: "suffix needs label boundary", email: "john@bold-brand.com", want: "john@bold-brand.com"},
+		{name: "pattern", email: "john@sales.acme.test", want: "john@sales.acme.example", wantOk: true},
+		{name: "no match", email: "john@example.org", want: "john@example.org"},
+		{name: "invalid", email: "john@", wantErr: bemailparts.ErrInvalidEmailFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := rules.Rewrite(tt.email)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Rewrite() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("Rewrite() got = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+
+	t.Run("test rewrite all", func(t *testing.T) {
+		got := rules.RewriteAll([]string{"jane@old-brand.com", "invalid", "john@example.org"})
+		want := []string{"jane@new-brand.com", "invalid", "john@example.org"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("RewriteAll() got = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("test rewrite dataset", func(t *testing.T) {
+		var out bytes.Buffer
+		input := "id,email\n1,jane@eu.old-brand.com\n2,john@example.org\n"
+		if err := rules.RewriteDataset(strings.NewReader(input), &out); err != nil {
+			t.Fatal(err)
+		}
+		if want := "id,email\n1,jane@new-brand.com\n2,john@example.org\n"; out.String() != want {
+			t.Errorf("RewriteDataset() got = %q, want %q", out.String(), want)
+		}
+	})
+}
+
+func TestNewRewriteRulesInvalid(t *testing.T) {
+	for _, rule := range []bemailparts.RewriteRule{
+		{Replacement: "example.com"},
+		{Domain: "a.com", Suffix: "b.com", Replacement: "example.com"},
+		{Domain: "a.com"},
+	} {
+		if _, err := bemailparts.NewRewriteRules(rule); !errors.Is(err, bemailparts.ErrInvalidRewriteRule) {
+			t.Errorf("NewRewriteRules(%+v) error = %v, want %v", rule, err, bemailparts.ErrInvalidRewriteRule)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package bemailparts
+
+import "strings"
+
+// isRFC5321LocalPart reports whether s is an RFC 5321 Local-part: a Dot-string, or a Quoted-string which,
+// unlike RFC 5322, may not contain tabs.
+func isRFC5321LocalPart(s string) bool {
+	return isRFC5322LocalPart(s) && !strings.ContainsRune(s, '\t')
+}
+
+// isRFC5321Domain reports whether s is an RFC 5321 Domain or address literal.
+func isRFC5321Domain(s string) bool {
+	if isAddressLiteral(s) {
+		return validateAddressLiteral(s) == nil
+	}
+	return isRFC5321DomainName(s)
+}
+
+// isRFC5321DomainName reports whether s is a sequence of dot-separated sub-domains made of letters, digits,
+// and hyphens that neither start nor end with a hyphen.
+func isRFC5321DomainName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, label := range strings.Split(s, domainSeparator) {
+		if !isLDHLabel(label) {
+			return false
+		}
+	}
+	return true
+}
+
+func isLDHLabel(label string) bool {
+	if !isDNSLabel(label) {
+		return false
+	}
+	for i := 0; i < len(label); i++ {
+		if !isAlphaNumeric(label[i]) && label[i] != '-' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,108 @@
+package bemailparts_test
+
+import (
+	"errors"
+	"github.com/bearaujus/bemailparts"
+	"strings"
+	"testing"
+)
+
+func TestNewWithRFC5321(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		wantErr error
+	}{
+		{
+			name:  "success",
+			email: "john.doe@mail.example.com",
+		},
+		{
+			name:  "success quoted local part",
+			email: `"john doe"@example.com`,
+		},
+		{
+			name:  "success address literal",
+			email: "john@[192.0.2.1]",
+		},
+		{
+			name:  "success ipv6 address literal",
+			email: "john@[IPv6:2001:db8::1]",
+		},
+		{
+			name:    "error address literal that is not an address",
+			email:   "a@[garbage]",
+			wantErr: bemailparts.ErrInvalidAddressLiteral,
+		},
+		{
+			name:    "error address literal out of range",
+			email:   "a@[1.2.3.999]",
+			wantErr: bemailparts.ErrInvalidAddressLiteral,
+		},
+		{
+			name:  "success 64 octet local part",
+			email: strings.Repeat("a", 64) + "@example.com",
+		},
+		{
+			name:    "error 65 octet local part",
+			email:   strings.Repeat("a", 65) + "@example.com",
+			wantErr: bemailparts.ErrEmailUsernameTooLong,
+		},
+		{
+			name:    "error 256 octet domain",
+			email:   "john@" + strings.Repeat(strings.Repeat("a", 63)+".", 4) + "com",
+			wantErr: bemailparts.ErrEmailDomainTooLong,
+		},
+		{
+			name:    "error domain label with underscore",
+			email:   "john@ex_ample.com",
+			wantErr: bemailparts.ErrInvalidEmailDomainFormat,
+		},
+		{
+			name:    "error domain label with leading hyphen",
+			email:   "john@-example.com",
+			wantErr: bemailparts.ErrInvalidEmailDomainFormat,
+		},
+		{
+			name:    "error tab in quoted local part",
+			email:   "\"john\tdoe\"@example.com",
+			wantErr: bemailparts.ErrInvalidEmailUsernameFormat,
+		},
+		{
+			name:    "error consecutive dots",
+			email:   "john..doe@example.com",
+			wantErr: bemailparts.ErrInvalidEmailFormat,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bemailparts.New(tt.email, bemailparts.WithRFC5321())
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr == nil && got.Email() != tt.email {
+				t.Errorf("New() got = %v, want %v", got, tt.email)
+			}
+		})
+	}
+
+	t.Run("test setters keep rfc5321 rules", func(t *testing.T) {
+		e, err := bemailparts.New("john@example.com", bemailparts.WithRFC5321())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = e.SetUsername(strings.Repeat("a", 65)); !errors.Is(err, bemailparts.ErrEmailUsernameTooLong) {
+			t.Errorf("SetUsername() error = %v, want %v", err, bemailparts.ErrEmailUsernameTooLong)
+		}
+		if err = e.SetDomainName("ex_ample"); !errors.Is(err, bemailparts.ErrInvalidEmailDomainNameFormat) {
+			t.Errorf("SetDomainName() error = %v, want %v", err, bemailparts.ErrInvalidEmailDomainNameFormat)
+		}
+		if err = e.SetDomainTLD("co.uk"); err != nil {
+			t.Fatal(err)
+		}
+		if e.Email() != "john@example.co.uk" {
+			t.Errorf("Email() got = %v, want %v", e.Email(), "john@example.co.uk")
+		}
+	})
+}
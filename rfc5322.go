@@ -0,0 +1,120 @@
+package bemailparts
+
+import "strings"
+
+// atextSpecials are the non-alphanumeric characters allowed in an RFC 5322 atom.
+const atextSpecials = "!#$%&'*+-/=?^_`{|}~"
+
+// splitRFC5322Email splits an RFC 5322 addr-spec into its local part and domain.
+func splitRFC5322Email(email string) (string, string, error) {
+	var end int
+	if strings.HasPrefix(email, `"`) {
+		end = scanQuotedString(email, 0)
+	} else {
+		end = scanDotAtom(email, 0)
+	}
+	if end < 0 || end >= len(email) || email[end] != emailSeparator[0] {
+		return "", "", ErrInvalidEmailFormat
+	}
+
+	username, domain := email[:end], email[end+1:]
+	if !isRFC5322Domain(domain) {
+		return "", "", ErrInvalidEmailFormat
+	}
+	return username, domain, nil
+}
+
+func isRFC5322LocalPart(s string) bool {
+	if strings.HasPrefix(s, `"`) {
+		return scanQuotedString(s, 0) == len(s)
+	}
+	return isRFC5322DotAtom(s)
+}
+
+func isRFC5322Domain(s string) bool {
+	if strings.HasPrefix(s, "[") {
+		return scanDomainLiteral(s, 0) == len(s)
+	}
+	return isRFC5322DotAtom(s)
+}
+
+func isRFC5322DotAtom(s string) bool {
+	return s != "" && scanDotAtom(s, 0) == len(s)
+}
+
+// scanDotAtom returns the index just past the dot-atom-text starting at s[i], or -1 if there is none.
+func scanDotAtom(s string, i int) int {
+	start := i
+	for i < len(s) {
+		if isAtext(s[i]) {
+			i++
+			continue
+		}
+		if s[i] == domainSeparator[0] && i > start && isAtext(s[i-1]) && i+1 < len(s) && isAtext(s[i+1]) {
+			i++
+			continue
+		}
+		break
+	}
+	if i == start {
+		return -1
+	}
+	return i
+}
+
+// scanQuotedString returns the index just past the quoted-string starting at s[i], or -1 if there is none.
+func scanQuotedString(s string, i int) int {
+	if i >= len(s) || s[i] != '"' {
+		return -1
+	}
+	for i++; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"':
+			return i + 1
+		case c == '\\':
+			if i+1 >= len(s) || !isVCharOrWSP(s[i+1]) {
+				return -1
+			}
+			i++
+		case !isQtext(c) && c != ' ' && c != '\t':
+			return -1
+		}
+	}
+	return -1
+}
+
+// scanDomainLiteral returns the index just past the domain-literal starting at s[i], or -1 if there is none.
+func scanDomainLiteral(s string, i int) int {
+	if i >= len(s) || s[i] != '[' {
+		return -1
+	}
+	for i++; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == ']':
+			return i + 1
+		case !isDtext(c) && c != ' ' && c != '\t':
+			return -1
+		}
+	}
+	return -1
+}
+
+func isAtext(c byte) bool {
+	return isAlphaNumeric(c) || strings.IndexByte(atextSpecials, c) >= 0
+}
+
+func isQtext(c byte) bool {
+	return c == 33 || (c >= 35 && c <= 91) || (c >= 93 && c <= 126)
+}
+
+func isDtext(c byte) bool {
+	return (c >= 33 && c <= 90) || (c >= 94 && c <= 126)
+}
+
+func isVCharOrWSP(c byte) bool {
+	return (c >= 33 && c <= 126) || c == ' ' || c == '\t'
+}
+
+func isAlphaNumeric(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,127 @@
+package bemailparts_test
+
+import (
+	"github.com/bearaujus/bemailparts"
+	"testing"
+)
+
+func TestNewWithRFC5322(t *testing.T) {
+	tests := []struct {
+		name         string
+		email        string
+		wantUsername string
+		wantDomain   string
+		wantErr      bool
+	}{
+		{
+			name:         "success dot-atom",
+			email:        "john.doe@example.com",
+			wantUsername: "john.doe",
+			wantDomain:   "example.com",
+		},
+		{
+			name:         "success atext specials",
+			email:        "o'brien+tag/x=y@example.com",
+			wantUsername: "o'brien+tag/x=y",
+			wantDomain:   "example.com",
+		},
+		{
+			name:         "success quoted local part",
+			email:        `"john doe"@example.com`,
+			wantUsername: `"john doe"`,
+			wantDomain:   "example.com",
+		},
+		{
+			name:         "success quoted local part with at sign and escapes",
+			email:        `"a@b\"c"@example.com`,
+			wantUsername: `"a@b\"c"`,
+			wantDomain:   "example.com",
+		},
+		{
+			name:         "success domain literal",
+			email:        "john@[192.0.2.1]",
+			wantUsername: "john",
+			wantDomain:   "[192.0.2.1]",
+		},
+		{
+			name:    "error consecutive dots",
+			email:   "user..name@example.com",
+			wantErr: true,
+		},
+		{
+			name:    "error leading dot",
+			email:   ".user@example.com",
+			wantErr: true,
+		},
+		{
+			name:    "error trailing dot",
+			email:   "user.@example.com",
+			wantErr: true,
+		},
+		{
+			name:    "error unterminated quoted string",
+			email:   `"john@example.com`,
+			wantErr: true,
+		},
+		{
+			name:    "error unquoted space",
+			email:   "john doe@example.com",
+			wantErr: true,
+		},
+		{
+			name:    "error missing domain",
+			email:   "john@",
+			wantErr: true,
+		},
+		{
+			name:    "error domain with consecutive dots",
+			email:   "john@example..com",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bemailparts.New(tt.email, bemailparts.WithRFC5322())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Username() != tt.wantUsername || got.Domain() != tt.wantDomain || got.Email() != tt.email {
+				t.Errorf("New() got = %v, %v, %v, want %v, %v, %v",
+					got.Username(), got.Domain(), got.Email(), tt.wantUsername, tt.wantDomain, tt.email)
+			}
+		})
+	}
+
+	t.Run("test setters keep rfc5322 rules", func(t *testing.T) {
+		e, err := bemailparts.New("john@example.com", bemailparts.WithRFC5322())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = e.SetUsername(`"jane doe"`); err != nil {
+			t.Fatal(err)
+		}
+		if err = e.SetUsername("jane..doe"); err == nil {
+			t.Error("expecting an error on SetUsername() but got nil")
+		}
+		if err = e.SetDomainTLD("org"); err != nil {
+			t.Fatal(err)
+		}
+		if e.Email() != `"jane doe"@example.org` {
+			t.Errorf("Email() got = %v, want %v", e.Email(), `"jane doe"@example.org`)
+		}
+	})
+
+	t.Run("test domain literal has no domain name or tld", func(t *testing.T) {
+		e, err := bemailparts.New("john@[192.0.2.1]", bemailparts.WithRFC5322())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.DomainName() != "" || e.DomainTLD() != "" {
+			t.Errorf("DomainName(), DomainTLD() got = %v, %v, want empty", e.DomainName(), e.DomainTLD())
+		}
+	})
+}
@@ -0,0 +1,34 @@
+package bemailparts
+
+// WithSampling passes a random fraction rate of the addresses accepted by the constructors to hook, so they
+// can be verified more deeply, e.g. by an SMTP probe, and the false-accept rate of the cheap checks of this
+// package monitored over time. A rate of 0 or less samples nothing, and 1 or more samples every address.
+//
+// The hook is called synchronously with the accepted address, so it should only hand the address off, e.g.
+// to a buffered channel read by a verification worker.
+//
+// Example:
+//
+//	samples := make(chan string, 100)
+//	parser := NewParser(WithSampling(0.01, func(email string) {
+//	    select {
+//	    case samples <- email:
+//	    default: // Drop the sample rather than slow down parsing.
+//	    }
+//	}))
+func WithSampling(rate float64, hook func(email string)) Option {
+	return func(o *options) {
+		o.sampleRate = rate
+		o.sampleHook = hook
+	}
+}
+
+// sample passes email to the sampling hook, if any, with the configured probability.
+func (o *options) sample(email string) {
+	if o.sampleHook == nil || o.sampleRate <= 0 {
+		return
+	}
+	if o.sampleRate >= 1 || o.float64() < o.sampleRate {
+		o.sampleHook(email)
+	}
+}
@@ -0,0 +1,45 @@
+package bemailparts_test
+
+import (
+	"github.com/bearaujus/bemailparts"
+	"reflect"
+	"testing"
+)
+
+func TestWithSampling(t *testing.T) {
+	tests := []struct {
+		name string
+		rate float64
+		want []string
+	}{
+		{name: "sample every address", rate: 1, want: []string{"john@example.com", "jane@example.com"}},
+		{name: "sample nothing", rate: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			parser := bemailparts.NewParser(bemailparts.WithLowercase(), bemailparts.WithSampling(tt.rate, func(email string) {
+				got = append(got, email)
+			}))
+			for _, email := range []string{"John@Example.com", "invalid", "jane@example.com"} {
+				_, _ = parser.Parse(email)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sampled = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("test sample fraction", func(t *testing.T) {
+		sampled := 0
+		parser := bemailparts.NewParser(bemailparts.WithSampling(0.25, func(string) { sampled++ }))
+		for i := 0; i < 10000; i++ {
+			if _, err := parser.Parse("john@example.com"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if sampled < 2000 || sampled > 3000 {
+			t.Errorf("sampled %d of 10000 addresses, want about 2500", sampled)
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package bemailparts
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SMTPError is an SMTP reply describing why an address was rejected. Its Code, EnhancedCode and Message
+// mirror github.com/emersion/go-smtp's SMTPError, so SMTP servers can convert one into the other directly.
+// It unwraps to Err, so errors.Is and ErrorCodeOf work on it as on the errors of New.
+type SMTPError struct {
+	Code         int
+	EnhancedCode EnhancedCode
+	Message      string
+
+	// Err is the error the address was rejected for, such as a *ParseError.
+	Err error
+}
+
+// Error returns the reply in wire format, e.g. "553 5.1.3 Bad destination mailbox address syntax".
+func (e *SMTPError) Error() string {
+	return fmt.Sprintf("%d %s %s", e.Code, e.EnhancedCode, e.Message)
+}
+
+func (e *SMTPError) Unwrap() error {
+	return e.Err
+}
+
+// ParseRcpt validates the forward-path argument of an SMTP RCPT TO command, as received by SMTP server
+// implementations such as go-smtp. Surrounding angle brackets and whitespace are accepted and stripped, and
+// so is a source route such as "@a,@b:" in "<@a,@b:user@c>", which RFC 5321 requires servers to ignore.
+// The special recipient "<Postmaster>" without a domain, which servers must accept in any case (RFC 5321,
+// section 4.5.1), is returned as given with an empty Domain: Email and Username both return "Postmaster".
+//
+// The recipient is not canonicalized: apply Canonical or RewriteRules to its Email before routing it.
+//
+// Parameters:
+//
+//	to: The forward-path to validate.
+//	opts: Optional Options controlling validation, e.g. WithRFC5321().
+//
+// Returns:
+//   - A BEmailParts instance representing the recipient.
+//   - An *SMTPError if the recipient is rejected: code 553 for a mailbox name that is not allowed, such as an
+//     invalid address, the null path "<>" or an address needing SMTPUTF8, and code 550 for other rejections,
+//     such as a reserved domain. The enhanced code is the one EnhancedCodeFor returns, e.g. 5.1.3.
+//
+// Example:
+//
+//	func (s *session) Rcpt(to string, opts *smtp.RcptOptions) error {
+//	    rcpt, err := ParseRcpt(to, WithRFC5321())
+//	    if err != nil {
+//	        return err
+//	    }
+//	    s.recipients = append(s.recipients, rcpt)
+//	    return nil
+//	}
+func ParseRcpt(to string, opts ...Option) (BEmailParts, error) {
+	to = strings.TrimSpace(to)
+	if strings.HasPrefix(to, "<") && strings.HasSuffix(to, ">") {
+		to = to[1 : len(to)-1]
+	}
+	if to == "" {
+		// The null path is only allowed as the reverse-path of MAIL FROM.
+		return nil, newRcptError(ErrInvalidEmailFormat)
+	}
+	if strings.EqualFold(to, postmaster) {
+		return &bEmailParts{username: to, opts: newOptions(opts)}, nil
+	}
+	if strings.HasPrefix(to, "@") {
+		i := strings.IndexByte(to, ':')
+		if i < 0 {
+			return nil, newRcptError(ErrInvalidEmailFormat)
+		}
+		to = to[i+1:]
+	}
+
+	e, err := New(to, opts...)
+	if err != nil {
+		return nil, newRcptError(err)
+	}
+	return e, nil
+}
+
+// postmaster is the local part every SMTP server must accept mail for, with or without a domain.
+const postmaster = "Postmaster"
+
+// newRcptError returns the *SMTPError rejecting a recipient for err.
+func newRcptError(err error) *SMTPError {
+	code, ok := EnhancedCodeFor(err)
+	if !ok {
+		code = EnhancedCode{5, 1, 3}
+	}
+	reply := 550
+	if code == (EnhancedCode{5, 1, 3}) || code == (EnhancedCode{5, 6, 7}) {
+		// 553: requested action not taken, mailbox name not allowed (RFC 5321, section 4.2.2).
+		reply = 553
+	}
+	return &SMTPError{Code: reply, EnhancedCode: code, Message: code.Description(), Err: err}
+}
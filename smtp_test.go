@@ -0,0 +1,108 @@
+package bemailparts_test
+
+import (
+	"errors"
+	"github.com/bearaujus/bemailparts"
+	"testing"
+)
+
+func TestParseRcpt(t *testing.T) {
+	tests := []struct {
+		name     string
+		to       string
+		opts     []bemailparts.Option
+		want     string
+		wantCode int
+		wantEnh  bemailparts.EnhancedCode
+	}{
+		{
+			name: "success",
+			to:   "john.doe@example.com",
+			want: "john.doe@example.com",
+		},
+		{
+			name: "success with angle brackets",
+			to:   " <john.doe@example.com> ",
+			want: "john.doe@example.com",
+		},
+		{
+			name: "success with source route",
+			to:   "<@relay1.example.org,@relay2.example.org:john.doe@example.com>",
+			want: "john.doe@example.com",
+		},
+		{
+			name: "success postmaster without domain",
+			to:   "<postMaster>",
+			want: "postMaster",
+		},
+		{
+			name: "success postmaster with domain",
+			to:   "<Postmaster@example.com>",
+			want: "Postmaster@example.com",
+		},
+		{
+			name:     "error invalid recipient",
+			to:       "<john.doe>",
+			wantCode: 553,
+			wantEnh:  bemailparts.EnhancedCode{5, 1, 3},
+		},
+		{
+			name:     "error null path",
+			to:       "<>",
+			wantCode: 553,
+			wantEnh:  bemailparts.EnhancedCode{5, 1, 3},
+		},
+		{
+			name:     "error source route without mailbox",
+			to:       "<@relay.example.org>",
+			wantCode: 553,
+			wantEnh:  bemailparts.EnhancedCode{5, 1, 3},
+		},
+		{
+			name:     "error requires smtputf8",
+			to:       "<用户@example.com>",
+			opts:     []bemailparts.Option{bemailparts.WithUnicodeLocalPart(), bemailparts.WithASCIIOnly()},
+			wantCode: 553,
+			wantEnh:  bemailparts.EnhancedCode{5, 6, 7},
+		},
+		{
+			name:     "error reserved domain",
+			to:       "<john.doe@example.com>",
+			opts:     []bemailparts.Option{bemailparts.WithRejectReservedDomains()},
+			wantCode: 550,
+			wantEnh:  bemailparts.EnhancedCode{5, 1, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bemailparts.ParseRcpt(tt.to, tt.opts...)
+			if (err != nil) != (tt.wantCode != 0) {
+				t.Errorf("ParseRcpt() error = %v, want code %v", err, tt.wantCode)
+				return
+			}
+			if tt.wantCode != 0 {
+				var smtpErr *bemailparts.SMTPError
+				if !errors.As(err, &smtpErr) || smtpErr.Code != tt.wantCode || smtpErr.EnhancedCode != tt.wantEnh {
+					t.Errorf("ParseRcpt() error = %v, want %v %v", err, tt.wantCode, tt.wantEnh)
+				}
+				return
+			}
+			if got.String() != tt.want {
+				t.Errorf("ParseRcpt() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	_, err := bemailparts.ParseRcpt("<john.doe@example.com>", bemailparts.WithRejectReservedDomains())
+	if !errors.Is(err, bemailparts.ErrEmailDomainReserved) {
+		t.Errorf("ParseRcpt() error = %v, want %v", err, bemailparts.ErrEmailDomainReserved)
+	}
+	if code, ok := bemailparts.ErrorCodeOf(err); !ok || code != bemailparts.CodeEmailDomainReserved {
+		t.Errorf("ErrorCodeOf() got = %v, %v, want %v", code, ok, bemailparts.CodeEmailDomainReserved)
+	}
+
+	err = &bemailparts.SMTPError{Code: 553, EnhancedCode: bemailparts.EnhancedCode{5, 1, 3}, Message: "Bad destination mailbox address syntax"}
+	if want := "553 5.1.3 Bad destination mailbox address syntax"; err.Error() != want {
+		t.Errorf("Error() got = %v, want %v", err.Error(), want)
+	}
+}
@@ -0,0 +1,78 @@
+package bemailparts
+
+import "strings"
+
+// SplitAddresses splits text holding several addresses, as pasted from mail clients or spreadsheets, into
+// candidate addresses for validation. Addresses may be separated by commas, semicolons, line breaks, tabs or
+// spaces, in any mix, e.g. "a@x.com; b@y.org\nc@z.net". Separators inside quoted strings, comments, angle
+// brackets and domain literals do not split.
+//
+// Mailboxes with display names, such as "Doe, John <john@example.com>" as copied from Outlook, yield the
+// address inside the angle brackets; the display name is dropped. Other words without '@' are dropped as
+// well. Candidates are returned in order, including duplicates, and are not validated. Use ParseAddressList
+// instead to parse header fields, keeping the display names.
+//
+// Example:
+//
+//	candidates := SplitAddresses("Doe, John <john@example.com>; jane@example.com\nbob@example.org")
+//	fmt.Println(candidates) // Output: [john@example.com jane@example.com bob@example.org]
+func SplitAddresses(s string) []string {
+	var candidates []string
+	for _, token := range splitAddressTokens(s) {
+		if strings.HasPrefix(token, "<") {
+			token = strings.TrimSuffix(token[1:], ">")
+		}
+		quoted := len(token) > 1 && token[0] == '"' && token[len(token)-1] == '"'
+		if token != "" && !quoted && strings.Contains(token, emailSeparator) {
+			candidates = append(candidates, token)
+		}
+	}
+	return candidates
+}
+
+// splitAddressTokens splits s at the separators of SplitAddresses outside quoted strings, comments, angle
+// brackets and domain literals, skipping empty tokens. An angle-bracketed address is a token of its own.
+func splitAddressTokens(s string) []string {
+	var tokens []string
+	add := func(token string) {
+		if token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+
+	start, depth, quoted, bracketed := 0, 0, false, false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' && (quoted || depth > 0):
+			i++
+		case quoted:
+			quoted = c != '"'
+		case c == '"' && depth == 0:
+			quoted = true
+		case c == '(':
+			depth++
+		case c == ')' && depth > 0:
+			depth--
+		case depth > 0:
+		case c == '[':
+			bracketed = true
+		case c == ']':
+			bracketed = false
+		case bracketed:
+		case c == '<':
+			add(s[start:i])
+			end := strings.IndexByte(s[i:], '>')
+			if end < 0 {
+				end = len(s) - i - 1
+			}
+			add(s[i : i+end+1])
+			i += end
+			start = i + 1
+		case strings.IndexByte(",; \t\r\n", c) >= 0:
+			add(s[start:i])
+			start = i + 1
+		}
+	}
+	add(s[start:])
+	return tokens
+}
@@ -0,0 +1,62 @@
+package bemailparts_test
+
+import (
+	"github.com/bearaujus/bemailparts"
+	"reflect"
+	"testing"
+)
+
+func TestSplitAddresses(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "outlook",
+			input: "Doe, John <john@example.com>; Jane Roe <jane@example.com>",
+			want:  []string{"john@example.com", "jane@example.com"},
+		},
+		{
+			name:  "mixed separators",
+			input: "a@x.com b@y.org,,c@z.net\t\r\nd@w.io;",
+			want:  []string{"a@x.com", "b@y.org", "c@z.net", "d@w.io"},
+		},
+		{
+			name:  "quoted strings",
+			input: `"john doe"@example.com, "Doe, Jane" <jane@example.com>, "bob@example.org" <bob@example.org>`,
+			want:  []string{`"john doe"@example.com`, "jane@example.com", "bob@example.org"},
+		},
+		{
+			name:  "comments and domain literals",
+			input: "john(work, home)@example.com; x@[192.0.2.1]",
+			want:  []string{"john(work, home)@example.com", "x@[192.0.2.1]"},
+		},
+		{
+			name:  "no space before angle bracket",
+			input: "John<john@x.com>,Jane<jane@x.com>",
+			want:  []string{"john@x.com", "jane@x.com"},
+		},
+		{
+			name:  "unterminated angle bracket",
+			input: "<john@x.com",
+			want:  []string{"john@x.com"},
+		},
+		{
+			name:  "duplicates kept",
+			input: "john@x.com\njohn@x.com",
+			want:  []string{"john@x.com", "john@x.com"},
+		},
+		{
+			name:  "no addresses",
+			input: " , ; not an address",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bemailparts.SplitAddresses(tt.input); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitAddresses() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package bemailparts
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SpoofRisk describes how the domain of an address could be used to impersonate another domain with
+// lookalike characters, a homograph attack.
+type SpoofRisk struct {
+	// MixedScript reports whether a label of the domain mixes scripts that are not written together, such
+	// as Latin and Cyrillic in "pаypal.com".
+	MixedScript bool
+
+	// Lookalike reports whether a label of the domain is made only of ASCII characters and non-ASCII
+	// characters that look like ASCII ones, such as Cyrillic "аррӏе", so that the domain can pass for
+	// Skeleton.
+	Lookalike bool
+
+	// Skeleton is the domain in Unicode form, lowercased, with every confusable character replaced by the
+	// ASCII character it looks like, e.g. "paypal.com" for "pаypal.com".
+	Skeleton string
+}
+
+// Risky reports whether the domain should be treated as a possible spoof, e.g. by requiring extra
+// verification at signup.
+func (r SpoofRisk) Risky() bool {
+	return r.MixedScript || r.Lookalike
+}
+
+// spoofScripts are the scripts told apart when looking for mixed-script labels. Characters of other
+// scripts, digits and hyphens are ignored.
+var spoofScripts = []struct {
+	name  string
+	table *unicode.RangeTable
+}{
+	{"Latin", unicode.Latin},
+	{"Cyrillic", unicode.Cyrillic},
+	{"Greek", unicode.Greek},
+	{"Armenian", unicode.Armenian},
+	{"Hebrew", unicode.Hebrew},
+	{"Arabic", unicode.Arabic},
+	{"Han", unicode.Han},
+	{"Hiragana", unicode.Hiragana},
+	{"Katakana", unicode.Katakana},
+	{"Hangul", unicode.Hangul},
+	{"Bopomofo", unicode.Bopomofo},
+	{"Thai", unicode.Thai},
+}
+
+// spoofScriptCombinations are the sets of scripts that may share a label, following the "highly
+// restrictive" level of Unicode Technical Standard #39.
+var spoofScriptCombinations = [][]string{
+	{"Latin", "Han", "Hiragana", "Katakana"},
+	{"Latin", "Han", "Bopomofo"},
+	{"Latin", "Han", "Hangul"},
+}
+
+// confusables maps characters that are commonly mistaken for ASCII letters and digits to them. It is a
+// subset of the Unicode confusables data covering the Cyrillic, Greek and Latin lookalikes seen in phishing
+// domains.
+var confusables = map[rune]rune{
+	// Cyrillic.
+	'а': 'a', 'е': 'e', 'һ': 'h', 'і': 'i', 'ј': 'j', 'о': 'o', 'р': 'p', 'с': 'c', 'ѕ': 's', 'у': 'y',
+	'х': 'x', 'ԁ': 'd', 'ԛ': 'q', 'ԝ': 'w', 'ӏ': 'l',
+	// Greek.
+	'α': 'a', 'ι': 'i', 'ν': 'v', 'ο': 'o', 'ρ': 'p', 'υ': 'u', 'ϲ': 'c', 'ϳ': 'j',
+	// Latin.
+	'ı': 'i', 'ɑ': 'a', 'ɡ': 'g', 'ɩ': 'i', 'ℓ': 'l',
+}
+
+// SpoofRiskOf analyzes the domain of e for homograph attacks: labels mixing scripts, such as Cyrillic "а" in
+// "pаypal.com", and labels made of characters that look like ASCII ones.
+//
+// Example:
+//
+//	e, err := New("john@pаypal.com", WithIDN())
+//	if err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	risk := SpoofRiskOf(e)
+//	fmt.Println(risk.Risky(), risk.Skeleton) // Output: true paypal.com
+func SpoofRiskOf(e BEmailParts) SpoofRisk {
+	return domainSpoofRisk(e.Domain())
+}
+
+// domainSpoofRisk analyzes domain, which may be in Unicode or ACE form.
+func domainSpoofRisk(domain string) SpoofRisk {
+	if isAddressLiteral(domain) {
+		return SpoofRisk{Skeleton: domain}
+	}
+	if unicodeDomain, err := ToUnicode(domain); err == nil {
+		domain = unicodeDomain
+	}
+	domain = strings.ToLower(domain)
+
+	var risk SpoofRisk
+	labels := strings.Split(domain, domainSeparator)
+	for i, label := range labels {
+		if isMixedScript(label) {
+			risk.MixedScript = true
+		}
+		if skeleton := labelSkeleton(label); skeleton != label {
+			labels[i] = skeleton
+			if isASCII(skeleton) {
+				risk.Lookalike = true
+			}
+		}
+	}
+	risk.Skeleton = strings.Join(labels, domainSeparator)
+	return risk
+}
+
+// labelSkeleton replaces the confusable characters of label with the ASCII characters they look like.
+func labelSkeleton(label string) string {
+	if isASCII(label) {
+		return label
+	}
+	var skeleton strings.Builder
+	for _, r := range label {
+		if c, ok := confusables[r]; ok {
+			r = c
+		}
+		skeleton.WriteRune(r)
+	}
+	return skeleton.String()
+}
+
+// isMixedScript reports whether label contains characters of scripts that may not share a label.
+func isMixedScript(label string) bool {
+	var scripts []string
+	for _, r := range label {
+		for _, script := range spoofScripts {
+			if unicode.Is(script.table, r) {
+				if !containsString(scripts, script.name) {
+					scripts = append(scripts, script.name)
+				}
+				break
+			}
+		}
+	}
+	if len(scripts) < 2 {
+		return false
+	}
+
+next:
+	for _, combination := range spoofScriptCombinations {
+		for _, script := range scripts {
+			if !containsString(combination, script) {
+				continue next
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func containsString(s []string, v string) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
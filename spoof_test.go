@@ -0,0 +1,72 @@
+package bemailparts_test
+
+import (
+	"github.com/bearaujus/bemailparts"
+	"testing"
+)
+
+func TestSpoofRisk(t *testing.T) {
+	tests := []struct {
+		name  string
+		email string
+		opts  []bemailparts.Option
+		want  bemailparts.SpoofRisk
+	}{
+		{
+			name:  "ascii domain",
+			email: "john@paypal.com",
+			want:  bemailparts.SpoofRisk{Skeleton: "paypal.com"},
+		},
+		{
+			name:  "cyrillic a in latin label",
+			email: "john@pаypal.com",
+			opts:  []bemailparts.Option{bemailparts.WithIDN()},
+			want:  bemailparts.SpoofRisk{MixedScript: true, Lookalike: true, Skeleton: "paypal.com"},
+		},
+		{
+			name:  "ace form of mixed label",
+			email: "john@xn--pypal-4ve.com",
+			opts:  []bemailparts.Option{bemailparts.WithIDN()},
+			want:  bemailparts.SpoofRisk{MixedScript: true, Lookalike: true, Skeleton: "paypal.com"},
+		},
+		{
+			name:  "whole-script cyrillic lookalike",
+			email: "john@аррӏе.com",
+			opts:  []bemailparts.Option{bemailparts.WithIDN()},
+			want:  bemailparts.SpoofRisk{Lookalike: true, Skeleton: "apple.com"},
+		},
+		{
+			name:  "genuine cyrillic domain",
+			email: "ivan@пример.рф",
+			opts:  []bemailparts.Option{bemailparts.WithIDN()},
+			want:  bemailparts.SpoofRisk{Skeleton: "пpимep.pф"},
+		},
+		{
+			name:  "japanese label mixing allowed scripts",
+			email: "taro@ドメイン名例jp.com",
+			opts:  []bemailparts.Option{bemailparts.WithIDN()},
+			want:  bemailparts.SpoofRisk{Skeleton: "ドメイン名例jp.com"},
+		},
+		{
+			name:  "address literal",
+			email: "john@[192.0.2.1]",
+			opts:  []bemailparts.Option{bemailparts.WithAllowIPDomain()},
+			want:  bemailparts.SpoofRisk{Skeleton: "[192.0.2.1]"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := bemailparts.New(tt.email, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := bemailparts.SpoofRiskOf(e)
+			if got != tt.want {
+				t.Errorf("SpoofRiskOf() got = %+v, want %+v", got, tt.want)
+			}
+			if got.Risky() != (tt.want.MixedScript || tt.want.Lookalike) {
+				t.Errorf("Risky() got = %v", got.Risky())
+			}
+		})
+	}
+}
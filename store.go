@@ -0,0 +1,130 @@
+package bemailparts
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Store is a key-value store persisting the state of a SuppressionList, a Quarantine or a BouncePolicy, so that
+// the storage backend can be swapped, e.g. for a database shared by several processes. Several of them can share
+// a Store; their keys are prefixed with "suppression/", "quarantine/" and "bounce/". MemoryStore keeps the
+// state in memory, and FileStore in a log file.
+//
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Get returns the value stored at key, and false if there is none or it has expired.
+	Get(key string) ([]byte, bool, error)
+
+	// Put stores value at key, replacing any previous value. A positive ttl makes the value expire once ttl
+	// has elapsed; otherwise it is kept until deleted.
+	Put(key string, value []byte, ttl time.Duration) error
+
+	// Delete removes the value stored at key, if any.
+	Delete(key string) error
+
+	// Scan calls fn for every unexpired key starting with prefix and its value, in key order, until fn
+	// returns false.
+	Scan(prefix string, fn func(key string, value []byte) bool) error
+}
+
+// MemoryStore is a Store keeping values in memory. It is the Store used by NewSuppressionList,
+// NewQuarantine and NewBouncePolicy.
+//
+// A MemoryStore is safe for concurrent use.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	clock   Clock
+	entries map[string]memoryStoreEntry
+}
+
+type memoryStoreEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+//
+// Example:
+//
+//	store := NewMemoryStore()
+//	suppressions := NewSuppressionListWithStore(store)
+//	quarantine := NewQuarantineWithStore(24*time.Hour, store)
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{clock: systemClock, entries: map[string]memoryStoreEntry{}}
+}
+
+// SetClock makes the MemoryStore read the current time from clock, e.g. to simulate the expiry of values in
+// tests. Passing nil restores the system clock.
+func (s *MemoryStore) SetClock(clock Clock) {
+	if clock == nil {
+		clock = systemClock
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clock = clock
+}
+
+func (s *MemoryStore) Get(key string) ([]byte, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entry, ok := s.entries[key]
+	if !ok || entry.expired(s.clock.Now()) {
+		return nil, false, nil
+	}
+	return append([]byte(nil), entry.value...), true, nil
+}
+
+func (s *MemoryStore) Put(key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry := memoryStoreEntry{value: append([]byte(nil), value...)}
+	if ttl > 0 {
+		entry.expires = s.clock.Now().Add(ttl)
+	}
+	s.entries[key] = entry
+	return nil
+}
+
+func (s *MemoryStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+	return nil
+}
+
+// Scan works on a snapshot of the matching values, so fn may call other methods of the MemoryStore.
+func (s *MemoryStore) Scan(prefix string, fn func(key string, value []byte) bool) error {
+	type pair struct {
+		key   string
+		value []byte
+	}
+
+	s.mu.Lock()
+	now := s.clock.Now()
+	var pairs []pair
+	for key, entry := range s.entries {
+		if entry.expired(now) {
+			delete(s.entries, key)
+			continue
+		}
+		if strings.HasPrefix(key, prefix) {
+			pairs = append(pairs, pair{key: key, value: append([]byte(nil), entry.value...)})
+		}
+	}
+	s.mu.Unlock()
+
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].key < pairs[j].key })
+	for _, p := range pairs {
+		if !fn(p.key, p.value) {
+			break
+		}
+	}
+	return nil
+}
+
+func (e memoryStoreEntry) expired(now time.Time) bool {
+	return !e.expires.IsZero() && !now.Before(e.expires)
+}
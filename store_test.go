@@ -0,0 +1,128 @@
+package bemailparts_test
+
+import (
+	"errors"
+	"github.com/bearaujus/bemailparts"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// failingStore is a Store whose backend is unavailable.
+type failingStore struct{}
+
+var errStoreUnavailable = errors.New("store unavailable")
+
+func (failingStore) Get(string) ([]byte, bool, error) { return nil, false, errStoreUnavailable }
+
+func (failingStore) Put(string, []byte, time.Duration) error { return errStoreUnavailable }
+
+func (failingStore) Delete(string) error { return errStoreUnavailable }
+
+func (failingStore) Scan(string, func(string, []byte) bool) error { return errStoreUnavailable }
+
+func TestMemoryStore(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	store := bemailparts.NewMemoryStore()
+	store.SetClock(clock)
+
+	for key, ttl := range map[string]time.Duration{"a/2": 0, "a/1": time.Hour, "b/1": 0} {
+		if err := store.Put(key, []byte(key), ttl); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if value, ok, err := store.Get("a/1"); err != nil || !ok || string(value) != "a/1" {
+		t.Errorf("Get() got = %s, %v, %v, want a/1, true, nil", value, ok, err)
+	}
+
+	scan := func() []string {
+		var keys []string
+		if err := store.Scan("a/", func(key string, _ []byte) bool {
+			keys = append(keys, key)
+			return true
+		}); err != nil {
+			t.Fatal(err)
+		}
+		return keys
+	}
+	if got, want := scan(), []string{"a/1", "a/2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Scan() got = %v, want %v", got, want)
+	}
+
+	clock.Advance(time.Hour)
+	if _, ok, _ := store.Get("a/1"); ok {
+		t.Error("Get() got = true after the ttl elapsed, want false")
+	}
+	if got, want := scan(), []string{"a/2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Scan() got = %v, want %v", got, want)
+	}
+
+	if err := store.Delete("a/2"); err != nil {
+		t.Fatal(err)
+	}
+	if got := scan(); len(got) != 0 {
+		t.Errorf("Scan() got = %v, want none", got)
+	}
+}
+
+func TestSharedStore(t *testing.T) {
+	store := bemailparts.NewMemoryStore()
+	list := bemailparts.NewSuppressionListWithStore(store)
+	if err := list.Add("john@example.com", bemailparts.SuppressionComplaint); err != nil {
+		t.Fatal(err)
+	}
+	q := bemailparts.NewQuarantineWithStore(time.Hour, store)
+	if err := q.Add("jane@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if list.Len() != 1 || q.Len() != 1 {
+		t.Errorf("Len() got = %v, %v, want 1, 1", list.Len(), q.Len())
+	}
+
+	reopened := bemailparts.NewSuppressionListWithStore(store)
+	if reason, ok := reopened.Reason("John@Example.com"); !ok || reason != bemailparts.SuppressionComplaint {
+		t.Errorf("Reason() got = %v, %v, want %v, true", reason, ok, bemailparts.SuppressionComplaint)
+	}
+	if !bemailparts.NewQuarantineWithStore(time.Hour, store).IsQuarantined("jane@example.com") {
+		t.Error("IsQuarantined() got = false, want true")
+	}
+
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	policy := bemailparts.NewBouncePolicyWithStore(bemailparts.DefaultBounceThresholds, store)
+	if _, err := policy.Record("john@example.com", bemailparts.SoftBounce, at); err != nil {
+		t.Fatal(err)
+	}
+	if list.Len() != 1 || q.Len() != 1 {
+		t.Errorf("Len() after Record() got = %v, %v, want 1, 1", list.Len(), q.Len())
+	}
+	reopenedPolicy := bemailparts.NewBouncePolicyWithStore(bemailparts.DefaultBounceThresholds, store)
+	if got := reopenedPolicy.Decide("John@Example.com", at); got != bemailparts.BounceRetry {
+		t.Errorf("Decide() got = %v, want %v", got, bemailparts.BounceRetry)
+	}
+}
+
+func TestFailingStore(t *testing.T) {
+	list := bemailparts.NewSuppressionListWithStore(failingStore{})
+	if err := list.Add("john@example.com", bemailparts.SuppressionBounce); !errors.Is(err, errStoreUnavailable) {
+		t.Errorf("Add() error = %v, want %v", err, errStoreUnavailable)
+	}
+	if !list.Contains("john@example.com") {
+		t.Error("Contains() got = false, want true when the store fails")
+	}
+
+	q := bemailparts.NewQuarantineWithStore(time.Hour, failingStore{})
+	if err := q.Remove("john@example.com"); !errors.Is(err, errStoreUnavailable) {
+		t.Errorf("Remove() error = %v, want %v", err, errStoreUnavailable)
+	}
+	if !q.IsQuarantined("john@example.com") || len(q.Eligible()) != 0 {
+		t.Error("IsQuarantined(), Eligible() released an address when the store fails")
+	}
+
+	policy := bemailparts.NewBouncePolicyWithStore(bemailparts.DefaultBounceThresholds, failingStore{})
+	if _, err := policy.Record("john@example.com", bemailparts.SoftBounce, time.Now()); !errors.Is(err, errStoreUnavailable) {
+		t.Errorf("Record() error = %v, want %v", err, errStoreUnavailable)
+	}
+	if got := policy.Decide("john@example.com", time.Now()); got != bemailparts.BounceSuppress {
+		t.Errorf("Decide() got = %v, want %v when the store fails", got, bemailparts.BounceSuppress)
+	}
+}
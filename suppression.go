@@ -0,0 +1,132 @@
+package bemailparts
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SuppressionReason records why an address was suppressed.
+type SuppressionReason string
+
+const (
+	SuppressionBounce      SuppressionReason = "bounce"
+	SuppressionComplaint   SuppressionReason = "complaint"
+	SuppressionUnsubscribe SuppressionReason = "unsubscribe"
+	SuppressionManual      SuppressionReason = "manual"
+)
+
+// suppressionKeyPrefix prefixes the Store keys of a SuppressionList.
+const suppressionKeyPrefix = "suppression/"
+
+// SuppressionList is a permanent list of addresses that must not be mailed, each with the reason it was
+// suppressed. Lookups are case-insensitive.
+//
+// A SuppressionList is safe for concurrent use.
+type SuppressionList struct {
+	store Store
+}
+
+// NewSuppressionList creates an empty SuppressionList kept in memory.
+//
+// Example:
+//
+//	list := NewSuppressionList()
+//	if err := list.Add("john.doe@example.com", SuppressionBounce); err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	fmt.Println(list.Contains("John.Doe@example.com")) // Output: true
+func NewSuppressionList() *SuppressionList {
+	return NewSuppressionListWithStore(NewMemoryStore())
+}
+
+// NewSuppressionListWithStore creates a SuppressionList kept in store, holding the addresses already
+// suppressed there. If store fails, Contains and Reason report addresses as suppressed (with an empty
+// reason), so that they are not mailed by mistake.
+//
+// Example:
+//
+//	list := NewSuppressionListWithStore(store)
+//	fmt.Println(list.Len()) // Output: the number of addresses suppressed in store
+func NewSuppressionListWithStore(store Store) *SuppressionList {
+	return &SuppressionList{store: store}
+}
+
+// Add suppresses email for the given reason, replacing any previous reason.
+// Returns an error if email is invalid or the Store fails.
+func (l *SuppressionList) Add(email string, reason SuppressionReason) error {
+	e, err := New(email)
+	if err != nil {
+		return err
+	}
+	return l.store.Put(suppressionKey(e.Email()), []byte(string(reason)+"\n"+e.Email()), 0)
+}
+
+// Remove lifts the suppression of email.
+// Returns an error if the Store fails.
+func (l *SuppressionList) Remove(email string) error {
+	return l.store.Delete(suppressionKey(email))
+}
+
+// Contains reports whether email is suppressed.
+func (l *SuppressionList) Contains(email string) bool {
+	_, ok := l.Reason(email)
+	return ok
+}
+
+// Reason returns why email is suppressed, and false if it is not suppressed.
+func (l *SuppressionList) Reason(email string) (SuppressionReason, bool) {
+	value, ok, err := l.store.Get(suppressionKey(email))
+	if err != nil {
+		return "", true
+	}
+	reason, _ := splitStoreValue(value)
+	return SuppressionReason(reason), ok
+}
+
+// Validate makes SuppressionList a Validator: it rejects suppressed addresses with ErrEmailSuppressed,
+// followed by the reason.
+func (l *SuppressionList) Validate(parts BEmailParts) error {
+	if reason, ok := l.Reason(parts.Email()); ok {
+		return fmt.Errorf("%w: %s", ErrEmailSuppressed, reason)
+	}
+	return nil
+}
+
+// Len returns the number of suppressed addresses.
+func (l *SuppressionList) Len() int {
+	var n int
+	_ = l.store.Scan(suppressionKeyPrefix, func(string, []byte) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// Emails returns the suppressed addresses sorted alphabetically.
+func (l *SuppressionList) Emails() []string {
+	emails := []string{}
+	_ = l.store.Scan(suppressionKeyPrefix, func(_ string, value []byte) bool {
+		_, email := splitStoreValue(value)
+		emails = append(emails, email)
+		return true
+	})
+	sort.Strings(emails)
+	return emails
+}
+
+func suppressionKey(email string) string {
+	return suppressionKeyPrefix + strings.ToLower(email)
+}
+
+// splitStoreValue splits a value stored by a SuppressionList or a Quarantine into its metadata and the
+// address. Addresses never contain a newline.
+func splitStoreValue(value []byte) (string, string) {
+	s := string(value)
+	i := strings.IndexByte(s, '\n')
+	if i < 0 {
+		return "", s
+	}
+	return s[:i], s[i+1:]
+}
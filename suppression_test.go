@@ -0,0 +1,35 @@
+package bemailparts_test
+
+import (
+	"github.com/bearaujus/bemailparts"
+	"reflect"
+	"testing"
+)
+
+func TestSuppressionList(t *testing.T) {
+	list := bemailparts.NewSuppressionList()
+	if err := list.Add("b@example.com", bemailparts.SuppressionBounce); err != nil {
+		t.Fatal(err)
+	}
+	if err := list.Add("A@example.com", bemailparts.SuppressionComplaint); err != nil {
+		t.Fatal(err)
+	}
+	if err := list.Add("!@#!@#", bemailparts.SuppressionManual); err == nil {
+		t.Error("expecting an error on Add() but got nil")
+	}
+
+	if !list.Contains("a@EXAMPLE.com") {
+		t.Error("Contains() got = false, want true")
+	}
+	if reason, ok := list.Reason("b@example.com"); !ok || reason != bemailparts.SuppressionBounce {
+		t.Errorf("Reason() got = %v, %v, want %v, true", reason, ok, bemailparts.SuppressionBounce)
+	}
+	if got, want := list.Emails(), []string{"A@example.com", "b@example.com"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Emails() got = %v, want %v", got, want)
+	}
+
+	list.Remove("b@example.com")
+	if list.Contains("b@example.com") || list.Len() != 1 {
+		t.Error("Remove() did not lift the suppression")
+	}
+}
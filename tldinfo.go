@@ -0,0 +1,155 @@
+package bemailparts
+
+import "strings"
+
+// TLDType classifies a TLD the way the IANA root zone database does.
+type TLDType int
+
+const (
+	// TLDUnknown is the type of a TLD missing from the embedded TLD list, such as a private TLD.
+	TLDUnknown TLDType = iota
+
+	// TLDGeneric is a generic TLD, such as "com", "org" or "shop", including brand TLDs.
+	TLDGeneric
+
+	// TLDCountryCode is a country-code TLD, such as "id", "uk" or "рф".
+	TLDCountryCode
+
+	// TLDSponsored is a generic TLD sponsored by a community, such as "edu", "gov" or "museum".
+	TLDSponsored
+
+	// TLDInfrastructure is the "arpa" TLD of the DNS infrastructure.
+	TLDInfrastructure
+)
+
+// String returns the lowercase name of the type as used by IANA, e.g. "country-code".
+func (t TLDType) String() string {
+	switch t {
+	case TLDGeneric:
+		return "generic"
+	case TLDCountryCode:
+		return "country-code"
+	case TLDSponsored:
+		return "sponsored"
+	case TLDInfrastructure:
+		return "infrastructure"
+	default:
+		return "unknown"
+	}
+}
+
+// TLDInfo describes the TLD of an address, the last label of its domain.
+type TLDInfo struct {
+	// TLD is the TLD in lowercase ACE form, e.g. "com" or "xn--p1ai" for "рф", or an empty string if the
+	// domain is an address literal or has a single label.
+	TLD string
+
+	// Type is the type of the TLD.
+	Type TLDType
+
+	// IDN reports whether the TLD is internationalized, e.g. "рф".
+	IDN bool
+}
+
+// sponsoredTLDs lists the sponsored TLDs of the IANA root zone database.
+var sponsoredTLDs = map[string]bool{
+	"aero": true, "asia": true, "cat": true, "coop": true, "edu": true, "gov": true, "int": true,
+	"jobs": true, "mil": true, "museum": true, "post": true, "tel": true, "travel": true, "xxx": true,
+}
+
+// idnCountryTLDs maps the internationalized country-code TLDs, in ACE form, to the ISO 3166-1 code of their
+// country or territory, "eu" for the European Union.
+var idnCountryTLDs = map[string]string{
+	"xn--4dbrk0ce": "il", "xn--mgbaam7a8h": "ae", "xn--y9a3aq": "am", "xn--54b7fta0cc": "bd",
+	"xn--90ae": "bg", "xn--mgbcpq6gpa1a": "bh", "xn--90ais": "by", "xn--fiqs8s": "cn", "xn--fiqz9s": "cn",
+	"xn--lgbbat1ad8j": "dz", "xn--wgbh1c": "eg", "xn--e1a4c": "eu", "xn--qxa6a": "eu",
+	"xn--node": "ge", "xn--qxam": "gr", "xn--j6w193g": "hk",
+	"xn--2scrj9c": "in", "xn--3hcrj9c": "in", "xn--45br5cyl": "in", "xn--h2breg3eve": "in",
+	"xn--h2brj9c8c": "in", "xn--mgbgu82a": "in", "xn--rvc1e0am3e": "in", "xn--h2brj9c": "in",
+	"xn--mgbbh1a": "in", "xn--mgbbh1a71e": "in", "xn--fpcrj9c3d": "in", "xn--gecrj9c": "in",
+	"xn--s9brj9c": "in", "xn--45brj9c": "in", "xn--xkc2dl3a5ee0h": "in",
+	"xn--mgba3a4f16a": "ir", "xn--mgba3a4fra": "ir", "xn--mgbtx2b": "iq", "xn--mgbayh7gpa": "jo",
+	"xn--3e0b707e": "kr", "xn--80ao21a": "kz", "xn--q7ce6a": "la", "xn--fzc2c9e2c": "lk",
+	"xn--xkc2al3hye2a": "lk", "xn--mgbc0a9azcg": "ma", "xn--d1alf": "mk", "xn--l1acc": "mn",
+	"xn--mix891f": "mo", "xn--mix082f": "mo", "xn--mgbah1a3hjkrd": "mr", "xn--mgbx4cd0ab": "my",
+	"xn--mgb9awbf": "om", "xn--mgbai9azgqp6j": "pk", "xn--mgbai9a5eva00b": "pk", "xn--ygbi2ammx": "ps",
+	"xn--wgbl6a": "qa", "xn--90a3ac": "rs", "xn--p1ai": "ru", "xn--mgberp4a5d4ar": "sa",
+	"xn--mgberp4a5d4a87g": "sa", "xn--mgbqly7c0a67fbc": "sa", "xn--mgbqly7cvafr": "sa",
+	"xn--mgbpl2fh": "sd", "xn--yfro4i67o": "sg", "xn--clchc0ea0b2g2a9gcd": "sg", "xn--ogbpf8fl": "sy",
+	"xn--mgbtf8fl": "sy", "xn--o3cw4h": "th", "xn--pgbs0dh": "tn", "xn--kpry57d": "tw",
+	"xn--kprw13d": "tw", "xn--nnx388a": "tw", "xn--j1amh": "ua", "xn--mgb2ddes": "ye",
+}
+
+// TLDInfoOf returns the TLD of e, the last label of its domain, with its type in the IANA root zone
+// database (generic, country-code, sponsored or infrastructure) and whether it is internationalized.
+//
+// Example:
+//
+//	e, err := New("ivan@пример.рф", WithIDN())
+//	if err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	fmt.Printf("%+v\n", TLDInfoOf(e)) // Output: {TLD:xn--p1ai Type:country-code IDN:true}
+func TLDInfoOf(e BEmailParts) TLDInfo {
+	return tldInfo(e.Domain())
+}
+
+// Country returns the ISO 3166-1 alpha-2 code of the country of the TLD of e if it is a country-code TLD, in
+// uppercase, or an empty string for other TLDs. Internationalized TLDs map to their country, and TLDs not
+// matching an assigned code return the code ISO 3166 reserves for them, e.g. "EU" and "AC".
+//
+// Example:
+//
+//	e, err := New("john@example.co.uk")
+//	if err != nil {
+//	    log.Fatalf("Invalid email: %v", err)
+//	}
+//
+//	fmt.Println(Country(e)) // Output: GB
+func Country(e BEmailParts) string {
+	info := tldInfo(e.Domain())
+	if info.Type != TLDCountryCode {
+		return ""
+	}
+	if country, ok := idnCountryTLDs[info.TLD]; ok {
+		return strings.ToUpper(country)
+	}
+	if info.TLD == "uk" {
+		// The United Kingdom is "GB" in ISO 3166-1; "UK" is only reserved for it.
+		return "GB"
+	}
+	return strings.ToUpper(info.TLD)
+}
+
+// tldInfo describes the TLD of domain, which is valid.
+func tldInfo(domain string) TLDInfo {
+	tld := lastDomainLabel(domain)
+	if tld == "" || isAddressLiteral(domain) {
+		return TLDInfo{}
+	}
+	if !isASCII(tld) {
+		ascii, err := ToASCII(tld)
+		if err != nil {
+			return TLDInfo{TLD: tld, IDN: true}
+		}
+		tld = ascii
+	}
+	tld = strings.ToLower(tld)
+	return TLDInfo{TLD: tld, Type: tldType(tld), IDN: strings.HasPrefix(tld, acePrefix)}
+}
+
+// tldType returns the type of tld, in lowercase ACE form.
+func tldType(tld string) TLDType {
+	switch {
+	case !loadKnownTLDs()[tld]:
+		return TLDUnknown
+	case tld == "arpa":
+		return TLDInfrastructure
+	case sponsoredTLDs[tld]:
+		return TLDSponsored
+	case len(tld) == 2 || idnCountryTLDs[tld] != "":
+		return TLDCountryCode
+	}
+	return TLDGeneric
+}
@@ -0,0 +1,164 @@
+package bemailparts_test
+
+import (
+	"github.com/bearaujus/bemailparts"
+	"testing"
+)
+
+func TestTLDInfo(t *testing.T) {
+	tests := []struct {
+		name  string
+		email string
+		opts  []bemailparts.Option
+		want  bemailparts.TLDInfo
+	}{
+		{
+			name:  "generic",
+			email: "john@example.com",
+			want:  bemailparts.TLDInfo{TLD: "com", Type: bemailparts.TLDGeneric},
+		},
+		{
+			name:  "new generic",
+			email: "john@example.shop",
+			want:  bemailparts.TLDInfo{TLD: "shop", Type: bemailparts.TLDGeneric},
+		},
+		{
+			name:  "country code",
+			email: "john@example.co.uk",
+			want:  bemailparts.TLDInfo{TLD: "uk", Type: bemailparts.TLDCountryCode},
+		},
+		{
+			name:  "uppercase",
+			email: "john@EXAMPLE.CO.ID",
+			want:  bemailparts.TLDInfo{TLD: "id", Type: bemailparts.TLDCountryCode},
+		},
+		{
+			name:  "sponsored",
+			email: "john@example.edu",
+			want:  bemailparts.TLDInfo{TLD: "edu", Type: bemailparts.TLDSponsored},
+		},
+		{
+			name:  "infrastructure",
+			email: "john@in-addr.arpa",
+			want:  bemailparts.TLDInfo{TLD: "arpa", Type: bemailparts.TLDInfrastructure},
+		},
+		{
+			name:  "internationalized country code",
+			email: "ivan@пример.рф",
+			opts:  []bemailparts.Option{bemailparts.WithIDN()},
+			want:  bemailparts.TLDInfo{TLD: "xn--p1ai", Type: bemailparts.TLDCountryCode, IDN: true},
+		},
+		{
+			name:  "internationalized country code in ace form",
+			email: "ivan@example.xn--p1ai",
+			want:  bemailparts.TLDInfo{TLD: "xn--p1ai", Type: bemailparts.TLDCountryCode, IDN: true},
+		},
+		{
+			name:  "internationalized generic",
+			email: "ivan@пример.онлайн",
+			opts:  []bemailparts.Option{bemailparts.WithIDN()},
+			want:  bemailparts.TLDInfo{TLD: "xn--80asehdb", Type: bemailparts.TLDGeneric, IDN: true},
+		},
+		{
+			name:  "unknown",
+			email: "john@hr.example.corp",
+			want:  bemailparts.TLDInfo{TLD: "corp", Type: bemailparts.TLDUnknown},
+		},
+		{
+			name:  "single label domain",
+			email: "john@mailhost",
+			opts:  []bemailparts.Option{bemailparts.WithAllowSingleLabelDomain()},
+			want:  bemailparts.TLDInfo{},
+		},
+		{
+			name:  "address literal",
+			email: "john@[192.0.2.1]",
+			opts:  []bemailparts.Option{bemailparts.WithAllowIPDomain()},
+			want:  bemailparts.TLDInfo{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := bemailparts.New(tt.email, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := bemailparts.TLDInfoOf(e); got != tt.want {
+				t.Errorf("TLDInfoOf() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("test every internationalized tld is classified", func(t *testing.T) {
+		for _, tld := range bemailparts.KnownTLDs() {
+			e, err := bemailparts.New("john@example." + tld)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := bemailparts.TLDInfoOf(e); got.Type == bemailparts.TLDUnknown || got.IDN != (tld[:2] == "xn") {
+				t.Errorf("TLDInfoOf() = %+v for %q", got, tld)
+			}
+		}
+	})
+}
+
+func TestTLDTypeString(t *testing.T) {
+	tests := []struct {
+		typ  bemailparts.TLDType
+		want string
+	}{
+		{typ: bemailparts.TLDUnknown, want: "unknown"},
+		{typ: bemailparts.TLDGeneric, want: "generic"},
+		{typ: bemailparts.TLDCountryCode, want: "country-code"},
+		{typ: bemailparts.TLDSponsored, want: "sponsored"},
+		{typ: bemailparts.TLDInfrastructure, want: "infrastructure"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.typ.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountry(t *testing.T) {
+	tests := []struct {
+		name  string
+		email string
+		opts  []bemailparts.Option
+		want  string
+	}{
+		{name: "country code", email: "john@example.de", want: "DE"},
+		{name: "second level", email: "john@example.co.id", want: "ID"},
+		{name: "united kingdom", email: "john@example.co.uk", want: "GB"},
+		{name: "uppercase", email: "john@EXAMPLE.FR", want: "FR"},
+		{name: "european union", email: "john@example.eu", want: "EU"},
+		{
+			name:  "internationalized",
+			email: "ivan@пример.рф",
+			opts:  []bemailparts.Option{bemailparts.WithIDN()},
+			want:  "RU",
+		},
+		{name: "internationalized in ace form", email: "li@example.xn--fiqs8s", want: "CN"},
+		{name: "generic", email: "john@example.com"},
+		{name: "sponsored", email: "john@example.edu"},
+		{name: "unknown two letters", email: "john@example.zz"},
+		{
+			name:  "address literal",
+			email: "john@[192.0.2.1]",
+			opts:  []bemailparts.Option{bemailparts.WithAllowIPDomain()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := bemailparts.New(tt.email, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := bemailparts.Country(e); got != tt.want {
+				t.Errorf("Country() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package bemailparts
+
+import (
+	_ "embed"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// knownTLDList lists the delegated TLDs in the format of the IANA list
+// (https://data.iana.org/TLD/tlds-alpha-by-domain.txt): one uppercase TLD in ACE form per line, after
+// comment lines starting with '#'. It is extracted from the ICANN section of the embedded Public Suffix List
+// snapshot rather than taken from IANA; see its header.
+//
+//go:embed tlds-alpha-by-domain.txt
+var knownTLDList string
+
+var (
+	knownTLDsOnce sync.Once
+	knownTLDSet   map[string]bool
+)
+
+// loadKnownTLDs parses knownTLDList on first use into a set of lowercase TLDs.
+func loadKnownTLDs() map[string]bool {
+	knownTLDsOnce.Do(func() {
+		knownTLDSet = map[string]bool{}
+		for _, line := range strings.Split(knownTLDList, "\n") {
+			if tld := strings.TrimSpace(line); tld != "" && !strings.HasPrefix(tld, "#") {
+				knownTLDSet[strings.ToLower(tld)] = true
+			}
+		}
+	})
+	return knownTLDSet
+}
+
+// KnownTLDs returns the delegated TLDs WithKnownTLDsOnly accepts, lowercase in ACE form and sorted, e.g.
+// "com", "id" and "xn--p1ai". The result is a copy; modifying it does not affect validation.
+func KnownTLDs() []string {
+	known := loadKnownTLDs()
+	tlds := make([]string, 0, len(known))
+	for tld := range known {
+		tlds = append(tlds, tld)
+	}
+	sort.Strings(tlds)
+	return tlds
+}
+
+// WithKnownTLDsOnly rejects addresses whose TLD, the last label of the domain, is not delegated in the DNS
+// root zone according to the embedded TLD list, with ErrEmailDomainTLDUnknown, e.g. "user@example.notatld".
+// Internationalized TLDs are checked in their ASCII form. Address literals and single-label domains are
+// exempt. Use WithKnownTLDs to accept private TLDs as well, and WithAllowedTLDs to accept a fixed list
+// instead.
+//
+// Example:
+//
+//	_, err := New("john.doe@example.notatld", WithKnownTLDsOnly())
+//	fmt.Println(errors.Is(err, ErrEmailDomainTLDUnknown)) // Output: true
+func WithKnownTLDsOnly() Option {
+	return func(o *options) {
+		o.knownTLDsOnly = true
+	}
+}
+
+// WithKnownTLDs is WithKnownTLDsOnly, also accepting tlds, e.g. the private TLDs of an intranet such as
+// "corp" or "internal". TLDs are compared ignoring case and may be given with a leading dot. Passing the
+// option several times accepts the TLDs of every call.
+//
+// Example:
+//
+//	parser := NewParser(WithKnownTLDs("corp"))
+//	_, err := parser.Parse("john.doe@hr.example.corp")
+//	fmt.Println(err) // Output: <nil>
+func WithKnownTLDs(tlds ...string) Option {
+	return func(o *options) {
+		o.knownTLDsOnly = true
+		o.extraTLDs = addTLDs(o.extraTLDs, tlds)
+	}
+}
+
+// isKnownTLD reports whether tld, in ASCII form, is accepted by WithKnownTLDsOnly, or true if the option is
+// not set.
+func (o *options) isKnownTLD(tld string) bool {
+	if !o.knownTLDsOnly {
+		return true
+	}
+	tld = strings.ToLower(tld)
+	return loadKnownTLDs()[tld] || o.extraTLDs[tld]
+}
@@ -0,0 +1,100 @@
+package bemailparts_test
+
+import (
+	"errors"
+	"github.com/bearaujus/bemailparts"
+	"sort"
+	"testing"
+)
+
+func TestWithKnownTLDsOnly(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		opts    []bemailparts.Option
+		wantErr error
+	}{
+		{name: "known tld", email: "john@example.com"},
+		{name: "known country tld", email: "john@example.co.id"},
+		{name: "case is ignored", email: "john@example.COM"},
+		{name: "unknown tld", email: "john@example.notatld", wantErr: bemailparts.ErrEmailDomainTLDUnknown},
+		{
+			name:  "internationalized tld",
+			email: "ivan@пример.рф",
+			opts:  []bemailparts.Option{bemailparts.WithIDN()},
+		},
+		{name: "ace form of internationalized tld", email: "ivan@example.xn--p1ai"},
+		{
+			name:  "private tld",
+			email: "john@hr.example.corp",
+			opts:  []bemailparts.Option{bemailparts.WithKnownTLDs(".Corp")},
+		},
+		{
+			name:    "other private tld",
+			email:   "john@hr.example.internal",
+			opts:    []bemailparts.Option{bemailparts.WithKnownTLDs("corp")},
+			wantErr: bemailparts.ErrEmailDomainTLDUnknown,
+		},
+		{
+			name:  "private tlds accumulate",
+			email: "john@hr.example.internal",
+			opts:  []bemailparts.Option{bemailparts.WithKnownTLDs("corp"), bemailparts.WithKnownTLDs("internal")},
+		},
+		{
+			name:  "single label domain",
+			email: "john@mailhost",
+			opts:  []bemailparts.Option{bemailparts.WithAllowSingleLabelDomain()},
+		},
+		{
+			name:  "address literal",
+			email: "john@[192.0.2.1]",
+			opts:  []bemailparts.Option{bemailparts.WithAllowIPDomain()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]bemailparts.Option{bemailparts.WithKnownTLDsOnly()}, tt.opts...)
+			_, err := bemailparts.New(tt.email, opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("test error names the tld", func(t *testing.T) {
+		_, err := bemailparts.New("john@example.notatld", bemailparts.WithKnownTLDsOnly())
+		want := "unknown top-level domain at position 13 in domain"
+		if err == nil || err.Error() != want {
+			t.Errorf("New() error = %v, want %v", err, want)
+		}
+		var emailErr *bemailparts.EmailError
+		if !errors.As(err, &emailErr) || emailErr.Field != bemailparts.FieldTLD || emailErr.Value != "notatld" {
+			t.Errorf("New() error = %#v, want an EmailError about the tld", emailErr)
+		}
+	})
+
+	t.Run("test setters reject unknown tlds", func(t *testing.T) {
+		e, err := bemailparts.New("john@example.com", bemailparts.WithKnownTLDsOnly())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = e.SetDomainTLD("notatld"); !errors.Is(err, bemailparts.ErrEmailDomainTLDUnknown) {
+			t.Errorf("SetDomainTLD() error = %v, want %v", err, bemailparts.ErrEmailDomainTLDUnknown)
+		}
+	})
+}
+
+func TestKnownTLDs(t *testing.T) {
+	got := bemailparts.KnownTLDs()
+	if len(got) < 1000 || !sort.StringsAreSorted(got) {
+		t.Fatalf("KnownTLDs() got %d TLDs, sorted %v, want over 1000 sorted", len(got), sort.StringsAreSorted(got))
+	}
+	i := sort.SearchStrings(got, "com")
+	if i == len(got) || got[i] != "com" {
+		t.Error("KnownTLDs() does not contain com")
+	}
+	got[i] = "changed"
+	if again := bemailparts.KnownTLDs(); again[i] != "com" {
+		t.Error("KnownTLDs() result aliases the list")
+	}
+}
@@ -0,0 +1,35 @@
+package bemailparts
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// maskUnicodeLocalPart returns maskUnicode(s) if WithUnicodeLocalPart is set, and s otherwise.
+func (o *options) maskUnicodeLocalPart(s string) string {
+	if !o.unicode {
+		return s
+	}
+	return maskUnicode(s)
+}
+
+// maskUnicode returns s with the octets of every printable non-ASCII character replaced by 'a', so that the
+// ASCII rules of the selected syntax accept those characters where they accept a letter. Byte offsets are
+// kept, and other non-ASCII octets are left for the rules to reject.
+func maskUnicode(s string) string {
+	if isASCII(s) {
+		return s
+	}
+
+	masked := []byte(s)
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r >= utf8.RuneSelf && r != utf8.RuneError && unicode.IsGraphic(r) && !unicode.IsSpace(r) {
+			for j := i; j < i+size; j++ {
+				masked[j] = 'a'
+			}
+		}
+		i += size
+	}
+	return string(masked)
+}
@@ -0,0 +1,66 @@
+package bemailparts_test
+
+import (
+	"errors"
+	"github.com/bearaujus/bemailparts"
+	"strings"
+	"testing"
+)
+
+func TestWithUnicodeLocalPart(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		opts    []bemailparts.Option
+		wantErr error
+	}{
+		{name: "success chinese", email: "用户@example.com"},
+		{name: "success mixed", email: "jürgen.müller@example.com"},
+		{name: "success devanagari with marks", email: "उपयोगकर्ता@example.com"},
+		{name: "success rfc5322", email: "用户.名@example.com", opts: []bemailparts.Option{bemailparts.WithRFC5322()}},
+		{name: "success rfc5322 quoted", email: `"用户 名"@example.com`, opts: []bemailparts.Option{bemailparts.WithRFC5322()}},
+		{name: "success rfc5321", email: "用户@example.com", opts: []bemailparts.Option{bemailparts.WithRFC5321()}},
+		{name: "success html5", email: "用户@example.com", opts: []bemailparts.Option{bemailparts.WithHTML5Validation()}},
+		{name: "success 21 characters in 63 octets", email: strings.Repeat("用", 21) + "@example.com"},
+		{
+			name:    "error 22 characters in 66 octets",
+			email:   strings.Repeat("用", 22) + "@example.com",
+			wantErr: bemailparts.ErrEmailUsernameTooLong,
+		},
+		{name: "error non-breaking space", email: "john\u00a0doe@example.com", wantErr: bemailparts.ErrInvalidEmailFormat},
+		{name: "error invalid utf-8", email: "john\xffdoe@example.com", wantErr: bemailparts.ErrInvalidEmailFormat},
+		{name: "error unicode domain", email: "用户@例子.com", wantErr: bemailparts.ErrInvalidEmailDomainFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]bemailparts.Option{bemailparts.WithUnicodeLocalPart()}, tt.opts...)
+			got, err := bemailparts.New(tt.email, opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr == nil && got.Email() != tt.email {
+				t.Errorf("New() got = %v, want %v", got, tt.email)
+			}
+		})
+	}
+
+	t.Run("test unicode username rejected without option", func(t *testing.T) {
+		if _, err := bemailparts.New("用户@example.com"); err == nil {
+			t.Error("expecting an error on New() but got nil")
+		}
+	})
+
+	t.Run("test set unicode username", func(t *testing.T) {
+		e, err := bemailparts.New("john@example.com", bemailparts.WithUnicodeLocalPart())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = e.SetUsername("用户"); err != nil {
+			t.Fatal(err)
+		}
+		if e.Email() != "用户@example.com" {
+			t.Errorf("Email() got = %v, want 用户@example.com", e.Email())
+		}
+	})
+}
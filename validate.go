@@ -0,0 +1,446 @@
+package bemailparts
+
+import "strings"
+
+const (
+	// maxUsernameLength is the maximum length of a local part in octets (RFC 5321 section 4.5.3.1.1).
+	maxUsernameLength = 64
+
+	// maxDomainLength is the maximum length of a domain in octets (RFC 5321 section 4.5.3.1.2).
+	maxDomainLength = 255
+
+	// maxLabelLength is the maximum length of a DNS label in octets (RFC 1035 section 2.3.4).
+	maxLabelLength = 63
+
+	// maxEmailLength is the maximum length of an address in octets: a forward-path of at most 256 octets
+	// (RFC 5321 section 4.5.3.1.3) minus its angle brackets (RFC 3696 errata 1690).
+	maxEmailLength = 254
+)
+
+// Validate checks email exactly like New, without creating a BEmailParts instance, for callers that only
+// need to know whether an address is valid and why not. Validations are recorded by WithAuditLog like New.
+//
+// Example:
+//
+//	if err := Validate("john.doe@example"); err != nil {
+//	    fmt.Println(err) // Output: missing top-level domain at position 16 in domain
+//	}
+func Validate(email string, opts ...Option) error {
+	return validate(email, newOptions(opts))
+}
+
+// ValidateUsername checks a username, the part of an address before the '@', like SetUsername does.
+//
+// Example:
+//
+//	fmt.Println(ValidateUsername("john doe")) // Output: invalid email username format
+func ValidateUsername(username string, opts ...Option) error {
+	o := newOptions(opts)
+	return newEmailError(FieldUsername, username, o.validateUsername(o.normalize(username)))
+}
+
+// ValidateDomain checks a domain, the part of an address after the '@', like SetDomain does.
+//
+// Example:
+//
+//	fmt.Println(ValidateDomain("-example.com")) // Output: invalid email domain format
+func ValidateDomain(domain string, opts ...Option) error {
+	o := newOptions(opts)
+	return newEmailError(FieldDomain, domain, o.validateDomain(o.normalize(domain)))
+}
+
+// ValidateTLD checks a top-level domain, with or without its leading dot, like SetDomainTLD does.
+//
+// Example:
+//
+//	fmt.Println(ValidateTLD(".co.id")) // Output: <nil>
+func ValidateTLD(domainTLD string, opts ...Option) error {
+	o := newOptions(opts)
+	return newEmailError(FieldTLD, domainTLD, o.validateDomainTLD(o.normalize(domainTLD)))
+}
+
+// IsValid reports whether email is valid under the default rules, like New without options. It neither
+// allocates nor uses regular expressions, which makes it suitable for hot loops over millions of rows.
+//
+// Example:
+//
+//	fmt.Println(IsValid("john.doe@example.com")) // Output: true
+//	fmt.Println(IsValid("john.doe@example"))     // Output: false
+func IsValid(email string) bool {
+	// The default patterns only accept ASCII, so the regular expression fallback would never match.
+	return isASCII(email) && isValid(email, defaultOptions)
+}
+
+// isValid is like validate, without building the *ParseError locating the problem, which allocates.
+func isValid(email string, o *options) bool {
+	err := o.check(email)
+	o.audit(email, err)
+	return err == nil
+}
+
+// check is isValid without the audit record, returning the unlocated error.
+func (o *options) check(email string) error {
+	prepared, _, err := o.prepareEmail(email)
+	if err == nil {
+		_, _, err = o.splitEmail(prepared)
+	}
+	return err
+}
+
+func validate(email string, o *options) error {
+	_, err := splitEmailParts(email, o)
+	o.audit(email, err)
+	return err
+}
+
+// splitEmail validates email and splits it into its username and domain.
+func (o *options) splitEmail(email string) (string, string, error) {
+	if err := o.checkASCIIOnly(email); err != nil {
+		return "", "", err
+	}
+	masked := email
+	if o.unicode || o.idn {
+		masked = maskUnicode(email)
+	}
+	username, _, err := o.splitAddress(masked)
+	if err != nil {
+		return "", "", err
+	}
+	// Masking keeps byte offsets, so the split applies to email as is.
+	username, domain := email[:len(username)], email[len(username)+len(emailSeparator):]
+
+	if err = o.validateUsername(username); err != nil {
+		return "", "", err
+	}
+	if err = o.validateDomain(domain); err != nil {
+		return "", "", err
+	}
+	if err = validateEmailLength(username, domain); err != nil {
+		return "", "", err
+	}
+	return username, domain, nil
+}
+
+// checkASCIIOnly returns the error of WithASCIIOnly for email if its username or domain is not ASCII. The
+// parts are checked before splitting, so that the error explains the conversion needed rather than reporting
+// an invalid format.
+func (o *options) checkASCIIOnly(email string) error {
+	at := strings.LastIndex(email, emailSeparator)
+	if !o.asciiOnly || at < 0 || isASCII(email) {
+		return nil
+	}
+	if !isASCII(email[:at]) {
+		return ErrEmailRequiresSMTPUTF8
+	}
+	if domain := email[at+1:]; !o.isCheckedAddressLiteral(domain) {
+		return ErrEmailRequiresIDNA
+	}
+	return nil
+}
+
+// splitAddress splits email into its username and domain according to the selected syntax, without
+// validating them further.
+func (o *options) splitAddress(email string) (string, string, error) {
+	var username, domain string
+	switch o.syntax {
+	case syntaxRFC5322, syntaxRFC5321:
+		var err error
+		if username, domain, err = splitRFC5322Email(email); err != nil {
+			return "", "", err
+		}
+	default:
+		if i := strings.LastIndex(email, emailSeparator+"["); o.allowIPDomain && i >= 0 {
+			username, domain = email[:i], email[i+1:]
+			break
+		}
+		if i := strings.LastIndex(email, emailSeparator); o.singleLabel && i >= 0 && isSingleLabel(email[i+1:]) {
+			username, domain = email[:i], email[i+1:]
+			break
+		}
+		if !o.emailRegexMatch(email) {
+			return "", "", ErrInvalidEmailFormat
+		}
+		i := strings.LastIndex(email, emailSeparator)
+		username, domain = email[:i], email[i+1:]
+	}
+	return username, domain, nil
+}
+
+// validateEmailLength checks the length of the address made of username and domain, which are valid on
+// their own.
+func validateEmailLength(username, domain string) error {
+	if len(username)+len(emailSeparator)+len(domain) > maxEmailLength {
+		return ErrEmailTooLong
+	}
+	return nil
+}
+
+func (o *options) emailRegexMatch(email string) bool {
+	if o.syntax == syntaxHTML5 {
+		return html5EmailRegex.MatchString(email)
+	}
+	if isASCII(email) {
+		return matchEmailASCII(email)
+	}
+	return emailRegex.MatchString(email)
+}
+
+func (o *options) validateUsername(username string) error {
+	if err := o.validateUsernameSyntax(username); err != nil {
+		return err
+	}
+	return runValidators(o.usernameValidators, PartLocal, username)
+}
+
+// validateUsernameSyntax runs the built-in checks of validateUsername.
+func (o *options) validateUsernameSyntax(username string) error {
+	if o.asciiOnly && !isASCII(username) {
+		return ErrEmailRequiresSMTPUTF8
+	}
+	masked := o.maskUnicodeLocalPart(username)
+	var valid bool
+	switch o.syntax {
+	case syntaxRFC5322:
+		valid = isRFC5322LocalPart(masked)
+	case syntaxRFC5321:
+		valid = isRFC5321LocalPart(masked)
+	case syntaxHTML5:
+		valid = html5UsernameRegex.MatchString(masked)
+	default:
+		valid = matchDefault(masked, matchUsernameASCII, usernameRegex.MatchString)
+	}
+	if !valid || (o.strictDotAtom() && !isDotAtomUsername(masked)) {
+		return ErrInvalidEmailUsernameFormat
+	}
+	if len(username) > maxUsernameLength {
+		return ErrEmailUsernameTooLong
+	}
+	return nil
+}
+
+func (o *options) validateDomain(domain string) error {
+	if err := o.validateDomainSyntax(domain); err != nil {
+		return err
+	}
+	return runValidators(o.domainValidators, PartDomain, domain)
+}
+
+// validateDomainSyntax runs the built-in checks of validateDomain.
+func (o *options) validateDomainSyntax(domain string) error {
+	if o.isCheckedAddressLiteral(domain) {
+		return validateAddressLiteral(domain)
+	}
+	if o.asciiOnly && !isASCII(domain) {
+		return ErrEmailRequiresIDNA
+	}
+	domain, err := o.toASCIIDomain(domain)
+	if err != nil {
+		return err
+	}
+
+	var valid bool
+	switch o.syntax {
+	case syntaxRFC5322:
+		valid = isRFC5322Domain(domain)
+	case syntaxRFC5321:
+		valid = isRFC5321Domain(domain)
+	case syntaxHTML5:
+		valid = html5DomainRegex.MatchString(domain)
+	default:
+		if o.singleLabel && isSingleLabel(domain) {
+			valid = isAlphaNumericOrHyphen(domain) && isDNSLabel(domain)
+			break
+		}
+		valid = matchDefault(domain, matchDomainASCII, domainRegex.MatchString) && isDNSName(domain)
+	}
+	if !valid {
+		return ErrInvalidEmailDomainFormat
+	}
+	if len(domain) > maxDomainLength {
+		return ErrEmailDomainTooLong
+	}
+	if _, ok := reservedDomainUse(domain); o.rejectReserved && ok {
+		return ErrEmailDomainReserved
+	}
+	if isAddressLiteral(domain) || (o.singleLabel && isSingleLabel(domain)) {
+		return nil
+	}
+	if minTLDLength := o.effectiveMinTLDLength(); minTLDLength > 0 && len(lastDomainLabel(domain)) < minTLDLength {
+		return ErrEmailDomainTLDTooShort
+	}
+	if len(lastDomainLabel(domain)) > o.effectiveMaxTLDLength() {
+		return ErrEmailDomainTLDTooLong
+	}
+	if o.strict && isNumeric(lastDomainLabel(domain)) {
+		return ErrEmailDomainTLDNumeric
+	}
+	if !o.isAllowedTLD(lastDomainLabel(domain)) {
+		return ErrEmailDomainTLDNotAllowed
+	}
+	if !o.isKnownTLD(lastDomainLabel(domain)) {
+		return ErrEmailDomainTLDUnknown
+	}
+	if o.requireRegistrable && registrableDomain(domain) == "" {
+		return ErrNoRegistrableDomain
+	}
+	if o.rejectDisposable && isDisposable(domain) {
+		return ErrEmailDomainDisposable
+	}
+	if o.rejectFreeProviders && o.isFreeProvider(domain) {
+		return ErrEmailDomainFreeProvider
+	}
+	return nil
+}
+
+func (o *options) validateDomainName(domainName string) error {
+	if o.asciiOnly && !isASCII(domainName) {
+		return ErrEmailRequiresIDNA
+	}
+	domainName, err := o.toASCIIDomain(domainName)
+	if err != nil {
+		return err
+	}
+
+	var valid bool
+	switch o.syntax {
+	case syntaxRFC5322:
+		valid = isRFC5322DotAtom(domainName)
+	case syntaxRFC5321:
+		valid = isRFC5321DomainName(domainName)
+	case syntaxHTML5:
+		valid = html5DomainRegex.MatchString(domainName)
+	default:
+		valid = matchDefault(domainName, matchDomainNameASCII, domainNameRegex.MatchString) && isDNSName(domainName)
+	}
+	if !valid {
+		return ErrInvalidEmailDomainNameFormat
+	}
+	return nil
+}
+
+func (o *options) validateDomainTLD(domainTLD string) error {
+	if o.asciiOnly && !isASCII(domainTLD) {
+		return ErrEmailRequiresIDNA
+	}
+	if o.idn {
+		// Every syntax accepts the TLD without its leading dot.
+		ascii, err := o.toASCIIDomain(strings.TrimPrefix(domainTLD, domainSeparator))
+		if err != nil {
+			return err
+		}
+		domainTLD = ascii
+	}
+
+	var valid bool
+	switch o.syntax {
+	case syntaxRFC5322:
+		valid = isRFC5322DotAtom(strings.TrimPrefix(domainTLD, domainSeparator))
+	case syntaxRFC5321:
+		valid = isRFC5321DomainName(strings.TrimPrefix(domainTLD, domainSeparator))
+	case syntaxHTML5:
+		valid = html5DomainRegex.MatchString(strings.TrimPrefix(domainTLD, domainSeparator))
+	default:
+		valid = matchDefault(domainTLD, matchDomainTLDASCII, domainTLDRegex.MatchString) &&
+			isDNSName(strings.TrimPrefix(domainTLD, domainSeparator))
+	}
+	if !valid {
+		return ErrInvalidEmailDomainTLDFormat
+	}
+	if o.strict && isNumeric(domainTLD[strings.LastIndex(domainTLD, domainSeparator)+1:]) {
+		return ErrEmailDomainTLDNumeric
+	}
+	return nil
+}
+
+// isDNSName reports whether every dot-separated label of s is non-empty, at most 63 octets long, and does
+// not start or end with a hyphen, so that s can be resolved. The default patterns only check characters.
+func isDNSName(s string) bool {
+	for {
+		i := strings.IndexByte(s, domainSeparator[0])
+		if i < 0 {
+			return isDNSLabel(s)
+		}
+		if !isDNSLabel(s[:i]) {
+			return false
+		}
+		s = s[i+1:]
+	}
+}
+
+// isSingleLabel reports whether domain has no dot. It may still be malformed.
+func isSingleLabel(domain string) bool {
+	return !strings.Contains(domain, domainSeparator)
+}
+
+func isDNSLabel(label string) bool {
+	return label != "" && len(label) <= maxLabelLength && label[0] != '-' && label[len(label)-1] != '-'
+}
+
+// matchDefault matches s against a default pattern, taking the ASCII fast path when possible and falling
+// back to the general regular expression otherwise.
+func matchDefault(s string, matchASCII func(string) bool, match func(string) bool) bool {
+	if isASCII(s) {
+		return matchASCII(s)
+	}
+	return match(s)
+}
+
+func (o *options) normalize(s string) string {
+	if o.lowercase {
+		return strings.ToLower(s)
+	}
+	return s
+}
+
+// strictDotAtom reports whether usernames must follow the dot-atom rules on top of the selected syntax.
+// The RFC syntaxes already enforce them.
+func (o *options) strictDotAtom() bool {
+	return o.strict && (o.syntax == syntaxDefault || o.syntax == syntaxHTML5)
+}
+
+// isDotAtomUsername reports whether username neither starts nor ends with a dot and has no consecutive dots.
+func isDotAtomUsername(username string) bool {
+	return !strings.HasPrefix(username, domainSeparator) && !strings.HasSuffix(username, domainSeparator) &&
+		!strings.Contains(username, domainSeparator+domainSeparator)
+}
+
+func (o *options) effectiveMinTLDLength() int {
+	if o.strict && o.minTLDLength < StrictMinTLDLength {
+		return StrictMinTLDLength
+	}
+	return o.minTLDLength
+}
+
+// isAllowedTLD reports whether tld, in ASCII form, is allowed by WithAllowedTLDs.
+func (o *options) isAllowedTLD(tld string) bool {
+	return o.allowedTLDs == nil || o.allowedTLDs[strings.ToLower(tld)]
+}
+
+func (o *options) effectiveMaxTLDLength() int {
+	if o.maxTLDLength < 1 || o.maxTLDLength > DefaultMaxTLDLength {
+		return DefaultMaxTLDLength
+	}
+	return o.maxTLDLength
+}
+
+// isNumeric reports whether s is made of ASCII digits only. It is false for an empty string.
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// lastDomainLabel returns the label after the last dot of domain, or an empty string if it has no dot.
+func lastDomainLabel(domain string) string {
+	i := strings.LastIndex(domain, domainSeparator)
+	if i < 0 {
+		return ""
+	}
+	return domain[i+1:]
+}
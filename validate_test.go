@@ -0,0 +1,103 @@
+package bemailparts_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/bearaujus/bemailparts"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		opts    []bemailparts.Option
+		wantErr error
+	}{
+		{name: "success", email: "john.doe@example.com"},
+		{name: "success with options", email: `"john doe"@example.com`, opts: []bemailparts.Option{bemailparts.WithRFC5322()}},
+		{name: "error format", email: "john.doe@example", wantErr: bemailparts.ErrInvalidEmailFormat},
+		{name: "error tld too short", email: "john@example.c", opts: []bemailparts.Option{bemailparts.WithStrict()}, wantErr: bemailparts.ErrEmailDomainTLDTooShort},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := bemailparts.Validate(tt.email, tt.opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if _, newErr := bemailparts.New(tt.email, tt.opts...); !errors.Is(newErr, tt.wantErr) || fmt.Sprint(newErr) != fmt.Sprint(err) {
+				t.Errorf("Validate() error = %v, New() error = %v", err, newErr)
+			}
+			if parserErr := bemailparts.NewParser(tt.opts...).Validate(tt.email); fmt.Sprint(parserErr) != fmt.Sprint(err) {
+				t.Errorf("Validate() error = %v, Parser.Validate() error = %v", err, parserErr)
+			}
+		})
+	}
+}
+
+func TestValidateParts(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		wantErr error
+	}{
+		{name: "username", err: bemailparts.ValidateUsername("john.doe")},
+		{name: "invalid username", err: bemailparts.ValidateUsername("john doe"), wantErr: bemailparts.ErrInvalidEmailUsernameFormat},
+		{name: "quoted username", err: bemailparts.ValidateUsername(`"john doe"`, bemailparts.WithRFC5322())},
+		{name: "domain", err: bemailparts.ValidateDomain("example.co.id")},
+		{name: "invalid domain", err: bemailparts.ValidateDomain("-example.com"), wantErr: bemailparts.ErrInvalidEmailDomainFormat},
+		{name: "domain tld too short", err: bemailparts.ValidateDomain("example.c", bemailparts.WithStrict()), wantErr: bemailparts.ErrEmailDomainTLDTooShort},
+		{name: "tld", err: bemailparts.ValidateTLD("com")},
+		{name: "tld with dot", err: bemailparts.ValidateTLD(".co.id")},
+		{name: "invalid tld", err: bemailparts.ValidateTLD("c0m"), wantErr: bemailparts.ErrInvalidEmailDomainTLDFormat},
+		{name: "numeric tld", err: bemailparts.ValidateTLD(".co.123", bemailparts.WithRFC5321(), bemailparts.WithStrict()), wantErr: bemailparts.ErrEmailDomainTLDNumeric},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !errors.Is(tt.err, tt.wantErr) {
+				t.Errorf("error = %v, wantErr %v", tt.err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIsValid(t *testing.T) {
+	tests := []struct {
+		email string
+		want  bool
+	}{
+		{email: "john.doe@example.com", want: true},
+		{email: "john.doe@example.co.id", want: true},
+		{email: "john.doe@example", want: false},
+		{email: "john.doe@-example.com", want: false},
+		{email: "jöhn@example.com", want: false},
+		{email: "", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			if got := bemailparts.IsValid(tt.email); got != tt.want {
+				t.Errorf("IsValid() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func BenchmarkIsValid(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if !bemailparts.IsValid("test.username@test-domain.com") {
+			b.Fatal("IsValid() got = false, want true")
+		}
+	}
+}
+
+// BenchmarkRegexMatch measures matching the documented default pattern, the regular expression path
+// IsValid is compared against.
+func BenchmarkRegexMatch(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if !defaultEmailRegex.MatchString("test.username@test-domain.com") {
+			b.Fatal("MatchString() got = false, want true")
+		}
+	}
+}
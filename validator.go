@@ -0,0 +1,154 @@
+package bemailparts
+
+// Validator checks an address, returning an error describing why it is rejected, or nil. Validators are the
+// extension point for checks beyond syntax, such as policy checks; compose them with a ValidatorChain.
+// *SuppressionList is a Validator.
+type Validator interface {
+	Validate(parts BEmailParts) error
+}
+
+// ValidatorFunc adapts a function to Validator.
+type ValidatorFunc func(parts BEmailParts) error
+
+// Validate calls f(parts).
+func (f ValidatorFunc) Validate(parts BEmailParts) error {
+	return f(parts)
+}
+
+// ValidatorChain runs Validators in order. It is itself a Validator, so chains can be nested.
+//
+// A ValidatorChain is immutable and safe for concurrent use if its Validators are.
+type ValidatorChain struct {
+	validators []Validator
+}
+
+// NewValidatorChain creates a ValidatorChain running validators in the given order.
+//
+// Example:
+//
+//	chain := NewValidatorChain(SyntaxValidator(WithStrict()), suppressions, ValidatorFunc(checkDomain))
+//	emailParts, _ := New("john.doe@example.com")
+//	if err := chain.Validate(emailParts); err != nil {
+//	    log.Printf("Rejected: %v", err)
+//	}
+func NewValidatorChain(validators ...Validator) *ValidatorChain {
+	return &ValidatorChain{validators: append([]Validator(nil), validators...)}
+}
+
+// Validate runs the Validators in order and returns the error of the first one rejecting parts, without
+// running the following ones, or nil if none rejects it.
+func (c *ValidatorChain) Validate(parts BEmailParts) error {
+	for _, validator := range c.validators {
+		if err := validator.Validate(parts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateAll runs every Validator and returns the errors of those rejecting parts, in order, e.g. to show
+// every problem at once. Returns nil if none rejects it.
+func (c *ValidatorChain) ValidateAll(parts BEmailParts) []error {
+	var errs []error
+	for _, validator := range c.validators {
+		if err := validator.Validate(parts); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// SyntaxValidator returns a Validator checking the address against the syntax selected by opts, e.g. to
+// apply stricter rules than the ones an address was parsed with. Its errors are those of Validate.
+//
+// Example:
+//
+//	emailParts, _ := New("john.doe@example.c")
+//	err := SyntaxValidator(WithStrict()).Validate(emailParts)
+//	fmt.Println(errors.Is(err, ErrEmailDomainTLDTooShort)) // Output: true
+func SyntaxValidator(opts ...Option) Validator {
+	o := newOptions(opts)
+	return ValidatorFunc(func(parts BEmailParts) error {
+		return validate(parts.Email(), o)
+	})
+}
+
+// PartValidator checks a part of an address beyond the built-in syntax checks, returning an error describing
+// why the part is rejected, or nil.
+type PartValidator func(part string) error
+
+// WithUsernameValidator runs validator on every username that passes the built-in checks, e.g. to enforce
+// company rules such as "first.last" usernames without "+" tags. Usernames are passed as validated, i.e.
+// lowercased with WithLowercase. Validators added by several options run in order until one fails.
+//
+// The error returned by validator is wrapped, so both errors.Is(err, ErrRejectedByValidator) and
+// errors.Is on the error of validator work, and constructors report it as a *ParseError in PartLocal.
+//
+// Example:
+//
+//	noTags := func(username string) error {
+//	    if strings.Contains(username, "+") {
+//	        return errors.New("plus tags are not allowed")
+//	    }
+//	    return nil
+//	}
+//
+//	_, err := New("john+news@example.com", WithUsernameValidator(noTags))
+//	fmt.Println(err)                                    // Output: plus tags are not allowed at position 0 in local part
+//	fmt.Println(errors.Is(err, ErrRejectedByValidator)) // Output: true
+func WithUsernameValidator(validator PartValidator) Option {
+	return func(o *options) {
+		o.usernameValidators = append(o.usernameValidators, validator)
+	}
+}
+
+// WithDomainValidator is like WithUsernameValidator for domains. It also runs when the domain is changed by
+// SetDomainName or SetDomainTLD, on the resulting domain. Internationalized domains are passed in the form
+// they were given in, and constructors report rejections as a *ParseError in PartDomain.
+//
+// Example:
+//
+//	corporate := func(domain string) error {
+//	    if domain != "example.com" {
+//	        return errors.New("only example.com addresses are allowed")
+//	    }
+//	    return nil
+//	}
+//
+//	_, err := New("john@gmail.com", WithDomainValidator(corporate))
+//	fmt.Println(errors.Is(err, ErrRejectedByValidator)) // Output: true
+func WithDomainValidator(validator PartValidator) Option {
+	return func(o *options) {
+		o.domainValidators = append(o.domainValidators, validator)
+	}
+}
+
+// validatorError is an error returned by a PartValidator for part of an address.
+type validatorError struct {
+	part string
+	err  error
+}
+
+func (e *validatorError) Error() string {
+	return e.err.Error()
+}
+
+func (e *validatorError) Unwrap() error {
+	return e.err
+}
+
+// Is makes errors.Is report validatorErrors as ErrRejectedByValidator, in addition to the error returned
+// by the PartValidator.
+func (e *validatorError) Is(target error) bool {
+	return target == ErrRejectedByValidator
+}
+
+// runValidators runs validators on value, a part of an address, until one fails.
+func runValidators(validators []PartValidator, part, value string) error {
+	for _, validator := range validators {
+		if err := validator(value); err != nil {
+			return &validatorError{part: part, err: err}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package bemailparts_test
+
+import (
+	"errors"
+	"github.com/bearaujus/bemailparts"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var (
+	errPlusTag    = errors.New("plus tags are not allowed")
+	errNotCompany = errors.New("only example.com addresses are allowed")
+)
+
+func noPlusTags(username string) error {
+	if strings.Contains(username, "+") {
+		return errPlusTag
+	}
+	return nil
+}
+
+func companyDomain(domain string) error {
+	if domain != "example.com" {
+		return errNotCompany
+	}
+	return nil
+}
+
+func TestValidators(t *testing.T) {
+	opts := []bemailparts.Option{
+		bemailparts.WithUsernameValidator(noPlusTags),
+		bemailparts.WithDomainValidator(companyDomain),
+	}
+	tests := []struct {
+		name     string
+		email    string
+		wantErr  error
+		wantPart string
+		wantMsg  string
+	}{
+		{name: "success", email: "john.doe@example.com"},
+		{
+			name:     "username rejected",
+			email:    "john+news@example.com",
+			wantErr:  errPlusTag,
+			wantPart: bemailparts.PartLocal,
+			wantMsg:  "plus tags are not allowed at position 0 in local part",
+		},
+		{
+			name:     "domain rejected",
+			email:    "john@gmail.com",
+			wantErr:  errNotCompany,
+			wantPart: bemailparts.PartDomain,
+			wantMsg:  "only example.com addresses are allowed at position 5 in domain",
+		},
+		{
+			name:     "built-in checks run first",
+			email:    "john+news@exa_mple.com",
+			wantErr:  bemailparts.ErrInvalidEmailFormat,
+			wantPart: bemailparts.PartDomain,
+			wantMsg:  "invalid character '_' at position 13 in domain",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := bemailparts.New(tt.email, opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				return
+			}
+			var parseErr *bemailparts.ParseError
+			if !errors.As(err, &parseErr) || parseErr.Part != tt.wantPart || err.Error() != tt.wantMsg {
+				t.Errorf("New() error = %v, want %v in %q", err, tt.wantMsg, tt.wantPart)
+			}
+			if rejected := errors.Is(err, bemailparts.ErrRejectedByValidator); rejected != (tt.wantErr != bemailparts.ErrInvalidEmailFormat) {
+				t.Errorf("errors.Is(ErrRejectedByValidator) = %v", rejected)
+			}
+			if valid := bemailparts.Inspect(tt.email, opts...).Valid(); valid {
+				t.Errorf("Inspect().Valid() = %v, want false", valid)
+			}
+		})
+	}
+
+	t.Run("test setters", func(t *testing.T) {
+		e, err := bemailparts.New("john.doe@example.com", opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = e.SetUsername("john+news"); !errors.Is(err, errPlusTag) {
+			t.Errorf("SetUsername() error = %v, want %v", err, errPlusTag)
+		}
+		if err = e.SetDomainTLD("org"); !errors.Is(err, errNotCompany) {
+			t.Errorf("SetDomainTLD() error = %v, want %v", err, errNotCompany)
+		}
+		if code, _ := bemailparts.ErrorCodeOf(err); code != bemailparts.CodeRejectedByValidator {
+			t.Errorf("ErrorCodeOf() got = %v, want %v", code, bemailparts.CodeRejectedByValidator)
+		}
+		if e.Email() != "john.doe@example.com" {
+			t.Errorf("Email() got = %v, want %v", e.Email(), "john.doe@example.com")
+		}
+	})
+}
+
+func TestValidatorChain(t *testing.T) {
+	suppressions := bemailparts.NewSuppressionList()
+	if err := suppressions.Add("spam@example.c", bemailparts.SuppressionReason("complaint")); err != nil {
+		t.Fatal(err)
+	}
+	var calls []string
+	tracked := func(name string, err error) bemailparts.Validator {
+		return bemailparts.ValidatorFunc(func(bemailparts.BEmailParts) error {
+			calls = append(calls, name)
+			return err
+		})
+	}
+	chain := bemailparts.NewValidatorChain(
+		bemailparts.SyntaxValidator(bemailparts.WithStrict()),
+		suppressions,
+		tracked("custom", errNotCompany),
+	)
+
+	tests := []struct {
+		name        string
+		email       string
+		wantErr     error
+		wantAllErrs []error
+	}{
+		{
+			name:        "custom only",
+			email:       "john@example.com",
+			wantErr:     errNotCompany,
+			wantAllErrs: []error{errNotCompany},
+		},
+		{
+			name:        "short-circuit and collect all",
+			email:       "spam@example.c",
+			wantErr:     bemailparts.ErrEmailDomainTLDTooShort,
+			wantAllErrs: []error{bemailparts.ErrEmailDomainTLDTooShort, bemailparts.ErrEmailSuppressed, errNotCompany},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := bemailparts.New(tt.email)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = chain.Validate(e); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+			errs := chain.ValidateAll(e)
+			if len(errs) != len(tt.wantAllErrs) {
+				t.Fatalf("ValidateAll() got = %v, want %v", errs, tt.wantAllErrs)
+			}
+			for i := range errs {
+				if !errors.Is(errs[i], tt.wantAllErrs[i]) {
+					t.Errorf("ValidateAll()[%d] got = %v, want %v", i, errs[i], tt.wantAllErrs[i])
+				}
+			}
+		})
+	}
+
+	t.Run("test short-circuit skips later validators", func(t *testing.T) {
+		calls = nil
+		e, err := bemailparts.New("john@example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		nested := bemailparts.NewValidatorChain(tracked("first", errPlusTag), tracked("second", nil))
+		if err = bemailparts.NewValidatorChain(nested, tracked("third", nil)).Validate(e); !errors.Is(err, errPlusTag) {
+			t.Errorf("Validate() error = %v, want %v", err, errPlusTag)
+		}
+		if !reflect.DeepEqual(calls, []string{"first"}) {
+			t.Errorf("calls got = %v, want [first]", calls)
+		}
+	})
+}